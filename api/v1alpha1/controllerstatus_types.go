@@ -0,0 +1,96 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ControllerStatusSpec is empty, the ControllerStatus is created and updated by the controller
+type ControllerStatusSpec struct {
+}
+
+// ProviderHealth is the result of the connectivity checks of a provider account
+type ProviderHealth struct {
+	// ID of the provider account
+	ID string `json:"id"`
+
+	// Type of the provider
+	Type string `json:"type"`
+
+	// Whether the provider is set up and its last connectivity check succeeded
+	Ready bool `json:"ready"`
+
+	// Error of the provider when it is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Last time the provider became ready or not ready
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// ControllerStatusStatus defines the observed state of the controller
+type ControllerStatusStatus struct {
+	// Connectivity of the providers in the controller config
+	// +listType=map
+	// +listMapKey=id
+	// +optional
+	Providers []ProviderHealth `json:"providers,omitempty"`
+
+	// Conditions represent the latest observations of the controller
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ConditionTypeProvidersReady is True when every provider in the controller config is reachable
+	ConditionTypeProvidersReady = "ProvidersReady"
+
+	// ReasonProvidersReachable is set when every provider is reachable
+	ReasonProvidersReachable = "ProvidersReachable"
+	// ReasonProviderUnreachable is set when at least one provider is not reachable
+	ReasonProviderUnreachable = "ProviderUnreachable"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Providers Ready",type=string,JSONPath=`.status.conditions[?(@.type=="ProvidersReady")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ControllerStatus reports the state of a controller instance, it is kept in the namespace of the controller
+type ControllerStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ControllerStatusSpec   `json:"spec,omitempty"`
+	Status ControllerStatusStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ControllerStatusList contains a list of ControllerStatus
+type ControllerStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ControllerStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ControllerStatus{}, &ControllerStatusList{})
+}
@@ -17,14 +17,62 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DeletionPolicy decides what happens to the monitor of a deleted EndpointMonitor
+// +kubebuilder:validation:Enum=Delete;Retain;Pause
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes the monitor from the provider
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the monitor and its history in the provider
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyPause keeps the monitor but disables its checks in the provider
+	DeletionPolicyPause DeletionPolicy = "Pause"
+)
+
+// CheckType is the kind of check a monitor runs against its target
+// +kubebuilder:validation:Enum=HTTP;TCP;ICMP;DNS;GRPC;Heartbeat
+type CheckType string
+
+const (
+	// CheckTypeHTTP requests the URL
+	CheckTypeHTTP CheckType = "HTTP"
+	// CheckTypeTCP connects to a `tcp://host:port` target
+	CheckTypeTCP CheckType = "TCP"
+	// CheckTypeICMP pings an `icmp://host` target
+	CheckTypeICMP CheckType = "ICMP"
+	// CheckTypeDNS resolves a `dns://host` target
+	CheckTypeDNS CheckType = "DNS"
+	// CheckTypeGRPC calls the `grpc.health.v1.Health` service of a `grpc://host:port` target
+	CheckTypeGRPC CheckType = "GRPC"
+	// CheckTypeHeartbeat waits for the jobs of a CronJob to ping the monitor
+	CheckTypeHeartbeat CheckType = "Heartbeat"
 )
 
 // EndpointMonitorSpec defines the desired state of EndpointMonitor
+// +kubebuilder:validation:XValidation:rule="!has(self.url) || size(self.url) == 0 || (has(self.checkType) && self.checkType != 'HTTP' ? self.url.startsWith(self.checkType.lowerAscii() + '://') : !self.url.matches('^(tcp|icmp|dns|grpc)://'))",message="the scheme of url must match checkType, tcp://, icmp://, dns:// and grpc:// targets need the TCP, ICMP, DNS or GRPC checkType"
+// +kubebuilder:validation:XValidation:rule="!has(self.checkType) || !(self.checkType in ['TCP', 'GRPC']) || !has(self.url) || size(self.url) == 0 || self.url.matches('^[a-z]+://[^/:]+:[0-9]+$')",message="url must be a tcp://host:port or grpc://host:port target for TCP and GRPC checks"
+// +kubebuilder:validation:XValidation:rule="!has(self.checkType) || self.checkType in ['HTTP', 'GRPC', 'Heartbeat'] || !has(self.urlFrom) || has(self.urlFrom.serviceRef)",message="only urlFrom.serviceRef can be used for TCP, ICMP and DNS checks"
+// +kubebuilder:validation:XValidation:rule="(has(self.checkType) && self.checkType == 'Heartbeat') == (has(self.urlFrom) && has(self.urlFrom.cronJobRef))",message="urlFrom.cronJobRef is required for and only used by the Heartbeat checkType"
+// +kubebuilder:validation:XValidation:rule="!has(self.checkType) || self.checkType != 'Heartbeat' || !has(self.url) || size(self.url) == 0",message="url can't be set for the Heartbeat checkType"
+// +kubebuilder:validation:XValidation:rule="!has(self.grpc) || (has(self.checkType) && self.checkType == 'GRPC')",message="grpc can only be set for the GRPC checkType"
 type EndpointMonitorSpec struct {
-	// URL to monitor
+	// URL to monitor, or the `tcp://host:port`, `icmp://host`, `dns://host` or `grpc://host:port` target of
+	// TCP, ICMP, DNS and GRPC checks
 	URL string `json:"url,omitempty"`
 
+	// Type of check run against the url. TCP, ICMP, DNS, GRPC and Heartbeat checks are only supported by some
+	// providers. Heartbeat monitors have no url, they are pinged by the jobs of urlFrom.cronJobRef instead.
+	// +kubebuilder:default=HTTP
+	// +optional
+	CheckType CheckType `json:"checkType,omitempty"`
+
 	// Force monitor endpoint to use HTTPS
 	// +optional
 	ForceHTTPS bool `json:"forceHttps,omitempty"`
@@ -32,14 +80,69 @@ type EndpointMonitorSpec struct {
 	// +optional
 	HealthEndpoint string `json:"healthEndpoint,omitempty"`
 
+	// Name of the monitor in the provider, used instead of the monitorNameTemplate of the controller.
+	// It must be unique for each provider across all namespaces.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	MonitorName string `json:"monitorName,omitempty"`
+
+	// Human friendly name shown in the provider instead of the monitor name, e.g. "Checkout API (EU)" on
+	// public status pages. It is presentation only, name conflicts are detected on the monitor name.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
 	// Comma separated list of providers
 	// +optional
 	Providers string `json:"providers"`
 
-	// URL to monitor from either an ingress or route reference
+	// ID of the provider account from the controller config to create the monitor in. Defaults to
+	// the default account of the provider type selected by the provider config set on the spec.
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// MonitorProvider or ClusterMonitorProvider to create the monitor in, instead of a provider
+	// of the controller config. Can't be combined with providerID.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
+
+	// What happens to the monitor in the provider when the EndpointMonitor is deleted. Defaults to
+	// Delete when enableMonitorDeletion is set in the controller config and to Retain otherwise.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Adopt an existing monitor of the provider instead of creating one
+	// +optional
+	Adopt *MonitorAdoption `json:"adopt,omitempty"`
+
+	// URL to monitor from an ingress, route or service reference, or the CronJob pinging a heartbeat monitor
 	// +optional
 	URLFrom *URLSource `json:"urlFrom,omitempty"`
 
+	// Provider agnostic check translated into the config of the provider. The provider config set
+	// on the spec takes precedence over it.
+	// +optional
+	Check *Check `json:"check,omitempty"`
+
+	// Options of the health checks of the GRPC checkType
+	// +optional
+	GRPC *GRPCCheck `json:"grpc,omitempty"`
+
+	// Certificate whose expiry is alerted on, through a dedicated certificate monitor when the provider
+	// has them or through the certificate settings of the monitor otherwise
+	// +optional
+	TLSCertificate *TLSCertificateSource `json:"tlsCertificate,omitempty"`
+
+	// ConfigMap or Secret the ID and links of the monitor are published in, kept up to date and owned by
+	// the EndpointMonitor
+	// +optional
+	OutputRef *OutputReference `json:"outputRef,omitempty"`
+
+	// Holds the creation of the monitor until the endpoint is live, instead of the fixed creationDelay
+	// of the controller config
+	// +optional
+	ReadinessGate *ReadinessGate `json:"readinessGate,omitempty"`
+
 	// Configuration for UptimeRobot Monitor Provider
 	// +optional
 	UptimeRobotConfig *UptimeRobotConfig `json:"uptimeRobotConfig,omitempty"`
@@ -75,6 +178,124 @@ type EndpointMonitorSpec struct {
 	// Configuration for Grafana Cloud Monitor Provider
 	// +optional
 	GrafanaConfig *GrafanaConfig `json:"grafanaConfig,omitempty"`
+
+	// Configuration for Healthchecks.io Monitor Provider
+	// +optional
+	HealthchecksConfig *HealthchecksConfig `json:"healthchecksConfig,omitempty"`
+
+	// Configuration for the Local Monitor Provider, which probes the endpoint from inside the cluster
+	// +optional
+	LocalConfig *LocalConfig `json:"localConfig,omitempty"`
+
+	// Configuration for the Blackbox Monitor Provider, which probes the endpoint through a Prometheus
+	// blackbox_exporter
+	// +optional
+	BlackboxConfig *BlackboxConfig `json:"blackboxConfig,omitempty"`
+
+	// Configuration for Better Stack Monitor Provider
+	// +optional
+	BetterStackConfig *BetterStackConfig `json:"betterStackConfig,omitempty"`
+
+	// Opaque configuration passed through as-is to an out-of-process provider plugin
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	PluginConfig *runtime.RawExtension `json:"pluginConfig,omitempty"`
+
+	// Opaque configuration sent as the monitor config to the Webhook Monitor Provider
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	WebhookConfig *runtime.RawExtension `json:"webhookConfig,omitempty"`
+}
+
+// Check defines a check independently of the provider. Fields a provider can't express are reported
+// by the CheckSupported condition.
+type Check struct {
+	// Interval between two checks in seconds
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Interval int `json:"interval,omitempty"`
+
+	// Seconds to wait for a response
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int `json:"timeout,omitempty"`
+
+	// HTTP method of the request
+	// +kubebuilder:validation:Enum=GET;HEAD;POST;PUT;PATCH;DELETE;OPTIONS
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Headers sent with the request
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Body sent with the request
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// HTTP status codes the endpoint is considered up with
+	// +optional
+	ExpectedStatusCodes []int `json:"expectedStatusCodes,omitempty"`
+
+	// Assertions on the response body, the endpoint is considered down when one fails
+	// +optional
+	BodyAssertions []BodyAssertion `json:"bodyAssertions,omitempty"`
+
+	// Follow redirects
+	// +optional
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+
+	// Verify the TLS certificate of the endpoint
+	// +optional
+	VerifyTLS *bool `json:"verifyTLS,omitempty"`
+
+	// Locations the check runs from, in the format of the provider
+	// +optional
+	Locations []string `json:"locations,omitempty"`
+}
+
+// BodyAssertionType is the kind of assertion on the response body
+// +kubebuilder:validation:Enum=Contains;NotContains
+type BodyAssertionType string
+
+const (
+	// BodyAssertionContains asserts that the response body contains the value
+	BodyAssertionContains BodyAssertionType = "Contains"
+	// BodyAssertionNotContains asserts that the response body doesn't contain the value
+	BodyAssertionNotContains BodyAssertionType = "NotContains"
+)
+
+// BodyAssertion is an assertion on the response body
+type BodyAssertion struct {
+	Type BodyAssertionType `json:"type"`
+
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+// SetFields returns the JSON names of the fields set on the check
+func (c *Check) SetFields() []string {
+	var fields []string
+	add := func(name string, set bool) {
+		if set {
+			fields = append(fields, name)
+		}
+	}
+	add("interval", c.Interval > 0)
+	add("timeout", c.Timeout > 0)
+	add("method", len(c.Method) > 0)
+	add("headers", len(c.Headers) > 0)
+	add("body", len(c.Body) > 0)
+	add("expectedStatusCodes", len(c.ExpectedStatusCodes) > 0)
+	add("bodyAssertions", len(c.BodyAssertions) > 0)
+	add("followRedirects", c.FollowRedirects != nil)
+	add("verifyTLS", c.VerifyTLS != nil)
+	add("locations", len(c.Locations) > 0)
+	return fields
 }
 
 // UptimeRobotConfig defines the configuration for UptimeRobot Monitor Provider
@@ -106,6 +327,10 @@ type UptimeRobotConfig struct {
 	// +optional
 	KeywordValue string `json:"keywordValue,omitempty"`
 
+	// Read keywordValue from a Secret in the namespace of the EndpointMonitor
+	// +optional
+	KeywordValueFrom *ValueSource `json:"keywordValueFrom,omitempty"`
+
 	// The uptimerobot public status page ID to add this monitor to
 	// +optional
 	StatusPages string `json:"statusPages,omitempty"`
@@ -157,6 +382,196 @@ type UpdownConfig struct {
 	RequestHeaders string `json:"requestHeaders,omitempty"`
 }
 
+// LocalConfig defines the configuration for the Local Monitor Provider, which runs HTTP and TCP checks from
+// inside the controller
+type LocalConfig struct {
+	// Interval between two probes in seconds, defaults to 60
+	// +kubebuilder:validation:Minimum=5
+	// +optional
+	Interval int `json:"interval,omitempty"`
+
+	// Seconds to wait for a response, defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int `json:"timeout,omitempty"`
+
+	// HTTP method of the request, defaults to GET
+	// +kubebuilder:validation:Enum=GET;HEAD;POST;PUT;PATCH;DELETE;OPTIONS
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Headers sent with the request
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Body sent with the request
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// HTTP status codes the endpoint is considered up with, defaults to 2xx and 3xx
+	// +optional
+	ExpectedStatusCodes []int `json:"expectedStatusCodes,omitempty"`
+
+	// Assertions on the response body, the endpoint is considered down when one fails
+	// +optional
+	BodyAssertions []BodyAssertion `json:"bodyAssertions,omitempty"`
+
+	// Follow redirects, defaults to true
+	// +optional
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+
+	// Verify the TLS certificate of the endpoint, defaults to true
+	// +optional
+	VerifyTLS *bool `json:"verifyTLS,omitempty"`
+}
+
+// BlackboxConfig defines the configuration for the Blackbox Monitor Provider, which manages prometheus-operator
+// Probes scraping a blackbox_exporter
+type BlackboxConfig struct {
+	// Module of the blackbox_exporter probing the endpoint, defaults to the module of the provider for HTTP
+	// checks and to tcp_connect, icmp and grpc for TCP, ICMP and GRPC checks
+	// +optional
+	Module string `json:"module,omitempty"`
+
+	// Interval between two probes in seconds, defaults to the scrape interval of Prometheus
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Interval int `json:"interval,omitempty"`
+
+	// Seconds the blackbox_exporter has to probe the endpoint, defaults to the scrape timeout of Prometheus
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ScrapeTimeout int `json:"scrapeTimeout,omitempty"`
+
+	// Labels added to the metrics of the probes
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Alert on the endpoint being down through a PrometheusRule owned by the EndpointMonitor
+	// +optional
+	Alert *BlackboxAlert `json:"alert,omitempty"`
+}
+
+// BlackboxAlert defines the alert of the PrometheusRule created for a Blackbox monitor
+type BlackboxAlert struct {
+	// How long the probes have to fail before the alert fires, defaults to 5m
+	// +optional
+	For *metav1.Duration `json:"for,omitempty"`
+
+	// Severity label of the alert, defaults to critical
+	// +optional
+	Severity string `json:"severity,omitempty"`
+
+	// Labels added to the alert
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Labels of the PrometheusRule, matched by the ruleSelector of Prometheus
+	// +optional
+	RuleLabels map[string]string `json:"ruleLabels,omitempty"`
+}
+
+// BetterStackConfig defines the configuration for Better Stack Monitor Provider
+type BetterStackConfig struct {
+	// Type of the monitor, defaults to status for HTTP checks, keyword when requiredKeyword is set,
+	// expected_status_code when expectedStatusCodes are set, tcp for TCP checks and ping for ICMP checks
+	// +kubebuilder:validation:Enum=status;expected_status_code;keyword;keyword_absence;ping;tcp
+	// +optional
+	MonitorType string `json:"monitorType,omitempty"`
+
+	// Seconds between two checks, defaults to 180
+	// +kubebuilder:validation:Enum=30;45;60;120;180;300;600;900;1800
+	// +optional
+	CheckFrequency int `json:"checkFrequency,omitempty"`
+
+	// Regions the monitor checks from out of us, eu, as and au, defaults to all of them
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+
+	// Headers sent with the request
+	// +optional
+	RequestHeaders map[string]string `json:"requestHeaders,omitempty"`
+
+	// HTTP method of the request, defaults to get
+	// +kubebuilder:validation:Enum=get;head;post;put;patch
+	// +optional
+	HTTPMethod string `json:"httpMethod,omitempty"`
+
+	// Body sent with the request
+	// +optional
+	RequestBody string `json:"requestBody,omitempty"`
+
+	// Seconds to wait for a response
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RequestTimeout int `json:"requestTimeout,omitempty"`
+
+	// Keyword the response of keyword monitors must contain, or must not contain for keyword_absence monitors
+	// +optional
+	RequiredKeyword string `json:"requiredKeyword,omitempty"`
+
+	// HTTP status codes of expected_status_code monitors
+	// +optional
+	ExpectedStatusCodes []int `json:"expectedStatusCodes,omitempty"`
+
+	// Follow redirects
+	// +optional
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+
+	// Verify the TLS certificate of the endpoint
+	// +optional
+	VerifySSL *bool `json:"verifySSL,omitempty"`
+
+	// ID of the escalation policy notified of incidents, instead of the team members selected by the
+	// email, sms, call and push settings
+	// +optional
+	PolicyID string `json:"policyID,omitempty"`
+
+	// Seconds the monitor waits after a failure before starting an incident
+	// +optional
+	ConfirmationPeriod int `json:"confirmationPeriod,omitempty"`
+
+	// Notify the team members by email
+	// +optional
+	Email *bool `json:"email,omitempty"`
+
+	// Notify the team members by SMS
+	// +optional
+	SMS *bool `json:"sms,omitempty"`
+
+	// Call the team members
+	// +optional
+	Call *bool `json:"call,omitempty"`
+
+	// Notify the team members by push notification
+	// +optional
+	Push *bool `json:"push,omitempty"`
+
+	// Pause the monitor
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// HealthchecksConfig defines the configuration for Healthchecks.io Monitor Provider
+type HealthchecksConfig struct {
+	// Comma separated list of the IDs of the integrations notified by the check, "*" for all of them
+	// +optional
+	Channels string `json:"channels,omitempty"`
+
+	// Space separated list of tags
+	// +optional
+	Tags string `json:"tags,omitempty"`
+
+	// Description of the check
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Grace period in seconds, takes precedence over the grace period of the CronJob
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	Grace int `json:"grace,omitempty"`
+}
+
 // StatusCakeConfig defines the configuration for StatusCake Monitor Provider
 //
 // Heartbeat Validation
@@ -167,11 +582,20 @@ type UpdownConfig struct {
 // See https://developers.statuscake.com/api/#tag/uptime/operation/create-uptime-test
 // +kubebuilder:validation:XValidation:rule="self.testType == 'Heartbeat' || self.checkRate in [0, 30, 60, 300, 900, 1800, 3600, 86400]",message="checkRate for uptime monitors must be one of: 0, 30, 60, 300, 900, 1800, 3600, 86400"
 type StatusCakeConfig struct {
-	// Basic Auth User
+	// Basic Auth User. Without basicAuthPasswordFrom, the password is read from the environment
+	// variable of the controller with this name
 	// +optional
 	BasicAuthUser string `json:"basicAuthUser,omitempty"`
 
-	// Basic Auth Secret Name
+	// Read the basic auth password for basicAuthUser from a Secret in the namespace of the EndpointMonitor
+	// +optional
+	BasicAuthPasswordFrom *ValueSource `json:"basicAuthPasswordFrom,omitempty"`
+
+	// Basic auth password resolved from basicAuthPasswordFrom by the controller
+	BasicAuthPassword string `json:"-"`
+
+	// Basic Auth Secret Name in the namespace of the controller, holding the `username` and `password` keys.
+	// Deprecated: use basicAuthUser and basicAuthPasswordFrom instead
 	// +optional
 	BasicAuthSecret string `json:"basicAuthSecret,omitempty"`
 
@@ -239,10 +663,18 @@ type StatusCakeConfig struct {
 	// +optional
 	FindString string `json:"findString,omitempty"`
 
+	// Read findString from a Secret in the namespace of the EndpointMonitor
+	// +optional
+	FindStringFrom *ValueSource `json:"findStringFrom,omitempty"`
+
 	// RawPostData can be used to send parameters within the URL. Changes the request from a GET to a POST
 	// +optional
 	RawPostData string `json:"rawPostData,omitempty"`
 
+	// Read rawPostData from a Secret in the namespace of the EndpointMonitor
+	// +optional
+	RawPostDataFrom *ValueSource `json:"rawPostDataFrom,omitempty"`
+
 	// UserAgent is used to set a user agent string.
 	// +optional
 	UserAgent string `json:"userAgent,omitempty"`
@@ -278,17 +710,35 @@ type PingdomConfig struct {
 
 	// Custom request headers that should be read from an environment variable as it possibly contains sensitive data.
 	// An example would be an API token.
+	// Deprecated: use requestHeadersFrom instead
 	// +optional
 	RequestHeadersEnvVar string `json:"requestHeadersEnvVar,omitempty"`
 
-	// Required for basic-authentication
+	// Read custom request headers as a JSON object from a Secret in the namespace of the EndpointMonitor.
+	// They are merged with requestHeaders.
+	// +optional
+	RequestHeadersFrom *ValueSource `json:"requestHeadersFrom,omitempty"`
+
+	// Required for basic-authentication. Without basicAuthPasswordFrom, the password is read from the
+	// environment variable of the controller with this name
 	// +optional
 	BasicAuthUser string `json:"basicAuthUser,omitempty"`
 
+	// Read the basic auth password for basicAuthUser from a Secret in the namespace of the EndpointMonitor
+	// +optional
+	BasicAuthPasswordFrom *ValueSource `json:"basicAuthPasswordFrom,omitempty"`
+
+	// Basic auth password resolved from basicAuthPasswordFrom by the controller
+	BasicAuthPassword string `json:"-"`
+
 	// Set to text string that has to be present in the HTML code of the page
 	// +optional
 	ShouldContain string `json:"shouldContain,omitempty"`
 
+	// Read shouldContain from a Secret in the namespace of the EndpointMonitor
+	// +optional
+	ShouldContainFrom *ValueSource `json:"shouldContainFrom,omitempty"`
+
 	// Comma separated set of tags to apply to check (e.g. "testing,aws")
 	// +optional
 	Tags string `json:"tags,omitempty"`
@@ -321,8 +771,16 @@ type PingdomConfig struct {
 	// Data that should be posted to the web page, for example submission data for a sign-up or login form.
 	// The data needs to be formatted in the same way as a web browser would send it to the web server.
 	// Because post data contains sensitive secret this field is only a reference to an environment variable.
+	// Deprecated: use postDataFrom instead
 	// +optional
 	PostDataEnvVar string `json:"postDataEnvVar,omitempty"`
+
+	// Read the data posted to the web page from a Secret in the namespace of the EndpointMonitor
+	// +optional
+	PostDataFrom *ValueSource `json:"postDataFrom,omitempty"`
+
+	// Post data resolved from postDataFrom by the controller
+	PostData string `json:"-"`
 }
 
 // PingdomTransactionConfig defines the configuration for Pingdom Transaction Monitor Provider
@@ -381,6 +839,10 @@ type PingdomStep struct {
 	// see available values at https://pkg.go.dev/github.com/karlderkaefer/pingdom-golang-client@latest/pkg/pingdom/client/tmschecks#StepArg
 	// +required
 	Args map[string]string `json:"args"`
+	// contains args whose value is read from a Secret in the namespace of the EndpointMonitor, e.g. a password.
+	// They take precedence over args with the same key.
+	// +optional
+	ArgsFrom map[string]ValueSource `json:"argsFrom,omitempty"`
 	// contains the function that is executed as part of the step
 	// commands: go_to, click, fill, check, uncheck, sleep, select_radio, basic_auth, submit, wait_for_element, wait_for_contains
 	// validations: url, exists, not_exists, contains, not_contains, field_contains, field_not_contains, is_checked, is_not_checked, radio_selected, dropdown_selected, dropdown_not_selected
@@ -430,12 +892,36 @@ type GrafanaConfig struct {
 	AlertSensitivity string `json:"alertSensitivity,omitempty"`
 }
 
+// ValueSource represents a source for the value of a sensitive field
+type ValueSource struct {
+	// Selects a key of a Secret in the namespace of the EndpointMonitor
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef"`
+}
+
 // URLSource represents the set of resources to fetch the URL from
 type URLSource struct {
 	// +optional
 	IngressRef *IngressURLSource `json:"ingressRef,omitempty"`
 	// +optional
 	RouteRef *RouteURLSource `json:"routeRef,omitempty"`
+	// +optional
+	ServiceRef *ServiceURLSource `json:"serviceRef,omitempty"`
+	// +optional
+	CronJobRef *CronJobURLSource `json:"cronJobRef,omitempty"`
+}
+
+// CronJobURLSource selects a CronJob whose jobs ping a heartbeat monitor. The period of the monitor
+// is derived from the schedule of the CronJob and the ping URL is published in a Secret.
+type CronJobURLSource struct {
+	Name string `json:"name"`
+
+	// Secret the ping URL is published in under the key pingURL, defaults to <EndpointMonitor name>-heartbeat
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Time the jobs have to ping the monitor after they are scheduled, defaults to 5m
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
 }
 
 // IngressURLSource selects an Ingress to populate the URL with
@@ -448,14 +934,325 @@ type RouteURLSource struct {
 	Name string `json:"name"`
 }
 
+// AdoptionMatch is the strategy used to find the monitor to adopt
+// +kubebuilder:validation:Enum=Name;URL;ID
+type AdoptionMatch string
+
+const (
+	// AdoptionMatchName matches monitors by their name
+	AdoptionMatchName AdoptionMatch = "Name"
+	// AdoptionMatchURL matches monitors by the URL of the EndpointMonitor
+	AdoptionMatchURL AdoptionMatch = "URL"
+	// AdoptionMatchID matches the monitor with the given ID
+	AdoptionMatchID AdoptionMatch = "ID"
+)
+
+// MonitorAdoption selects the existing monitor an EndpointMonitor takes over. Once adopted, the
+// monitor is updated and renamed like the monitors created by the controller.
+// +kubebuilder:validation:XValidation:rule="self.match != 'ID' || (has(self.id) && size(self.id) > 0)",message="id is required when matching by ID"
+type MonitorAdoption struct {
+	// How the monitor is matched
+	Match AdoptionMatch `json:"match"`
+
+	// Name of the monitor to adopt when matching by name, defaults to the monitor name of the EndpointMonitor
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ID of the monitor to adopt when matching by ID
+	// +optional
+	ID string `json:"id,omitempty"`
+}
+
+// GRPCCheck configures the `grpc.health.v1.Health` checks of the GRPC checkType
+type GRPCCheck struct {
+	// Service whose health is checked, the overall health of the server is checked when empty
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// Connect with TLS, defaults to true when the port of the target is 443
+	// +optional
+	TLS *bool `json:"tls,omitempty"`
+
+	// Skip the verification of the server certificate
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// Metadata sent with the health check request
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// UsesTLS returns whether the check connects with TLS to a target on the port
+func (g *GRPCCheck) UsesTLS(port int) bool {
+	if g == nil || g.TLS == nil {
+		return port == 443
+	}
+	return *g.TLS
+}
+
+// TLSCertificateSource selects the certificate whose expiry is alerted on
+// +kubebuilder:validation:XValidation:rule="has(self.certificateRef) != has(self.ingressRef)",message="exactly one of certificateRef and ingressRef must be set"
+type TLSCertificateSource struct {
+	// cert-manager Certificate in the namespace of the EndpointMonitor
+	// +optional
+	CertificateRef *CertificateReference `json:"certificateRef,omitempty"`
+
+	// Ingress in the namespace of the EndpointMonitor, the certificate is read from the Secret of its first spec.tls entry
+	// +optional
+	IngressRef *IngressURLSource `json:"ingressRef,omitempty"`
+
+	// Days before the expiry of the certificate to alert at. Derived from the renewBefore of the cert-manager
+	// Certificate when unset, leaving two days for failed renewals to be retried.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AlertDaysBefore int `json:"alertDaysBefore,omitempty"`
+}
+
+// ReadinessGate waits for the Ingress, Route or Service of urlFrom to be provisioned and for the url to
+// answer before the monitor is created. The Ingress needs a load-balancer address, its backend Services
+// ready endpoints and its TLS Secrets have to exist.
+type ReadinessGate struct {
+	// Longest time after the creation of the EndpointMonitor to wait for the endpoint, the monitor is
+	// created anyway once it has passed. Defaults to 15m.
+	// +optional
+	MaxWait *metav1.Duration `json:"maxWait,omitempty"`
+
+	// Skips the HTTP probe of the url, only the resources of urlFrom are waited for
+	// +optional
+	SkipProbe bool `json:"skipProbe,omitempty"`
+}
+
+// OutputKind is the kind of object the outputs of a monitor are published in
+// +kubebuilder:validation:Enum=ConfigMap;Secret
+type OutputKind string
+
+const (
+	OutputKindConfigMap OutputKind = "ConfigMap"
+	OutputKindSecret    OutputKind = "Secret"
+)
+
+// OutputReference names the ConfigMap or Secret in the namespace of the EndpointMonitor the outputs of the
+// monitor are published in. It holds the keys provider, monitorID, monitorName, dashboardURL, statusPageURL
+// and pingURL, the keys without a value for the provider are left out.
+type OutputReference struct {
+	// +kubebuilder:default=ConfigMap
+	// +optional
+	Kind OutputKind `json:"kind,omitempty"`
+
+	Name string `json:"name"`
+}
+
+// CertificateReference selects a cert-manager Certificate
+type CertificateReference struct {
+	Name string `json:"name"`
+}
+
+// ServiceURLSource selects a LoadBalancer Service to populate the URL or target with its external address
+type ServiceURLSource struct {
+	Name string `json:"name"`
+
+	// Name or number of the Service port, defaults to the first port
+	// +optional
+	Port *intstr.IntOrString `json:"port,omitempty"`
+}
+
+// ProviderReference selects a MonitorProvider in the namespace of the EndpointMonitor or a ClusterMonitorProvider
+type ProviderReference struct {
+	// +kubebuilder:validation:Enum=MonitorProvider;ClusterMonitorProvider
+	// +kubebuilder:default=MonitorProvider
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	Name string `json:"name"`
+}
+
+// CertificateStatus describes the certificate whose expiry is alerted on
+type CertificateStatus struct {
+	// Secret holding the certificate
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Expiry of the certificate
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// Days before the expiry of the certificate alerts are sent at
+	// +optional
+	AlertDaysBefore int `json:"alertDaysBefore,omitempty"`
+
+	// ID of the dedicated certificate monitor in the provider
+	// +optional
+	MonitorID string `json:"monitorID,omitempty"`
+}
+
+// ProbeStatus is the result of the last probe of the endpoint
+type ProbeStatus struct {
+	// Whether the endpoint was up
+	Success bool `json:"success"`
+
+	// Reason the probe failed
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// HTTP status code of the response
+	// +optional
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Time success last changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Expiry of the certificate of the endpoint that expires first
+	// +optional
+	CertificateNotAfter *metav1.Time `json:"certificateNotAfter,omitempty"`
+}
+
 // EndpointMonitorStatus defines the observed state of EndpointMonitor
 type EndpointMonitorStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ID of the monitor in the provider
+	// +optional
+	MonitorID string `json:"monitorID,omitempty"`
+
+	// Monitor name claimed by the EndpointMonitor, the name of the monitor in the provider unless
+	// spec.displayName is set
+	// +optional
+	MonitorName string `json:"monitorName,omitempty"`
+
+	// Type of the provider the monitor was created in
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// ID of the provider account the monitor was created in
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// Provider config the monitor is created with, after merging the EndpointMonitorTemplates and
+	// the defaults of the provider. Values read from Secrets are left out.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	EffectiveConfig *runtime.RawExtension `json:"effectiveConfig,omitempty"`
+
+	// Names of the EndpointMonitorTemplates merged into the provider config
+	// +optional
+	AppliedTemplates []string `json:"appliedTemplates,omitempty"`
+
+	// Certificate selected by spec.tlsCertificate
+	// +optional
+	Certificate *CertificateStatus `json:"certificate,omitempty"`
+
+	// ConfigMap or Secret the outputs of the monitor were last published in
+	// +optional
+	OutputRef *OutputReference `json:"outputRef,omitempty"`
+
+	// Result of the last probe of the endpoint, for providers that probe it from inside the cluster
+	// +optional
+	Probe *ProbeStatus `json:"probe,omitempty"`
+
+	// Conditions represent the latest observations of the EndpointMonitor
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ConditionTypeNameConflict is True when another EndpointMonitor already owns the monitor name in the provider
+	ConditionTypeNameConflict = "NameConflict"
+
+	// ReasonNameInUse is set when the monitor name is owned by another EndpointMonitor
+	ReasonNameInUse = "NameInUse"
+	// ReasonNameAvailable is set when the monitor name is owned by this EndpointMonitor
+	ReasonNameAvailable = "NameAvailable"
+
+	// ConditionTypeDegraded is True when the provider of the EndpointMonitor failed to set up, its monitor
+	// is left as is until the provider is ready again
+	ConditionTypeDegraded = "Degraded"
+
+	// ReasonProviderNotReady is set when the provider failed to set up
+	ReasonProviderNotReady = "ProviderNotReady"
+	// ReasonProviderReady is set when the provider is set up
+	ReasonProviderReady = "ProviderReady"
+
+	// ConditionTypeCheckSupported is False when the provider can't express some fields of spec.check
+	ConditionTypeCheckSupported = "CheckSupported"
+
+	// ReasonAllFieldsSupported is set when all fields of spec.check are translated into the provider config
+	ReasonAllFieldsSupported = "AllFieldsSupported"
+	// ReasonUnsupportedFields is set when some fields of spec.check are ignored
+	ReasonUnsupportedFields = "UnsupportedFields"
+
+	// ConditionTypeCheckTypeSupported is False when the provider can't run the checkType of the EndpointMonitor,
+	// no monitor is created then
+	ConditionTypeCheckTypeSupported = "CheckTypeSupported"
+
+	// ReasonCheckTypeSupported is set when the provider runs the checkType
+	ReasonCheckTypeSupported = "CheckTypeSupported"
+	// ReasonUnsupportedCheckType is set when the provider can't run the checkType
+	ReasonUnsupportedCheckType = "UnsupportedCheckType"
+
+	// ConditionTypeCertificateMonitored is True when the expiry of the certificate of spec.tlsCertificate is alerted on
+	ConditionTypeCertificateMonitored = "CertificateMonitored"
+
+	// ReasonCertificateMonitored is set when the provider alerts on the expiry of the certificate
+	ReasonCertificateMonitored = "CertificateMonitored"
+	// ReasonCertificateNotFound is set when the certificate can't be read
+	ReasonCertificateNotFound = "CertificateNotFound"
+	// ReasonCertificateAlertsUnsupported is set when the provider can't alert on the expiry of certificates
+	ReasonCertificateAlertsUnsupported = "CertificateAlertsUnsupported"
+
+	// ConditionTypeAdopted is True when the EndpointMonitor is bound to the monitor it adopts
+	ConditionTypeAdopted = "Adopted"
+
+	// ReasonMonitorAdopted is set when the monitor is adopted
+	ReasonMonitorAdopted = "MonitorAdopted"
+	// ReasonNoMatchingMonitor is set when no monitor matches
+	ReasonNoMatchingMonitor = "NoMatchingMonitor"
+	// ReasonAmbiguousMatch is set when several monitors match, none of them is adopted
+	ReasonAmbiguousMatch = "AmbiguousMatch"
+	// ReasonMonitorOwned is set when the matching monitor is owned by another EndpointMonitor
+	ReasonMonitorOwned = "MonitorOwned"
+	// ReasonRenameUnsupported is set when the matching monitor can't be renamed to the monitor name
+	ReasonRenameUnsupported = "RenameUnsupported"
+
+	// ConditionTypeEndpointReady is True once spec.readinessGate let the monitor be created, its message tells
+	// what is waited for while it is False
+	ConditionTypeEndpointReady = "EndpointReady"
+
+	// ReasonEndpointReady is set when the endpoint is live
+	ReasonEndpointReady = "EndpointReady"
+	// ReasonWaitingForEndpoint is set while the creation of the monitor is held
+	ReasonWaitingForEndpoint = "WaitingForEndpoint"
+	// ReasonReadinessTimeout is set when the monitor is created after maxWait without the endpoint being live
+	ReasonReadinessTimeout = "ReadinessTimeout"
+
+	// ConditionTypePingURLPublished is True when the ping URL of a heartbeat monitor is published in its Secret
+	ConditionTypePingURLPublished = "PingURLPublished"
+
+	// ReasonPingURLPublished is set when the ping URL is written into the Secret
+	ReasonPingURLPublished = "PingURLPublished"
+	// ReasonSecretNotOwned is set when the Secret exists and isn't controlled by the EndpointMonitor
+	ReasonSecretNotOwned = "SecretNotOwned"
+
+	// ConditionTypeOutputsPublished is True when the outputs of the monitor are published in the object named by
+	// spec.outputRef
+	ConditionTypeOutputsPublished = "OutputsPublished"
+
+	// ReasonOutputsPublished is set when the outputs are written into the object
+	ReasonOutputsPublished = "OutputsPublished"
+	// ReasonOutputNotOwned is set when the object exists and isn't controlled by the EndpointMonitor
+	ReasonOutputNotOwned = "OutputNotOwned"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Monitor",type=string,JSONPath=`.status.monitorName`
+//+kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.status.provider`
+//+kubebuilder:printcolumn:name="Account",type=string,JSONPath=`.status.providerID`,priority=1
+//+kubebuilder:printcolumn:name="Certificate Expiry",type=date,JSONPath=`.status.certificate.notAfter`,priority=1
+//+kubebuilder:printcolumn:name="Up",type=boolean,JSONPath=`.status.probe.success`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // EndpointMonitor is the Schema for the endpointmonitors API
 type EndpointMonitor struct {
@@ -0,0 +1,62 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EndpointMonitorTemplateSpec defines the provider config shared by the EndpointMonitors of a namespace
+type EndpointMonitorTemplateSpec struct {
+	// Selects the EndpointMonitors in the namespace of the template, all of them when empty
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Provider config merged under the provider config of the selected EndpointMonitors
+	MonitorProviderDefaults `json:",inline"`
+}
+
+// EndpointMonitorTemplateStatus defines the observed state of EndpointMonitorTemplate
+type EndpointMonitorTemplateStatus struct {
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=emt
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// EndpointMonitorTemplate is the Schema for the endpointmonitortemplates API
+type EndpointMonitorTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EndpointMonitorTemplateSpec   `json:"spec,omitempty"`
+	Status EndpointMonitorTemplateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EndpointMonitorTemplateList contains a list of EndpointMonitorTemplate
+type EndpointMonitorTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EndpointMonitorTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EndpointMonitorTemplate{}, &EndpointMonitorTemplateList{})
+}
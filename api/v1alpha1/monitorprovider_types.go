@@ -0,0 +1,169 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// MonitorProviderKind is the kind of namespaced providers referenced by EndpointMonitors
+	MonitorProviderKind = "MonitorProvider"
+	// ClusterMonitorProviderKind is the kind of cluster scoped providers referenced by EndpointMonitors
+	ClusterMonitorProviderKind = "ClusterMonitorProvider"
+)
+
+// MonitorProviderSpec defines a provider account and the defaults of the EndpointMonitors using it
+type MonitorProviderSpec struct {
+	// Type of the provider
+	// +kubebuilder:validation:Enum=UptimeRobot;Pingdom;PingdomTransaction;StatusCake;Uptime;Updown;AppInsights;gcloud;Grafana;Healthchecks;Local;Blackbox;BetterStack;Plugin;Webhook
+	Type string `json:"type"`
+
+	// URL of the provider API
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+
+	// Secret holding the credentials of the provider account in the keys apiKey, apiToken and password
+	// +optional
+	CredentialsSecretRef *ProviderSecretReference `json:"credentialsSecretRef,omitempty"`
+
+	// Alert contacts notified by the monitors of the provider account
+	// +optional
+	AlertContacts string `json:"alertContacts,omitempty"`
+
+	// +optional
+	AlertIntegrations string `json:"alertIntegrations,omitempty"`
+
+	// +optional
+	TeamAlertContacts string `json:"teamAlertContacts,omitempty"`
+
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// +optional
+	AccountEmail string `json:"accountEmail,omitempty"`
+
+	// Provider specific settings with the same keys as a provider of the controller config, one of appInsightsConfig,
+	// gcloudConfig, grafanaConfig, pluginConfig, webhookConfig and blackboxConfig
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	Config *runtime.RawExtension `json:"config,omitempty"`
+
+	// Defaults for the provider config of the EndpointMonitors using this provider,
+	// fields set on an EndpointMonitor take precedence
+	// +optional
+	Defaults *MonitorProviderDefaults `json:"defaults,omitempty"`
+}
+
+// ProviderSecretReference selects the Secret holding the credentials of a provider
+type ProviderSecretReference struct {
+	Name string `json:"name"`
+
+	// Namespace of the Secret, required for a ClusterMonitorProvider and ignored for a MonitorProvider
+	// which always reads the Secret from its own namespace
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// MonitorProviderDefaults holds the default provider config of EndpointMonitors, only the config
+// matching the type of the provider is used
+type MonitorProviderDefaults struct {
+	// +optional
+	UptimeRobotConfig *UptimeRobotConfig `json:"uptimeRobotConfig,omitempty"`
+
+	// +optional
+	UptimeConfig *UptimeConfig `json:"uptimeConfig,omitempty"`
+
+	// +optional
+	UpdownConfig *UpdownConfig `json:"updownConfig,omitempty"`
+
+	// +optional
+	StatusCakeConfig *StatusCakeConfig `json:"statusCakeConfig,omitempty"`
+
+	// +optional
+	PingdomConfig *PingdomConfig `json:"pingdomConfig,omitempty"`
+
+	// +optional
+	PingdomTransactionConfig *PingdomTransactionConfig `json:"pingdomTransactionConfig,omitempty"`
+
+	// +optional
+	AppInsightsConfig *AppInsightsConfig `json:"appInsightsConfig,omitempty"`
+
+	// +optional
+	GCloudConfig *GCloudConfig `json:"gcloudConfig,omitempty"`
+
+	// +optional
+	GrafanaConfig *GrafanaConfig `json:"grafanaConfig,omitempty"`
+
+	// +optional
+	HealthchecksConfig *HealthchecksConfig `json:"healthchecksConfig,omitempty"`
+
+	// +optional
+	LocalConfig *LocalConfig `json:"localConfig,omitempty"`
+
+	// +optional
+	BlackboxConfig *BlackboxConfig `json:"blackboxConfig,omitempty"`
+
+	// +optional
+	BetterStackConfig *BetterStackConfig `json:"betterStackConfig,omitempty"`
+
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	PluginConfig *runtime.RawExtension `json:"pluginConfig,omitempty"`
+
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	WebhookConfig *runtime.RawExtension `json:"webhookConfig,omitempty"`
+}
+
+// MonitorProviderStatus defines the observed state of MonitorProvider
+type MonitorProviderStatus struct {
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// MonitorProvider is the Schema for the monitorproviders API
+type MonitorProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MonitorProviderSpec   `json:"spec,omitempty"`
+	Status MonitorProviderStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MonitorProviderList contains a list of MonitorProvider
+type MonitorProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MonitorProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MonitorProvider{}, &MonitorProviderList{})
+}
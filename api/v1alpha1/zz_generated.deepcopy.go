@@ -21,7 +21,10 @@ limitations under the License.
 package v1alpha1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -39,59 +42,908 @@ func (in *AppInsightsConfig) DeepCopy() *AppInsightsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BetterStackConfig) DeepCopyInto(out *BetterStackConfig) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequestHeaders != nil {
+		in, out := &in.RequestHeaders, &out.RequestHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpectedStatusCodes != nil {
+		in, out := &in.ExpectedStatusCodes, &out.ExpectedStatusCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.FollowRedirects != nil {
+		in, out := &in.FollowRedirects, &out.FollowRedirects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VerifySSL != nil {
+		in, out := &in.VerifySSL, &out.VerifySSL
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SMS != nil {
+		in, out := &in.SMS, &out.SMS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Call != nil {
+		in, out := &in.Call, &out.Call
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Push != nil {
+		in, out := &in.Push, &out.Push
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BetterStackConfig.
+func (in *BetterStackConfig) DeepCopy() *BetterStackConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BetterStackConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackboxAlert) DeepCopyInto(out *BlackboxAlert) {
+	*out = *in
+	if in.For != nil {
+		in, out := &in.For, &out.For
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RuleLabels != nil {
+		in, out := &in.RuleLabels, &out.RuleLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackboxAlert.
+func (in *BlackboxAlert) DeepCopy() *BlackboxAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackboxAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackboxConfig) DeepCopyInto(out *BlackboxConfig) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Alert != nil {
+		in, out := &in.Alert, &out.Alert
+		*out = new(BlackboxAlert)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackboxConfig.
+func (in *BlackboxConfig) DeepCopy() *BlackboxConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackboxConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BodyAssertion) DeepCopyInto(out *BodyAssertion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BodyAssertion.
+func (in *BodyAssertion) DeepCopy() *BodyAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(BodyAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateReference) DeepCopyInto(out *CertificateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateReference.
+func (in *CertificateReference) DeepCopy() *CertificateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Check) DeepCopyInto(out *Check) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpectedStatusCodes != nil {
+		in, out := &in.ExpectedStatusCodes, &out.ExpectedStatusCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.BodyAssertions != nil {
+		in, out := &in.BodyAssertions, &out.BodyAssertions
+		*out = make([]BodyAssertion, len(*in))
+		copy(*out, *in)
+	}
+	if in.FollowRedirects != nil {
+		in, out := &in.FollowRedirects, &out.FollowRedirects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VerifyTLS != nil {
+		in, out := &in.VerifyTLS, &out.VerifyTLS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Locations != nil {
+		in, out := &in.Locations, &out.Locations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Check.
+func (in *Check) DeepCopy() *Check {
+	if in == nil {
+		return nil
+	}
+	out := new(Check)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMonitorProvider) DeepCopyInto(out *ClusterMonitorProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMonitorProvider.
+func (in *ClusterMonitorProvider) DeepCopy() *ClusterMonitorProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMonitorProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMonitorProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMonitorProviderList) DeepCopyInto(out *ClusterMonitorProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterMonitorProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMonitorProviderList.
+func (in *ClusterMonitorProviderList) DeepCopy() *ClusterMonitorProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMonitorProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMonitorProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerStatus) DeepCopyInto(out *ControllerStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerStatus.
+func (in *ControllerStatus) DeepCopy() *ControllerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ControllerStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerStatusList) DeepCopyInto(out *ControllerStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ControllerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerStatusList.
+func (in *ControllerStatusList) DeepCopy() *ControllerStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ControllerStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerStatusSpec) DeepCopyInto(out *ControllerStatusSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerStatusSpec.
+func (in *ControllerStatusSpec) DeepCopy() *ControllerStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerStatusStatus) DeepCopyInto(out *ControllerStatusStatus) {
+	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]ProviderHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerStatusStatus.
+func (in *ControllerStatusStatus) DeepCopy() *ControllerStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronJobURLSource) DeepCopyInto(out *CronJobURLSource) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronJobURLSource.
+func (in *CronJobURLSource) DeepCopy() *CronJobURLSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CronJobURLSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EndpointMonitor) DeepCopyInto(out *EndpointMonitor) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitor.
+func (in *EndpointMonitor) DeepCopy() *EndpointMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EndpointMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointMonitorList) DeepCopyInto(out *EndpointMonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EndpointMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorList.
+func (in *EndpointMonitorList) DeepCopy() *EndpointMonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointMonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EndpointMonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointMonitorSpec) DeepCopyInto(out *EndpointMonitorSpec) {
+	*out = *in
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
+	}
+	if in.Adopt != nil {
+		in, out := &in.Adopt, &out.Adopt
+		*out = new(MonitorAdoption)
+		**out = **in
+	}
+	if in.URLFrom != nil {
+		in, out := &in.URLFrom, &out.URLFrom
+		*out = new(URLSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Check != nil {
+		in, out := &in.Check, &out.Check
+		*out = new(Check)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(GRPCCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSCertificate != nil {
+		in, out := &in.TLSCertificate, &out.TLSCertificate
+		*out = new(TLSCertificateSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OutputRef != nil {
+		in, out := &in.OutputRef, &out.OutputRef
+		*out = new(OutputReference)
+		**out = **in
+	}
+	if in.ReadinessGate != nil {
+		in, out := &in.ReadinessGate, &out.ReadinessGate
+		*out = new(ReadinessGate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UptimeRobotConfig != nil {
+		in, out := &in.UptimeRobotConfig, &out.UptimeRobotConfig
+		*out = new(UptimeRobotConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UptimeConfig != nil {
+		in, out := &in.UptimeConfig, &out.UptimeConfig
+		*out = new(UptimeConfig)
+		**out = **in
+	}
+	if in.UpdownConfig != nil {
+		in, out := &in.UpdownConfig, &out.UpdownConfig
+		*out = new(UpdownConfig)
+		**out = **in
+	}
+	if in.StatusCakeConfig != nil {
+		in, out := &in.StatusCakeConfig, &out.StatusCakeConfig
+		*out = new(StatusCakeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PingdomConfig != nil {
+		in, out := &in.PingdomConfig, &out.PingdomConfig
+		*out = new(PingdomConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PingdomTransactionConfig != nil {
+		in, out := &in.PingdomTransactionConfig, &out.PingdomTransactionConfig
+		*out = new(PingdomTransactionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppInsightsConfig != nil {
+		in, out := &in.AppInsightsConfig, &out.AppInsightsConfig
+		*out = new(AppInsightsConfig)
+		**out = **in
+	}
+	if in.GCloudConfig != nil {
+		in, out := &in.GCloudConfig, &out.GCloudConfig
+		*out = new(GCloudConfig)
+		**out = **in
+	}
+	if in.GrafanaConfig != nil {
+		in, out := &in.GrafanaConfig, &out.GrafanaConfig
+		*out = new(GrafanaConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthchecksConfig != nil {
+		in, out := &in.HealthchecksConfig, &out.HealthchecksConfig
+		*out = new(HealthchecksConfig)
+		**out = **in
+	}
+	if in.LocalConfig != nil {
+		in, out := &in.LocalConfig, &out.LocalConfig
+		*out = new(LocalConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlackboxConfig != nil {
+		in, out := &in.BlackboxConfig, &out.BlackboxConfig
+		*out = new(BlackboxConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BetterStackConfig != nil {
+		in, out := &in.BetterStackConfig, &out.BetterStackConfig
+		*out = new(BetterStackConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebhookConfig != nil {
+		in, out := &in.WebhookConfig, &out.WebhookConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorSpec.
+func (in *EndpointMonitorSpec) DeepCopy() *EndpointMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointMonitorStatus) DeepCopyInto(out *EndpointMonitorStatus) {
+	*out = *in
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppliedTemplates != nil {
+		in, out := &in.AppliedTemplates, &out.AppliedTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(CertificateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OutputRef != nil {
+		in, out := &in.OutputRef, &out.OutputRef
+		*out = new(OutputReference)
+		**out = **in
+	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(ProbeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorStatus.
+func (in *EndpointMonitorStatus) DeepCopy() *EndpointMonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointMonitorTemplate) DeepCopyInto(out *EndpointMonitorTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorTemplate.
+func (in *EndpointMonitorTemplate) DeepCopy() *EndpointMonitorTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointMonitorTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EndpointMonitorTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointMonitorTemplateList) DeepCopyInto(out *EndpointMonitorTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EndpointMonitorTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorTemplateList.
+func (in *EndpointMonitorTemplateList) DeepCopy() *EndpointMonitorTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointMonitorTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EndpointMonitorTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointMonitorTemplateSpec) DeepCopyInto(out *EndpointMonitorTemplateSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.MonitorProviderDefaults.DeepCopyInto(&out.MonitorProviderDefaults)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorTemplateSpec.
+func (in *EndpointMonitorTemplateSpec) DeepCopy() *EndpointMonitorTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointMonitorTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointMonitorTemplateStatus) DeepCopyInto(out *EndpointMonitorTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorTemplateStatus.
+func (in *EndpointMonitorTemplateStatus) DeepCopy() *EndpointMonitorTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointMonitorTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCloudConfig) DeepCopyInto(out *GCloudConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCloudConfig.
+func (in *GCloudConfig) DeepCopy() *GCloudConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GCloudConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCCheck) DeepCopyInto(out *GRPCCheck) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCCheck.
+func (in *GRPCCheck) DeepCopy() *GRPCCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaConfig) DeepCopyInto(out *GrafanaConfig) {
+	*out = *in
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfig.
+func (in *GrafanaConfig) DeepCopy() *GrafanaConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthchecksConfig) DeepCopyInto(out *HealthchecksConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthchecksConfig.
+func (in *HealthchecksConfig) DeepCopy() *HealthchecksConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthchecksConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressURLSource) DeepCopyInto(out *IngressURLSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressURLSource.
+func (in *IngressURLSource) DeepCopy() *IngressURLSource {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressURLSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalConfig) DeepCopyInto(out *LocalConfig) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpectedStatusCodes != nil {
+		in, out := &in.ExpectedStatusCodes, &out.ExpectedStatusCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.BodyAssertions != nil {
+		in, out := &in.BodyAssertions, &out.BodyAssertions
+		*out = make([]BodyAssertion, len(*in))
+		copy(*out, *in)
+	}
+	if in.FollowRedirects != nil {
+		in, out := &in.FollowRedirects, &out.FollowRedirects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VerifyTLS != nil {
+		in, out := &in.VerifyTLS, &out.VerifyTLS
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitor.
-func (in *EndpointMonitor) DeepCopy() *EndpointMonitor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalConfig.
+func (in *LocalConfig) DeepCopy() *LocalConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(EndpointMonitor)
+	out := new(LocalConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EndpointMonitor) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorAdoption) DeepCopyInto(out *MonitorAdoption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorAdoption.
+func (in *MonitorAdoption) DeepCopy() *MonitorAdoption {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(MonitorAdoption)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EndpointMonitorList) DeepCopyInto(out *EndpointMonitorList) {
+func (in *MonitorProvider) DeepCopyInto(out *MonitorProvider) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]EndpointMonitor, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorList.
-func (in *EndpointMonitorList) DeepCopy() *EndpointMonitorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorProvider.
+func (in *MonitorProvider) DeepCopy() *MonitorProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(EndpointMonitorList)
+	out := new(MonitorProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EndpointMonitorList) DeepCopyObject() runtime.Object {
+func (in *MonitorProvider) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -99,17 +951,12 @@ func (in *EndpointMonitorList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EndpointMonitorSpec) DeepCopyInto(out *EndpointMonitorSpec) {
+func (in *MonitorProviderDefaults) DeepCopyInto(out *MonitorProviderDefaults) {
 	*out = *in
-	if in.URLFrom != nil {
-		in, out := &in.URLFrom, &out.URLFrom
-		*out = new(URLSource)
-		(*in).DeepCopyInto(*out)
-	}
 	if in.UptimeRobotConfig != nil {
 		in, out := &in.UptimeRobotConfig, &out.UptimeRobotConfig
 		*out = new(UptimeRobotConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.UptimeConfig != nil {
 		in, out := &in.UptimeConfig, &out.UptimeConfig
@@ -124,12 +971,12 @@ func (in *EndpointMonitorSpec) DeepCopyInto(out *EndpointMonitorSpec) {
 	if in.StatusCakeConfig != nil {
 		in, out := &in.StatusCakeConfig, &out.StatusCakeConfig
 		*out = new(StatusCakeConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.PingdomConfig != nil {
 		in, out := &in.PingdomConfig, &out.PingdomConfig
 		*out = new(PingdomConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.PingdomTransactionConfig != nil {
 		in, out := &in.PingdomTransactionConfig, &out.PingdomTransactionConfig
@@ -151,79 +998,136 @@ func (in *EndpointMonitorSpec) DeepCopyInto(out *EndpointMonitorSpec) {
 		*out = new(GrafanaConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HealthchecksConfig != nil {
+		in, out := &in.HealthchecksConfig, &out.HealthchecksConfig
+		*out = new(HealthchecksConfig)
+		**out = **in
+	}
+	if in.LocalConfig != nil {
+		in, out := &in.LocalConfig, &out.LocalConfig
+		*out = new(LocalConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlackboxConfig != nil {
+		in, out := &in.BlackboxConfig, &out.BlackboxConfig
+		*out = new(BlackboxConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BetterStackConfig != nil {
+		in, out := &in.BetterStackConfig, &out.BetterStackConfig
+		*out = new(BetterStackConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebhookConfig != nil {
+		in, out := &in.WebhookConfig, &out.WebhookConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorSpec.
-func (in *EndpointMonitorSpec) DeepCopy() *EndpointMonitorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorProviderDefaults.
+func (in *MonitorProviderDefaults) DeepCopy() *MonitorProviderDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(EndpointMonitorSpec)
+	out := new(MonitorProviderDefaults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EndpointMonitorStatus) DeepCopyInto(out *EndpointMonitorStatus) {
+func (in *MonitorProviderList) DeepCopyInto(out *MonitorProviderList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MonitorProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointMonitorStatus.
-func (in *EndpointMonitorStatus) DeepCopy() *EndpointMonitorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorProviderList.
+func (in *MonitorProviderList) DeepCopy() *MonitorProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(EndpointMonitorStatus)
+	out := new(MonitorProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MonitorProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GCloudConfig) DeepCopyInto(out *GCloudConfig) {
+func (in *MonitorProviderSpec) DeepCopyInto(out *MonitorProviderSpec) {
 	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(ProviderSecretReference)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(MonitorProviderDefaults)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCloudConfig.
-func (in *GCloudConfig) DeepCopy() *GCloudConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorProviderSpec.
+func (in *MonitorProviderSpec) DeepCopy() *MonitorProviderSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GCloudConfig)
+	out := new(MonitorProviderSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaConfig) DeepCopyInto(out *GrafanaConfig) {
+func (in *MonitorProviderStatus) DeepCopyInto(out *MonitorProviderStatus) {
 	*out = *in
-	if in.Probes != nil {
-		in, out := &in.Probes, &out.Probes
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaConfig.
-func (in *GrafanaConfig) DeepCopy() *GrafanaConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorProviderStatus.
+func (in *MonitorProviderStatus) DeepCopy() *MonitorProviderStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaConfig)
+	out := new(MonitorProviderStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressURLSource) DeepCopyInto(out *IngressURLSource) {
+func (in *OutputReference) DeepCopyInto(out *OutputReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressURLSource.
-func (in *IngressURLSource) DeepCopy() *IngressURLSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputReference.
+func (in *OutputReference) DeepCopy() *OutputReference {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressURLSource)
+	out := new(OutputReference)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -231,6 +1135,26 @@ func (in *IngressURLSource) DeepCopy() *IngressURLSource {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PingdomConfig) DeepCopyInto(out *PingdomConfig) {
 	*out = *in
+	if in.RequestHeadersFrom != nil {
+		in, out := &in.RequestHeadersFrom, &out.RequestHeadersFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BasicAuthPasswordFrom != nil {
+		in, out := &in.BasicAuthPasswordFrom, &out.BasicAuthPasswordFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShouldContainFrom != nil {
+		in, out := &in.ShouldContainFrom, &out.ShouldContainFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostDataFrom != nil {
+		in, out := &in.PostDataFrom, &out.PostDataFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PingdomConfig.
@@ -253,6 +1177,13 @@ func (in *PingdomStep) DeepCopyInto(out *PingdomStep) {
 			(*out)[key] = val
 		}
 	}
+	if in.ArgsFrom != nil {
+		in, out := &in.ArgsFrom, &out.ArgsFrom
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PingdomStep.
@@ -292,6 +1223,95 @@ func (in *PingdomTransactionConfig) DeepCopy() *PingdomTransactionConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeStatus) DeepCopyInto(out *ProbeStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CertificateNotAfter != nil {
+		in, out := &in.CertificateNotAfter, &out.CertificateNotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeStatus.
+func (in *ProbeStatus) DeepCopy() *ProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderHealth) DeepCopyInto(out *ProviderHealth) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderHealth.
+func (in *ProviderHealth) DeepCopy() *ProviderHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderReference) DeepCopyInto(out *ProviderReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderReference.
+func (in *ProviderReference) DeepCopy() *ProviderReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderSecretReference) DeepCopyInto(out *ProviderSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSecretReference.
+func (in *ProviderSecretReference) DeepCopy() *ProviderSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessGate) DeepCopyInto(out *ReadinessGate) {
+	*out = *in
+	if in.MaxWait != nil {
+		in, out := &in.MaxWait, &out.MaxWait
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessGate.
+func (in *ReadinessGate) DeepCopy() *ReadinessGate {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteURLSource) DeepCopyInto(out *RouteURLSource) {
 	*out = *in
@@ -307,9 +1327,44 @@ func (in *RouteURLSource) DeepCopy() *RouteURLSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceURLSource) DeepCopyInto(out *ServiceURLSource) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceURLSource.
+func (in *ServiceURLSource) DeepCopy() *ServiceURLSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceURLSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusCakeConfig) DeepCopyInto(out *StatusCakeConfig) {
 	*out = *in
+	if in.BasicAuthPasswordFrom != nil {
+		in, out := &in.BasicAuthPasswordFrom, &out.BasicAuthPasswordFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FindStringFrom != nil {
+		in, out := &in.FindStringFrom, &out.FindStringFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RawPostDataFrom != nil {
+		in, out := &in.RawPostDataFrom, &out.RawPostDataFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusCakeConfig.
@@ -322,6 +1377,31 @@ func (in *StatusCakeConfig) DeepCopy() *StatusCakeConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificateSource) DeepCopyInto(out *TLSCertificateSource) {
+	*out = *in
+	if in.CertificateRef != nil {
+		in, out := &in.CertificateRef, &out.CertificateRef
+		*out = new(CertificateReference)
+		**out = **in
+	}
+	if in.IngressRef != nil {
+		in, out := &in.IngressRef, &out.IngressRef
+		*out = new(IngressURLSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificateSource.
+func (in *TLSCertificateSource) DeepCopy() *TLSCertificateSource {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificateSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *URLSource) DeepCopyInto(out *URLSource) {
 	*out = *in
@@ -335,6 +1415,16 @@ func (in *URLSource) DeepCopyInto(out *URLSource) {
 		*out = new(RouteURLSource)
 		**out = **in
 	}
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = new(ServiceURLSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CronJobRef != nil {
+		in, out := &in.CronJobRef, &out.CronJobRef
+		*out = new(CronJobURLSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new URLSource.
@@ -380,6 +1470,11 @@ func (in *UptimeConfig) DeepCopy() *UptimeConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UptimeRobotConfig) DeepCopyInto(out *UptimeRobotConfig) {
 	*out = *in
+	if in.KeywordValueFrom != nil {
+		in, out := &in.KeywordValueFrom, &out.KeywordValueFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UptimeRobotConfig.
@@ -391,3 +1486,23 @@ func (in *UptimeRobotConfig) DeepCopy() *UptimeRobotConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValueSource) DeepCopyInto(out *ValueSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueSource.
+func (in *ValueSource) DeepCopy() *ValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,18 +17,23 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
+	"sync/atomic"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -36,7 +41,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
 	controllers "github.com/stakater/IngressMonitorController/v2/internal/controller"
-	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	configpkg "github.com/stakater/IngressMonitorController/v2/pkg/config"
 	"github.com/stakater/IngressMonitorController/v2/pkg/kube"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -67,6 +72,10 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var metricsCertDir string
+	var leaderElectionID string
+	var namespaceSelector string
+	var endpointMonitorSelector string
+	var controllerStatusName string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -74,6 +83,8 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "2bf90a83.stakater.com",
+		"Name of the leader election lease, must be unique for every controller instance in a namespace.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
@@ -84,6 +95,12 @@ func main() {
 	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
 		"The maximum number of concurrent Reconciles which can be run.",
 	)
+	flag.StringVar(&namespaceSelector, "namespace-selector", "",
+		"Label selector of the namespaces whose EndpointMonitors are handled, overrides namespaceSelector of the config.")
+	flag.StringVar(&endpointMonitorSelector, "endpointmonitor-selector", "",
+		"Label selector of the EndpointMonitors that are handled, overrides endpointMonitorSelector of the config.")
+	flag.StringVar(&controllerStatusName, "controller-status-name", "ingressmonitorcontroller",
+		"Name of the ControllerStatus the connectivity of the providers is reported in, must be unique for every controller instance in a namespace.")
 
 	opts := zap.Options{
 		Development: false,
@@ -132,13 +149,82 @@ func main() {
 		setupLog.Info("Unable to fetch WatchNamespace, the manager will watch and manage resources in all Namespaces")
 	}
 
+	restConfig := ctrl.GetConfigOrDie()
+
+	ctx := ctrl.SetupSignalHandler()
+	flags := managerFlags{
+		probeAddr:               probeAddr,
+		enableLeaderElection:    enableLeaderElection,
+		leaderElectionID:        leaderElectionID,
+		metricsServerOptions:    metricsServerOptions,
+		maxConcurrentReconciles: maxConcurrentReconciles,
+		namespaceSelector:       namespaceSelector,
+		endpointMonitorSelector: endpointMonitorSelector,
+		controllerStatusName:    controllerStatusName,
+	}
+	for restarted := false; runManager(ctx, restConfig, restarted, watchNamespace, flags); restarted = true {
+		setupLog.Info("restarting manager with the loaded controller config")
+	}
+}
+
+// managerFlags are the command line flags used to set up the manager
+type managerFlags struct {
+	probeAddr               string
+	enableLeaderElection    bool
+	leaderElectionID        string
+	metricsServerOptions    metricsserver.Options
+	maxConcurrentReconciles int
+	namespaceSelector       string
+	endpointMonitorSelector string
+	controllerStatusName    string
+}
+
+// runManager sets up the manager from the controller config and runs it until ctx is done. It returns true when
+// it was stopped to be started again, as the config loaded after failing to load at first.
+func runManager(ctx context.Context, restConfig *rest.Config, restarted bool, watchNamespace string, flags managerFlags) bool {
+	// Load Controller Config before the manager is created, since it configures the cache
+	apiReader, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+	// A config that fails to load leaves only MonitorProviders and ClusterMonitorProviders usable, which is
+	// reported through the readiness check. It is loaded again with backoff, the manager is then restarted to
+	// set up the cache and the providers of the config.
+	configErr := configpkg.LoadControllerConfig(apiReader)
+	if configErr != nil {
+		setupLog.Error(configErr, "unable to load controller config")
+	}
+	config := configpkg.GetControllerConfig()
+
+	namespaceSelector, endpointMonitorSelector := flags.namespaceSelector, flags.endpointMonitorSelector
+	if len(namespaceSelector) == 0 {
+		namespaceSelector = config.NamespaceSelector
+	}
+	if len(endpointMonitorSelector) == 0 {
+		endpointMonitorSelector = config.EndpointMonitorSelector
+	}
+	namespaceLabelSelector, err := parseSelector(namespaceSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid namespace selector")
+		os.Exit(1)
+	}
+	endpointMonitorLabelSelector, err := parseSelector(endpointMonitorSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid EndpointMonitor selector")
+		os.Exit(1)
+	}
+
 	options := ctrl.Options{
 		Scheme:                 scheme,
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "2bf90a83.stakater.com",
+		HealthProbeBindAddress: flags.probeAddr,
+		LeaderElection:         flags.enableLeaderElection,
+		LeaderElectionID:       flags.leaderElectionID,
 		// Namespace:              watchNamespace, // namespaced-scope when the value is not an empty string
-		Metrics: metricsServerOptions,
+		Metrics: flags.metricsServerOptions,
+		// The manager is stopped and started again once a failing config loads
+		LeaderElectionReleaseOnCancel: true,
+		Controller:                    ctrlconfig.Controller{SkipNameValidation: &restarted},
 	}
 
 	// Add support for MultiNamespace set in WATCH_NAMESPACE (e.g ns1,ns2)
@@ -146,30 +232,91 @@ func main() {
 	// Only restrict the cache when a non-empty WATCH_NAMESPACE is set. When empty, leave DefaultNamespaces nil
 	// so the manager uses a global (all-namespace) cache. Setting DefaultNamespaces = {"": {}} would trigger
 	// multiNamespaceCache whose List() does not fall back to the global informer for specific namespaces.
-	if defaultNamespaces := buildDefaultNamespaces(watchNamespace); defaultNamespaces != nil {
+	defaultNamespaces := buildDefaultNamespaces(watchNamespace)
+	if defaultNamespaces != nil {
 		setupLog.Info("Manager will be watching namespace(s)", "namespaces", watchNamespace)
+	}
+	if endpointMonitorLabelSelector != nil {
+		setupLog.Info("Manager will only cache EndpointMonitors matching the selector", "selector", endpointMonitorSelector)
+	}
+	if defaultNamespaces != nil || endpointMonitorLabelSelector != nil {
 		options.NewCache = func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
 			opts.DefaultNamespaces = defaultNamespaces
+			if endpointMonitorLabelSelector != nil {
+				opts.ByObject = map[client.Object]cache.ByObject{
+					&endpointmonitorv1alpha1.EndpointMonitor{}: {Label: endpointMonitorLabelSelector},
+				}
+			}
 			return cache.New(config, opts)
 		}
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+	mgr, err := ctrl.NewManager(restConfig, options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	// Load Controller Config
-	config.LoadControllerConfig(mgr.GetAPIReader())
-	config := config.GetControllerConfig()
+	monitorServices, err := monitors.SetupMonitorServicesForProviders(config.Providers)
+	if err != nil {
+		setupLog.Error(err, "invalid providers in controller config")
+		os.Exit(1)
+	}
+
+	var configLoaded atomic.Bool
+	managerCtx, stopManager := context.WithCancel(ctx)
+	defer stopManager()
+	configRetrier := monitors.NewConfigRetrier(func() error { return configpkg.LoadControllerConfig(apiReader) }, configErr,
+		func() {
+			configLoaded.Store(true)
+			stopManager()
+		})
+	if err := mgr.Add(configRetrier); err != nil {
+		setupLog.Error(err, "unable to set up config retrier")
+		os.Exit(1)
+	}
+
+	// Providers that failed to set up are retried without affecting the others
+	if err := mgr.Add(monitors.NewSetupRetrier(monitorServices)); err != nil {
+		setupLog.Error(err, "unable to set up provider setup retrier")
+		os.Exit(1)
+	}
+
+	// Check the connectivity of the providers, the leader reports it in the ControllerStatus
+	controllerStatusUpdater := &controllers.ControllerStatusUpdater{
+		Client:          mgr.GetClient(),
+		APIReader:       mgr.GetAPIReader(),
+		Namespace:       configpkg.OperatorNamespace,
+		Name:            flags.controllerStatusName,
+		MonitorServices: monitorServices,
+		Elected:         mgr.Elected(),
+	}
+	if err := mgr.Add(monitors.NewHealthChecker(monitorServices, config.ProviderCheckInterval, controllerStatusUpdater.Update)); err != nil {
+		setupLog.Error(err, "unable to set up provider health checker")
+		os.Exit(1)
+	}
+
+	// Reload providers when their credentials stored in Secrets or files change
+	credentialsWatcher := configpkg.NewCredentialsWatcher(mgr.GetAPIReader(), configpkg.OperatorNamespace, config,
+		func(index int, provider configpkg.Provider) {
+			_ = monitorServices[index].Reload(provider)
+		})
+	if err := mgr.Add(credentialsWatcher); err != nil {
+		setupLog.Error(err, "unable to set up credentials watcher")
+		os.Exit(1)
+	}
 
 	if err = (&controllers.EndpointMonitorReconciler{
 		Client:          mgr.GetClient(),
 		Log:             ctrl.Log.WithName("controllers").WithName("EndpointMonitor"),
 		Scheme:          mgr.GetScheme(),
-		MonitorServices: monitors.SetupMonitorServicesForProviders(config.Providers),
-	}).SetupWithManager(mgr, maxConcurrentReconciles); err != nil {
+		MonitorServices: monitorServices,
+		APIReader:       mgr.GetAPIReader(),
+
+		NamespaceSelector:       namespaceLabelSelector,
+		EndpointMonitorSelector: endpointMonitorLabelSelector,
+		ClusterScoped:           defaultNamespaces == nil,
+	}).SetupWithManager(mgr, flags.maxConcurrentReconciles); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EndpointMonitor")
 		os.Exit(1)
 	}
@@ -183,12 +330,31 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("config", configRetrier.ReadyzCheck); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	for _, monitorService := range monitorServices {
+		if err := mgr.AddReadyzCheck("provider-"+monitorService.GetID(), monitorService.ReadyzCheck); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "provider", monitorService.GetID())
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(managerCtx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	return configLoaded.Load()
+}
+
+// parseSelector parses a label selector, an empty selector returns nil
+func parseSelector(selector string) (labels.Selector, error) {
+	if len(strings.TrimSpace(selector)) == 0 {
+		return nil, nil
+	}
+	return labels.Parse(selector)
 }
 
 // buildDefaultNamespaces returns the cache.Config map for the given comma-separated
@@ -3,6 +3,8 @@ package main
 import (
 	"os"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func TestBuildDefaultNamespaces(t *testing.T) {
@@ -62,6 +64,63 @@ func TestBuildDefaultNamespaces(t *testing.T) {
 	}
 }
 
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		wantNil  bool
+		wantErr  bool
+		matches  map[string]string
+	}{
+		{
+			name:     "empty selector returns nil",
+			selector: " ",
+			wantNil:  true,
+		},
+		{
+			name:     "equality selector",
+			selector: "monitoring.stakater.com/enabled=true",
+			matches:  map[string]string{"monitoring.stakater.com/enabled": "true"},
+		},
+		{
+			name:     "set based selector",
+			selector: "environment in (production, staging)",
+			matches:  map[string]string{"environment": "staging"},
+		},
+		{
+			name:     "invalid selector",
+			selector: "environment in production",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSelector(tt.selector)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got selector %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("expected nil, got %v", got)
+				}
+				return
+			}
+
+			if got == nil || !got.Matches(labels.Set(tt.matches)) {
+				t.Errorf("expected selector %v to match %v", got, tt.matches)
+			}
+		})
+	}
+}
+
 func TestGetWatchNamespace(t *testing.T) {
 	tests := []struct {
 		name    string
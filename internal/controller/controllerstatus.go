@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+//+kubebuilder:rbac:groups=endpointmonitor.stakater.com,resources=controllerstatuses,verbs=get;create
+//+kubebuilder:rbac:groups=endpointmonitor.stakater.com,resources=controllerstatuses/status,verbs=get;update;patch
+
+// ControllerStatusUpdater records the connectivity of the providers in the ControllerStatus of the controller
+type ControllerStatusUpdater struct {
+	Client          client.Client
+	APIReader       client.Reader
+	Namespace       string
+	Name            string
+	MonitorServices []*monitors.MonitorServiceProxy
+
+	// Elected is closed once this replica is the leader, only the leader updates the ControllerStatus
+	Elected <-chan struct{}
+}
+
+// Update records the result of the last connectivity checks, it is meant to be used as HealthChecker.OnCheck
+func (u *ControllerStatusUpdater) Update(ctx context.Context) {
+	if len(u.Namespace) == 0 {
+		return
+	}
+	select {
+	case <-u.Elected:
+	default:
+		return
+	}
+	if err := u.update(ctx); err != nil {
+		log.Error(err, "Failed to update ControllerStatus "+u.Namespace+"/"+u.Name)
+	}
+}
+
+func (u *ControllerStatusUpdater) update(ctx context.Context) error {
+	status := &endpointmonitorv1alpha1.ControllerStatus{}
+	err := u.APIReader.Get(ctx, types.NamespacedName{Name: u.Name, Namespace: u.Namespace}, status)
+	if errors.IsNotFound(err) {
+		status = &endpointmonitorv1alpha1.ControllerStatus{ObjectMeta: metav1.ObjectMeta{Name: u.Name, Namespace: u.Namespace}}
+		err = u.Client.Create(ctx, status)
+	}
+	if err != nil {
+		return err
+	}
+
+	providers := providerHealth(status.Status.Providers, u.MonitorServices)
+	changed := !reflect.DeepEqual(providers, status.Status.Providers)
+	status.Status.Providers = providers
+
+	var unreachable []string
+	for _, provider := range providers {
+		if !provider.Ready {
+			unreachable = append(unreachable, provider.ID)
+		}
+	}
+	condition := metav1.Condition{
+		Type:    endpointmonitorv1alpha1.ConditionTypeProvidersReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  endpointmonitorv1alpha1.ReasonProvidersReachable,
+		Message: "All providers are reachable",
+	}
+	if len(unreachable) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = endpointmonitorv1alpha1.ReasonProviderUnreachable
+		condition.Message = "Providers not reachable: " + strings.Join(unreachable, ", ")
+	}
+	if meta.SetStatusCondition(&status.Status.Conditions, condition) {
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return u.Client.Status().Update(ctx, status)
+}
+
+// providerHealth returns the health of the monitor services sorted by ID, the transition time of
+// providers whose readiness didn't change is kept
+func providerHealth(previous []endpointmonitorv1alpha1.ProviderHealth, monitorServices []*monitors.MonitorServiceProxy) []endpointmonitorv1alpha1.ProviderHealth {
+	previousByID := map[string]endpointmonitorv1alpha1.ProviderHealth{}
+	for _, provider := range previous {
+		previousByID[provider.ID] = provider
+	}
+
+	providers := []endpointmonitorv1alpha1.ProviderHealth{}
+	for _, monitorService := range monitorServices {
+		provider := endpointmonitorv1alpha1.ProviderHealth{
+			ID:                 monitorService.GetID(),
+			Type:               monitorService.GetType(),
+			Ready:              true,
+			LastTransitionTime: metav1.Now(),
+		}
+		if err := monitorService.Ready(); err != nil {
+			provider.Ready = false
+			provider.Message = err.Error()
+		}
+		if old, ok := previousByID[provider.ID]; ok && old.Ready == provider.Ready {
+			provider.LastTransitionTime = old.LastTransitionTime
+		}
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].ID < providers[j].ID
+	})
+	return providers
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestControllerStatusUpdater(t *testing.T) {
+	monitorServices, err := monitors.SetupMonitorServicesForProviders([]config.Provider{
+		{Name: monitors.TypeStatusCake, ID: "retail"},
+		// Fails to set up without an url
+		{Name: monitors.TypeWebhook, ID: "hooks"},
+	})
+	assert.NilError(t, err)
+
+	r := newProviderTestReconciler(t, nil)
+	elected := make(chan struct{})
+	updater := &ControllerStatusUpdater{
+		Client:          r.Client,
+		APIReader:       r.Client,
+		Namespace:       "imc",
+		Name:            "ingressmonitorcontroller",
+		MonitorServices: monitorServices,
+		Elected:         elected,
+	}
+	key := types.NamespacedName{Name: "ingressmonitorcontroller", Namespace: "imc"}
+
+	// Only the leader reports the status
+	updater.Update(context.TODO())
+	status := &endpointmonitorv1alpha1.ControllerStatus{}
+	assert.Assert(t, r.Get(context.TODO(), key, status) != nil)
+
+	close(elected)
+	updater.Update(context.TODO())
+	assert.NilError(t, r.Get(context.TODO(), key, status))
+	assert.Equal(t, len(status.Status.Providers), 2)
+	assert.Equal(t, status.Status.Providers[0].ID, "hooks")
+	assert.Equal(t, status.Status.Providers[0].Ready, false)
+	assert.Assert(t, len(status.Status.Providers[0].Message) > 0)
+	assert.Equal(t, status.Status.Providers[1].ID, "retail")
+	assert.Equal(t, status.Status.Providers[1].Ready, true)
+
+	condition := meta.FindStatusCondition(status.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeProvidersReady)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Message, "Providers not reachable: hooks")
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/kube/util"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+const monitorIDIndexField = "status.monitorID"
+
+// monitorIDIndexer indexes EndpointMonitors by the monitor ID recorded in their status
+func monitorIDIndexer(obj client.Object) []string {
+	endpointMonitor := obj.(*endpointmonitorv1alpha1.EndpointMonitor)
+	if len(endpointMonitor.Status.MonitorID) == 0 {
+		return nil
+	}
+	return []string{endpointMonitor.Status.MonitorID}
+}
+
+// adoptionFailure explains why an EndpointMonitor can't adopt a monitor
+type adoptionFailure struct {
+	reason  string
+	message string
+}
+
+// findAdoptedMonitor returns the monitor the EndpointMonitor adopts. Once adopted, the monitor is
+// found by the ID recorded in the status. Nothing is adopted unless exactly one monitor matches, the
+// reason is returned instead.
+func (r *EndpointMonitorReconciler) findAdoptedMonitor(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, monitorService *monitors.MonitorServiceProxy) (*models.Monitor, *adoptionFailure, error) {
+	status := instance.Status
+	if len(status.MonitorID) > 0 && recordedInAccount(status.Provider, status.ProviderID, monitorService) {
+		monitor, err := monitorService.GetByID(status.MonitorID)
+		if err != nil || monitor != nil {
+			return monitor, nil, err
+		}
+		// The adopted monitor has been removed from the provider, match again
+	}
+
+	candidates, err := r.matchMonitors(instance, monitorName, monitorService)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, &adoptionFailure{
+			reason:  endpointmonitorv1alpha1.ReasonNoMatchingMonitor,
+			message: "No monitor of provider " + monitorService.GetID() + " matches by " + string(instance.Spec.Adopt.Match),
+		}, nil
+	}
+	if len(candidates) > 1 {
+		matches := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			matches = append(matches, fmt.Sprintf("%s (%s)", candidate.ID, candidate.Name))
+		}
+		return nil, &adoptionFailure{
+			reason:  endpointmonitorv1alpha1.ReasonAmbiguousMatch,
+			message: "Monitors " + strings.Join(matches, ", ") + " all match by " + string(instance.Spec.Adopt.Match),
+		}, nil
+	}
+
+	monitor := &candidates[0]
+	owner, err := r.findMonitorIDOwner(instance, monitor.ID, monitorService)
+	if err != nil {
+		return nil, nil, err
+	}
+	if owner != nil {
+		return nil, &adoptionFailure{
+			reason:  endpointmonitorv1alpha1.ReasonMonitorOwned,
+			message: "Monitor " + monitor.ID + " (" + monitor.Name + ") is owned by EndpointMonitor " + owner.Namespace + "/" + owner.Name,
+		}, nil
+	}
+	if monitor.Name != monitorName && (!monitorService.SupportsRename() || config.GetControllerConfig().MonitorRename.Disabled) {
+		return nil, &adoptionFailure{
+			reason:  endpointmonitorv1alpha1.ReasonRenameUnsupported,
+			message: "Monitor " + monitor.ID + " (" + monitor.Name + ") can't be renamed in provider " + monitorService.GetID() + ", only a monitor named " + monitorName + " can be adopted",
+		}, nil
+	}
+	return monitor, nil, nil
+}
+
+// matchMonitors returns the monitors of the provider matching the adoption strategy of the EndpointMonitor
+func (r *EndpointMonitorReconciler) matchMonitors(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, monitorService *monitors.MonitorServiceProxy) ([]models.Monitor, error) {
+	adopt := instance.Spec.Adopt
+	if adopt.Match == endpointmonitorv1alpha1.AdoptionMatchID {
+		monitor, err := monitorService.GetByID(adopt.ID)
+		if err != nil || monitor == nil {
+			return nil, err
+		}
+		return []models.Monitor{*monitor}, nil
+	}
+
+	var matches func(monitor models.Monitor) bool
+	switch adopt.Match {
+	case endpointmonitorv1alpha1.AdoptionMatchName:
+		name := adopt.Name
+		if len(name) == 0 {
+			name = monitorName
+		}
+		matches = func(monitor models.Monitor) bool { return monitor.Name == name }
+	case endpointmonitorv1alpha1.AdoptionMatchURL:
+		url, err := util.GetMonitorURL(r.Client, r.apiReader(), instance)
+		if err != nil {
+			return nil, err
+		}
+		matches = func(monitor models.Monitor) bool { return sameMonitorURL(monitor.URL, url) }
+	default:
+		return nil, fmt.Errorf("unknown adoption match %q", adopt.Match)
+	}
+
+	// Search all monitors, since a provider may hold several monitors with the same name or URL
+	all, err := monitorService.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var candidates []models.Monitor
+	for _, monitor := range all {
+		if matches(monitor) {
+			candidates = append(candidates, monitor)
+		}
+	}
+	return candidates, nil
+}
+
+// sameMonitorURL compares monitor URLs, ignoring a trailing slash
+func sameMonitorURL(a, b string) bool {
+	return len(a) > 0 && strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+// findMonitorIDOwner returns another EndpointMonitor that records monitorID of the same provider account in its status
+func (r *EndpointMonitorReconciler) findMonitorIDOwner(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorID string, monitorService *monitors.MonitorServiceProxy) (*endpointmonitorv1alpha1.EndpointMonitor, error) {
+	endpointMonitors := &endpointmonitorv1alpha1.EndpointMonitorList{}
+	if err := r.List(context.TODO(), endpointMonitors, client.MatchingFields{monitorIDIndexField: monitorID}); err != nil {
+		return nil, err
+	}
+	for i := range endpointMonitors.Items {
+		other := &endpointMonitors.Items[i]
+		if other.UID != instance.UID && recordedInAccount(other.Status.Provider, other.Status.ProviderID, monitorService) {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// setAdoptionFailed reports in the status of the EndpointMonitor why no monitor is adopted
+func (r *EndpointMonitorReconciler) setAdoptionFailed(instance *endpointmonitorv1alpha1.EndpointMonitor, failure *adoptionFailure) error {
+	if !meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeAdopted,
+		Status:             metav1.ConditionFalse,
+		Reason:             failure.reason,
+		Message:            failure.message,
+		ObservedGeneration: instance.Generation,
+	}) {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), instance)
+}
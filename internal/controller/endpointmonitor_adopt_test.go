@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestFindAdoptedMonitor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"1","name":"Checkout","url":"https://checkout.example.com/"},
+			{"id":"2","name":"Blog","url":"https://blog.example.com"},
+			{"id":"3","name":"Blog (old)","url":"https://blog.example.com"},
+			{"id":"4","name":"Shop","url":"https://shop.example.com"}
+		]`))
+	}))
+	defer server.Close()
+	monitorService := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeWebhook, ID: "webhook", WebhookConfig: config.Webhook{URL: server.URL}})
+	assert.NilError(t, monitorService.Healthy())
+
+	// Shop is already owned by another EndpointMonitor
+	owner := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop", UID: "shop"},
+		Status:     endpointmonitorv1alpha1.EndpointMonitorStatus{MonitorID: "4", MonitorName: "shop-shop", Provider: monitors.TypeWebhook, ProviderID: "webhook"},
+	}
+	r := newProviderTestReconciler(t, nil, owner)
+	assert.NilError(t, r.Status().Update(context.TODO(), owner))
+
+	newInstance := func(url string, adopt endpointmonitorv1alpha1.MonitorAdoption) *endpointmonitorv1alpha1.EndpointMonitor {
+		return &endpointmonitorv1alpha1.EndpointMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "web"},
+			Spec:       endpointmonitorv1alpha1.EndpointMonitorSpec{URL: url, Adopt: &adopt},
+		}
+	}
+
+	monitor, failure, err := r.findAdoptedMonitor(newInstance("https://checkout.example.com", endpointmonitorv1alpha1.MonitorAdoption{Match: endpointmonitorv1alpha1.AdoptionMatchURL}), "web-shop", monitorService)
+	assert.NilError(t, err)
+	assert.Assert(t, failure == nil)
+	assert.Equal(t, monitor.ID, "1")
+
+	monitor, failure, err = r.findAdoptedMonitor(newInstance("https://blog.example.com", endpointmonitorv1alpha1.MonitorAdoption{Match: endpointmonitorv1alpha1.AdoptionMatchName, Name: "Blog"}), "web-shop", monitorService)
+	assert.NilError(t, err)
+	assert.Assert(t, failure == nil)
+	assert.Equal(t, monitor.ID, "2")
+
+	// Several monitors share the URL, none of them is picked
+	_, failure, err = r.findAdoptedMonitor(newInstance("https://blog.example.com", endpointmonitorv1alpha1.MonitorAdoption{Match: endpointmonitorv1alpha1.AdoptionMatchURL}), "web-shop", monitorService)
+	assert.NilError(t, err)
+	assert.Equal(t, failure.reason, endpointmonitorv1alpha1.ReasonAmbiguousMatch)
+	assert.Equal(t, failure.message, "Monitors 2 (Blog), 3 (Blog (old)) all match by URL")
+
+	_, failure, err = r.findAdoptedMonitor(newInstance("https://shop.example.com", endpointmonitorv1alpha1.MonitorAdoption{Match: endpointmonitorv1alpha1.AdoptionMatchID, ID: "4"}), "web-shop", monitorService)
+	assert.NilError(t, err)
+	assert.Equal(t, failure.reason, endpointmonitorv1alpha1.ReasonMonitorOwned)
+
+	_, failure, err = r.findAdoptedMonitor(newInstance("https://docs.example.com", endpointmonitorv1alpha1.MonitorAdoption{Match: endpointmonitorv1alpha1.AdoptionMatchURL}), "web-shop", monitorService)
+	assert.NilError(t, err)
+	assert.Equal(t, failure.reason, endpointmonitorv1alpha1.ReasonNoMatchingMonitor)
+
+	// Once adopted, the monitor is found by the ID recorded in the status
+	instance := newInstance("https://blog.example.com", endpointmonitorv1alpha1.MonitorAdoption{Match: endpointmonitorv1alpha1.AdoptionMatchURL})
+	instance.Status = endpointmonitorv1alpha1.EndpointMonitorStatus{MonitorID: "3", MonitorName: "web-shop", Provider: monitors.TypeWebhook, ProviderID: "webhook"}
+	monitor, failure, err = r.findAdoptedMonitor(instance, "web-shop", monitorService)
+	assert.NilError(t, err)
+	assert.Assert(t, failure == nil)
+	assert.Equal(t, monitor.ID, "3")
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+// prometheusRuleGVK is the prometheus-operator PrometheusRule, which is managed as unstructured so that
+// prometheus-operator doesn't have to be installed
+var prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+
+const (
+	// defaultAlertFor is how long the probes of a Blackbox monitor have to fail before the alert fires
+	defaultAlertFor = 5 * time.Minute
+	// defaultAlertSeverity is the severity label of the alert when the alert sets none
+	defaultAlertSeverity = "critical"
+	// endpointDownAlert is the name of the alert of the PrometheusRule
+	endpointDownAlert = "EndpointDown"
+)
+
+// alertRuleName returns the name of the PrometheusRule alerting on the probes of the EndpointMonitor
+func alertRuleName(instance *endpointmonitorv1alpha1.EndpointMonitor) string {
+	return instance.Name + "-blackbox"
+}
+
+// reconcileAlertRule creates the PrometheusRule alerting on the probes of a Blackbox monitor, which is
+// owned by the EndpointMonitor, and deletes it once blackboxConfig.alert is unset
+func (r *EndpointMonitorReconciler) reconcileAlertRule(instance *endpointmonitorv1alpha1.EndpointMonitor, spec endpointmonitorv1alpha1.EndpointMonitorSpec, monitorName string, monitorService *monitors.MonitorServiceProxy) error {
+	if monitorService.GetType() != monitors.TypeBlackbox {
+		return nil
+	}
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(prometheusRuleGVK)
+	rule.SetName(alertRuleName(instance))
+	rule.SetNamespace(instance.Namespace)
+
+	if spec.BlackboxConfig == nil || spec.BlackboxConfig.Alert == nil {
+		return r.removeAlertRule(instance, rule)
+	}
+	alert := spec.BlackboxConfig.Alert
+	result, err := r.applyOwnedObject(instance, rule, func() {
+		rule.SetLabels(alert.RuleLabels)
+		rule.Object["spec"] = alertRuleSpec(alert, monitorName)
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to create PrometheusRule %s, prometheus-operator is not installed: %w", rule.GetName(), err)
+		}
+		return fmt.Errorf("failed to create PrometheusRule %s: %w", rule.GetName(), err)
+	}
+	if result != controllerutil.OperationResultNone {
+		r.Log.Info("Reconciled the alert of monitor "+monitorName, "PrometheusRule", rule.GetName(), "Operation", result)
+	}
+	return nil
+}
+
+// alertRuleSpec returns the spec of the PrometheusRule, its alert fires when the probes of the monitor fail.
+// The Probe of the monitor sets its name as the job of the metrics.
+func alertRuleSpec(alert *endpointmonitorv1alpha1.BlackboxAlert, monitorName string) map[string]interface{} {
+	alertFor := defaultAlertFor
+	if alert.For != nil {
+		alertFor = alert.For.Duration
+	}
+	labels := map[string]interface{}{"severity": defaultAlertSeverity}
+	if len(alert.Severity) > 0 {
+		labels["severity"] = alert.Severity
+	}
+	for key, value := range alert.Labels {
+		labels[key] = value
+	}
+	return map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name": monitorName,
+				"rules": []interface{}{
+					map[string]interface{}{
+						"alert":  endpointDownAlert,
+						"expr":   "probe_success{job=" + strconv.Quote(monitorName) + "} == 0",
+						"for":    model.Duration(alertFor).String(),
+						"labels": labels,
+						"annotations": map[string]interface{}{
+							"summary":     "Endpoint {{ $labels.instance }} is down",
+							"description": "The probes of monitor " + monitorName + " have failed for " + model.Duration(alertFor).String() + ".",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// removeAlertRule deletes the PrometheusRule of the EndpointMonitor, unless another object took it over
+func (r *EndpointMonitorReconciler) removeAlertRule(instance *endpointmonitorv1alpha1.EndpointMonitor, rule *unstructured.Unstructured) error {
+	if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: rule.GetName(), Namespace: rule.GetNamespace()}, rule); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(rule, instance) {
+		return nil
+	}
+	if err := r.Delete(context.TODO(), rule); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete PrometheusRule %s: %w", rule.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestReconcileAlertRule(t *testing.T) {
+	blackbox := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeBlackbox})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", UID: "checkout-uid"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			BlackboxConfig: &endpointmonitorv1alpha1.BlackboxConfig{
+				Alert: &endpointmonitorv1alpha1.BlackboxAlert{
+					Severity:   "warning",
+					RuleLabels: map[string]string{"release": "prometheus"},
+				},
+			},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, instance)
+	getRule := func() (*unstructured.Unstructured, error) {
+		rule := &unstructured.Unstructured{}
+		rule.SetGroupVersionKind(prometheusRuleGVK)
+		return rule, r.Get(context.TODO(), types.NamespacedName{Name: "checkout-blackbox", Namespace: "shop"}, rule)
+	}
+
+	assert.NilError(t, r.reconcileAlertRule(instance, instance.Spec, "shop-checkout", blackbox))
+	rule, err := getRule()
+	assert.NilError(t, err)
+	assert.Equal(t, rule.GetLabels()["release"], "prometheus")
+	assert.Equal(t, rule.GetOwnerReferences()[0].UID, instance.UID)
+	rules, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	alert := rules[0].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, alert["expr"], `probe_success{job="shop-checkout"} == 0`)
+	assert.Equal(t, alert["for"], "5m")
+	assert.Equal(t, alert["labels"].(map[string]interface{})["severity"], "warning")
+
+	// Unsetting the alert deletes the PrometheusRule
+	instance.Spec.BlackboxConfig.Alert = nil
+	assert.NilError(t, r.reconcileAlertRule(instance, instance.Spec, "shop-checkout", blackbox))
+	_, err = getRule()
+	assert.Assert(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/kube/util"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+// certificateGVK is the cert-manager Certificate, which is read as unstructured so that cert-manager
+// doesn't have to be installed
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// certificateNameAnnotation is set by cert-manager on the Secrets of its Certificates
+const certificateNameAnnotation = "cert-manager.io/certificate-name"
+
+const (
+	// certificateDefaultDuration is the duration cert-manager issues certificates for when the Certificate doesn't set one
+	certificateDefaultDuration = 90 * 24 * time.Hour
+	// certificateRenewalGraceDays leaves failed renewals some days to be retried before alerting
+	certificateRenewalGraceDays = 2
+)
+
+// errCertificateNotFound is returned when the certificate of spec.tlsCertificate can't be read
+var errCertificateNotFound = errors.New("certificate not found")
+
+// getCertificate reads the certificate selected by spec.tlsCertificate, its expiry and the days before the
+// expiry to alert at. An error wrapping errCertificateNotFound is returned when it doesn't exist (yet).
+func (r *EndpointMonitorReconciler) getCertificate(instance *endpointmonitorv1alpha1.EndpointMonitor) (*endpointmonitorv1alpha1.CertificateStatus, error) {
+	source := instance.Spec.TLSCertificate
+	namespace := instance.Namespace
+
+	var secretName, certificateName string
+	if source.CertificateRef != nil {
+		certificateName = source.CertificateRef.Name
+	} else {
+		ingress := &networkingv1.Ingress{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: source.IngressRef.Name, Namespace: namespace}, ingress); err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, fmt.Errorf("%w: ingress %s/%s does not exist", errCertificateNotFound, namespace, source.IngressRef.Name)
+			}
+			return nil, err
+		}
+		for _, tls := range ingress.Spec.TLS {
+			if len(tls.SecretName) > 0 {
+				secretName = tls.SecretName
+				break
+			}
+		}
+		if len(secretName) == 0 {
+			return nil, fmt.Errorf("%w: ingress %s/%s has no TLS secret", errCertificateNotFound, namespace, source.IngressRef.Name)
+		}
+	}
+
+	var secret *corev1.Secret
+	if len(secretName) > 0 {
+		var err error
+		if secret, err = r.getTLSSecret(namespace, secretName); err != nil {
+			return nil, err
+		}
+		if secret != nil {
+			certificateName = secret.Annotations[certificateNameAnnotation]
+		}
+	}
+
+	var certificate *unstructured.Unstructured
+	if len(certificateName) > 0 {
+		certificate = &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(certificateGVK)
+		if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: certificateName, Namespace: namespace}, certificate); err != nil {
+			if !kerrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return nil, err
+			}
+			if source.CertificateRef != nil {
+				return nil, fmt.Errorf("%w: certificate %s/%s does not exist", errCertificateNotFound, namespace, certificateName)
+			}
+			// The Secret of the Ingress outlived its Certificate
+			certificate = nil
+		}
+	}
+	if certificate != nil && secret == nil {
+		secretName, _, _ = unstructured.NestedString(certificate.Object, "spec", "secretName")
+		var err error
+		if secret, err = r.getTLSSecret(namespace, secretName); err != nil {
+			return nil, err
+		}
+	}
+
+	status := &endpointmonitorv1alpha1.CertificateStatus{
+		SecretName:      secretName,
+		AlertDaysBefore: instance.Spec.TLSCertificate.AlertDaysBefore,
+	}
+	if secret != nil {
+		notAfter, err := parseCertificateExpiry(secret)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errCertificateNotFound, err.Error())
+		}
+		status.NotAfter = &metav1.Time{Time: notAfter}
+	} else if certificate != nil {
+		// The Secret has not been issued yet or can't be read, fall back to the status of the Certificate
+		if notAfter, found, _ := unstructured.NestedString(certificate.Object, "status", "notAfter"); found {
+			if parsed, err := time.Parse(time.RFC3339, notAfter); err == nil {
+				status.NotAfter = &metav1.Time{Time: parsed}
+			}
+		}
+	}
+	if status.NotAfter == nil {
+		return nil, fmt.Errorf("%w: secret %s/%s has not been issued", errCertificateNotFound, namespace, secretName)
+	}
+	if status.AlertDaysBefore == 0 {
+		status.AlertDaysBefore = certificateAlertDays(certificate)
+	}
+	return status, nil
+}
+
+// lookupCertificate reads the certificate of spec.tlsCertificate when it is set. A certificate that can't be
+// found is returned as the reason it isn't monitored rather than as an error, so the monitor is still reconciled.
+func (r *EndpointMonitorReconciler) lookupCertificate(instance *endpointmonitorv1alpha1.EndpointMonitor) (*endpointmonitorv1alpha1.CertificateStatus, error, error) {
+	if instance.Spec.TLSCertificate == nil {
+		return nil, nil, nil
+	}
+	certificate, err := r.getCertificate(instance)
+	if errors.Is(err, errCertificateNotFound) {
+		return nil, err, nil
+	}
+	return certificate, nil, err
+}
+
+// getTLSSecret reads a Secret holding a certificate, nil is returned when it doesn't exist
+func (r *EndpointMonitorReconciler) getTLSSecret(namespace string, name string) (*corev1.Secret, error) {
+	if len(name) == 0 {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// parseCertificateExpiry returns the expiry of the leaf certificate in the tls.crt of a Secret
+func parseCertificateExpiry(secret *corev1.Secret) (time.Time, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return time.Time{}, fmt.Errorf("secret %s/%s does not contain a PEM certificate in %s", secret.Namespace, secret.Name, corev1.TLSCertKey)
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("secret %s/%s does not contain a valid certificate: %s", secret.Namespace, secret.Name, err.Error())
+	}
+	return certificate.NotAfter, nil
+}
+
+// certificateAlertDays returns the days before the expiry to alert at, which is when cert-manager renews
+// the Certificate less certificateRenewalGraceDays. cert-manager renews a third of the duration before the
+// expiry unless renewBefore is set.
+func certificateAlertDays(certificate *unstructured.Unstructured) int {
+	duration := certificateDefaultDuration
+	var renewBefore time.Duration
+	if certificate != nil {
+		if value, found, _ := unstructured.NestedString(certificate.Object, "spec", "duration"); found {
+			if parsed, err := time.ParseDuration(value); err == nil {
+				duration = parsed
+			}
+		}
+		if value, found, _ := unstructured.NestedString(certificate.Object, "spec", "renewBefore"); found {
+			if parsed, err := time.ParseDuration(value); err == nil {
+				renewBefore = parsed
+			}
+		}
+	}
+	if renewBefore == 0 {
+		renewBefore = duration / 3
+	}
+	return max(int(renewBefore/(24*time.Hour))-certificateRenewalGraceDays, 1)
+}
+
+// translateCertificateAlert translates the alerting on the expiry of the certificate into the config of
+// providers that alert through the certificate settings of their monitors
+func translateCertificateAlert(certificate *endpointmonitorv1alpha1.CertificateStatus, monitorService *monitors.MonitorServiceProxy) *endpointmonitorv1alpha1.MonitorProviderDefaults {
+	if certificate == nil || monitorService.SupportsCertificateMonitors() {
+		return nil
+	}
+	return monitorService.TranslateCertificateAlert(certificate.AlertDaysBefore)
+}
+
+// reconcileCertificateMonitor alerts on the expiry of the certificate of spec.tlsCertificate through the
+// dedicated certificate monitor of providers that have them, and records the certificate in the status.
+// The certificate monitor is removed together with spec.tlsCertificate.
+func (r *EndpointMonitorReconciler) reconcileCertificateMonitor(instance *endpointmonitorv1alpha1.EndpointMonitor, spec endpointmonitorv1alpha1.EndpointMonitorSpec, monitorName string, certificate *endpointmonitorv1alpha1.CertificateStatus, certificateErr error, monitorService *monitors.MonitorServiceProxy) error {
+	status := &instance.Status
+	previous := status.Certificate.DeepCopy()
+
+	if instance.Spec.TLSCertificate == nil {
+		if previous == nil {
+			return nil
+		}
+		if err := removeCertificateMonitor(instance, monitorService); err != nil {
+			return err
+		}
+		status.Certificate = nil
+		meta.RemoveStatusCondition(&status.Conditions, endpointmonitorv1alpha1.ConditionTypeCertificateMonitored)
+		return r.Status().Update(context.TODO(), instance)
+	}
+
+	condition := metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeCertificateMonitored,
+		ObservedGeneration: instance.Generation,
+	}
+	switch {
+	case certificateErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = endpointmonitorv1alpha1.ReasonCertificateNotFound
+		condition.Message = certificateErr.Error()
+		// Keep the certificate monitor and the last known expiry until the certificate is back
+		certificate = previous
+	case monitorService.SupportsCertificateMonitors():
+		var id string
+		if previous != nil {
+			id = previous.MonitorID
+		}
+		url, err := util.GetMonitorURL(r.Client, r.apiReader(), instance)
+		if err != nil {
+			return err
+		}
+		monitor := models.Monitor{Name: monitorName, URL: url, Config: monitorService.ExtractConfig(spec), Labels: instance.Labels}
+		if certificate.MonitorID, err = monitorService.EnsureCertificateMonitor(monitor, id, certificate.AlertDaysBefore); err != nil {
+			return err
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = endpointmonitorv1alpha1.ReasonCertificateMonitored
+		condition.Message = fmt.Sprintf("Certificate monitor %s alerts %d days before the expiry", certificate.MonitorID, certificate.AlertDaysBefore)
+	case monitorService.TranslateCertificateAlert(certificate.AlertDaysBefore) != nil:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = endpointmonitorv1alpha1.ReasonCertificateMonitored
+		condition.Message = fmt.Sprintf("Monitor %s alerts %d days before the expiry", monitorName, certificate.AlertDaysBefore)
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = endpointmonitorv1alpha1.ReasonCertificateAlertsUnsupported
+		condition.Message = "Provider " + monitorService.GetType() + " can't alert on the expiry of certificates"
+	}
+
+	changed := meta.SetStatusCondition(&status.Conditions, condition)
+	if !changed && equalCertificateStatus(previous, certificate) {
+		return nil
+	}
+	status.Certificate = certificate
+	return r.Status().Update(context.TODO(), instance)
+}
+
+// removeCertificateMonitor deletes the certificate monitor recorded in the status of the EndpointMonitor
+func removeCertificateMonitor(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorService *monitors.MonitorServiceProxy) error {
+	if instance.Status.Certificate == nil || len(instance.Status.Certificate.MonitorID) == 0 {
+		return nil
+	}
+	err := monitorService.RemoveCertificateMonitor(instance.Status.Certificate.MonitorID)
+	if errors.Is(err, monitors.ErrCertificateMonitorsNotSupported) {
+		return nil
+	}
+	return err
+}
+
+func equalCertificateStatus(a, b *endpointmonitorv1alpha1.CertificateStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if (a.NotAfter == nil) != (b.NotAfter == nil) || (a.NotAfter != nil && !a.NotAfter.Equal(b.NotAfter)) {
+		return false
+	}
+	return a.SecretName == b.SecretName && a.AlertDaysBefore == b.AlertDaysBefore && a.MonitorID == b.MonitorID
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func newTLSSecret(t *testing.T, name string, notAfter time.Time) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: notAfter.Add(-90 * 24 * time.Hour), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+}
+
+func TestCertificateAlertDays(t *testing.T) {
+	certificate := func(spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	}
+	assert.Equal(t, certificateAlertDays(nil), 28)
+	assert.Equal(t, certificateAlertDays(certificate(map[string]interface{}{"renewBefore": "360h"})), 13)
+	assert.Equal(t, certificateAlertDays(certificate(map[string]interface{}{"duration": "720h"})), 8)
+	assert.Equal(t, certificateAlertDays(certificate(map[string]interface{}{"renewBefore": "24h"})), 1)
+}
+
+func TestGetCertificateOfIngress(t *testing.T) {
+	notAfter := time.Now().Add(60 * 24 * time.Hour).Truncate(time.Second).UTC()
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{Hosts: []string{"checkout.example.com"}, SecretName: "checkout-tls"}},
+		},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			TLSCertificate: &endpointmonitorv1alpha1.TLSCertificateSource{
+				IngressRef: &endpointmonitorv1alpha1.IngressURLSource{Name: "checkout"},
+			},
+		},
+	}
+
+	// The Secret has not been issued yet
+	r := newProviderTestReconciler(t, nil, ingress)
+	certificate, notFound, err := r.lookupCertificate(instance)
+	assert.NilError(t, err)
+	assert.Assert(t, certificate == nil)
+	assert.Assert(t, errors.Is(notFound, errCertificateNotFound))
+
+	r = newProviderTestReconciler(t, nil, ingress, newTLSSecret(t, "checkout-tls", notAfter))
+	certificate, notFound, err = r.lookupCertificate(instance)
+	assert.NilError(t, err)
+	assert.NilError(t, notFound)
+	assert.Equal(t, certificate.SecretName, "checkout-tls")
+	assert.Assert(t, certificate.NotAfter.Time.Equal(notAfter))
+	assert.Equal(t, certificate.AlertDaysBefore, 28)
+}
+
+func TestReconcileCertificateMonitorThroughProviderConfig(t *testing.T) {
+	pingdom := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypePingdom})
+	uptimeRobot := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeUptimeRobot})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			URL: "https://checkout.example.com",
+			TLSCertificate: &endpointmonitorv1alpha1.TLSCertificateSource{
+				CertificateRef:  &endpointmonitorv1alpha1.CertificateReference{Name: "checkout"},
+				AlertDaysBefore: 14,
+			},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, instance)
+	certificate := &endpointmonitorv1alpha1.CertificateStatus{
+		SecretName:      "checkout-tls",
+		NotAfter:        &metav1.Time{Time: time.Now().Add(60 * 24 * time.Hour).Truncate(time.Second)},
+		AlertDaysBefore: 14,
+	}
+
+	// Pingdom alerts through the certificate settings of the check
+	defaults := translateCertificateAlert(certificate, pingdom)
+	assert.Equal(t, defaults.PingdomConfig.SSLDownDaysBefore, 14)
+	assert.Assert(t, defaults.PingdomConfig.VerifyCertificate)
+	spec, _, err := r.getEffectiveSpec(instance, []*endpointmonitorv1alpha1.MonitorProviderDefaults{defaults}, nil, pingdom.GetType())
+	assert.NilError(t, err)
+	assert.Equal(t, spec.PingdomConfig.SSLDownDaysBefore, 14)
+
+	assert.NilError(t, r.reconcileCertificateMonitor(instance, spec, "checkout", certificate, nil, pingdom))
+	assert.Equal(t, instance.Status.Certificate.SecretName, "checkout-tls")
+	condition := meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeCertificateMonitored)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+	assert.Equal(t, condition.Reason, endpointmonitorv1alpha1.ReasonCertificateMonitored)
+
+	// UptimeRobot can't alert on certificates, the expiry is still recorded
+	assert.Assert(t, translateCertificateAlert(certificate, uptimeRobot) == nil)
+	assert.NilError(t, r.reconcileCertificateMonitor(instance, spec, "checkout", certificate, nil, uptimeRobot))
+	condition = meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeCertificateMonitored)
+	assert.Equal(t, condition.Reason, endpointmonitorv1alpha1.ReasonCertificateAlertsUnsupported)
+	assert.Assert(t, instance.Status.Certificate.NotAfter != nil)
+
+	// Both go away with spec.tlsCertificate
+	instance.Spec.TLSCertificate = nil
+	assert.NilError(t, r.reconcileCertificateMonitor(instance, spec, "checkout", nil, nil, uptimeRobot))
+	assert.Assert(t, instance.Status.Certificate == nil)
+	assert.Assert(t, meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeCertificateMonitored) == nil)
+}
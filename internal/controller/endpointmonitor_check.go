@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+// translateCheck translates spec.check of the EndpointMonitor into the config of its provider, returned as
+// defaults for the provider config of the spec, along with the fields of the check the provider can't express
+func translateCheck(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorService *monitors.MonitorServiceProxy) (*endpointmonitorv1alpha1.MonitorProviderDefaults, []string) {
+	if instance.Spec.Check == nil {
+		return nil, nil
+	}
+	return monitorService.TranslateCheck(instance.Spec.Check)
+}
+
+// setCheckSupported records in the conditions of the EndpointMonitor which fields of spec.check are ignored
+// by the provider, and returns whether the conditions changed
+func setCheckSupported(instance *endpointmonitorv1alpha1.EndpointMonitor, unsupported []string, monitorService *monitors.MonitorServiceProxy) bool {
+	conditions := &instance.Status.Conditions
+	if instance.Spec.Check == nil {
+		return meta.RemoveStatusCondition(conditions, endpointmonitorv1alpha1.ConditionTypeCheckSupported)
+	}
+	if len(unsupported) == 0 {
+		return meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               endpointmonitorv1alpha1.ConditionTypeCheckSupported,
+			Status:             metav1.ConditionTrue,
+			Reason:             endpointmonitorv1alpha1.ReasonAllFieldsSupported,
+			Message:            "All fields of the check are supported by provider " + monitorService.GetType(),
+			ObservedGeneration: instance.Generation,
+		})
+	}
+	return meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeCheckSupported,
+		Status:             metav1.ConditionFalse,
+		Reason:             endpointmonitorv1alpha1.ReasonUnsupportedFields,
+		Message:            "Fields of the check ignored by provider " + monitorService.GetType() + ": " + strings.Join(unsupported, "; "),
+		ObservedGeneration: instance.Generation,
+	})
+}
+
+// getCheckType returns the checkType of the EndpointMonitor, HTTP when it is not set
+func getCheckType(instance *endpointmonitorv1alpha1.EndpointMonitor) endpointmonitorv1alpha1.CheckType {
+	if len(instance.Spec.CheckType) == 0 {
+		return endpointmonitorv1alpha1.CheckTypeHTTP
+	}
+	return instance.Spec.CheckType
+}
+
+// setCheckTypeSupported records in the conditions of the EndpointMonitor whether the provider can run its
+// checkType, and returns whether the conditions changed. HTTP checks get no condition on the providers running them.
+func setCheckTypeSupported(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorService *monitors.MonitorServiceProxy) bool {
+	conditions := &instance.Status.Conditions
+	checkType := getCheckType(instance)
+	if checkType == endpointmonitorv1alpha1.CheckTypeHTTP && monitorService.SupportsCheckType(checkType) {
+		return meta.RemoveStatusCondition(conditions, endpointmonitorv1alpha1.ConditionTypeCheckTypeSupported)
+	}
+	if monitorService.SupportsCheckType(checkType) {
+		return meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               endpointmonitorv1alpha1.ConditionTypeCheckTypeSupported,
+			Status:             metav1.ConditionTrue,
+			Reason:             endpointmonitorv1alpha1.ReasonCheckTypeSupported,
+			Message:            string(checkType) + " checks are supported by provider " + monitorService.GetType(),
+			ObservedGeneration: instance.Generation,
+		})
+	}
+	return meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeCheckTypeSupported,
+		Status:             metav1.ConditionFalse,
+		Reason:             endpointmonitorv1alpha1.ReasonUnsupportedCheckType,
+		Message:            string(checkType) + " checks are not supported by provider " + monitorService.GetType() + ", no monitor is created",
+		ObservedGeneration: instance.Generation,
+	})
+}
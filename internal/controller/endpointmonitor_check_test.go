@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestTranslateCheckUnderProviderConfig(t *testing.T) {
+	monitorService := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeUptimeRobot})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			Check: &endpointmonitorv1alpha1.Check{
+				Interval:       300,
+				Method:         "HEAD",
+				BodyAssertions: []endpointmonitorv1alpha1.BodyAssertion{{Type: endpointmonitorv1alpha1.BodyAssertionContains, Value: "ok"}},
+			},
+			// The provider config takes precedence over the check
+			UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{Interval: 60},
+		},
+	}
+	r := newProviderTestReconciler(t, nil)
+
+	checkConfig, unsupported := translateCheck(instance, monitorService)
+	assert.DeepEqual(t, unsupported, []string{"method"})
+	spec, _, err := r.getEffectiveSpec(instance, []*endpointmonitorv1alpha1.MonitorProviderDefaults{checkConfig}, nil, monitorService.GetType())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *spec.UptimeRobotConfig, endpointmonitorv1alpha1.UptimeRobotConfig{
+		Interval:      60,
+		MonitorType:   "keyword",
+		KeywordValue:  "ok",
+		KeywordExists: "no",
+	})
+
+	assert.Assert(t, setCheckSupported(instance, unsupported, monitorService))
+	condition := meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeCheckSupported)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Message, "Fields of the check ignored by provider UptimeRobot: method")
+
+	// The condition goes away with the check
+	instance.Spec.Check = nil
+	assert.Assert(t, setCheckSupported(instance, nil, monitorService))
+	assert.Assert(t, meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeCheckSupported) == nil)
+}
+
+func TestSetCheckTypeSupported(t *testing.T) {
+	uptimeRobot := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeUptimeRobot})
+	updown := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeUpdown})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			URL:       "tcp://db.example.com:5432",
+			CheckType: endpointmonitorv1alpha1.CheckTypeTCP,
+		},
+	}
+
+	assert.Assert(t, setCheckTypeSupported(instance, uptimeRobot))
+	condition := meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeCheckTypeSupported)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+
+	// Updown only runs HTTP checks
+	assert.Assert(t, !updown.SupportsCheckType(endpointmonitorv1alpha1.CheckTypeTCP))
+	assert.Assert(t, setCheckTypeSupported(instance, updown))
+	condition = meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeCheckTypeSupported)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, endpointmonitorv1alpha1.ReasonUnsupportedCheckType)
+
+	// HTTP checks run everywhere and carry no condition
+	instance.Spec.CheckType = ""
+	assert.Assert(t, setCheckTypeSupported(instance, updown))
+	assert.Assert(t, meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeCheckTypeSupported) == nil)
+}
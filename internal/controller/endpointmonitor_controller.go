@@ -19,18 +19,25 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
-	"github.com/stakater/IngressMonitorController/v2/pkg/util"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
@@ -38,21 +45,62 @@ import (
 
 var log = logf.Log.WithName("endpointmonitor-controller")
 
+const monitorNameIndexField = "status.monitorName"
+
+// providerNotReadyRequeueTime is how long EndpointMonitors wait for their provider to be set up again
+const providerNotReadyRequeueTime = 30 * time.Second
+
 // EndpointMonitorReconciler reconciles a EndpointMonitor object
 type EndpointMonitorReconciler struct {
 	client.Client
 	Log             logr.Logger
 	Scheme          *runtime.Scheme
 	MonitorServices []*monitors.MonitorServiceProxy
+
+	// APIReader reads Secrets referenced by EndpointMonitors without caching them, the Client is used when unset
+	APIReader client.Reader
+
+	// NamespaceSelector limits the handled EndpointMonitors to the namespaces matching it, all namespaces when nil
+	NamespaceSelector labels.Selector
+
+	// EndpointMonitorSelector limits the handled EndpointMonitors to those matching it, all of them when nil
+	EndpointMonitorSelector labels.Selector
+
+	// ClusterScoped is set when the controller watches all namespaces, ClusterMonitorProviders are only watched then
+	ClusterScoped bool
+
+	// renameLimiter throttles monitor renames across all EndpointMonitors
+	renameLimiter *rate.Limiter
+
+	// monitorRecords remembers the monitor name and provider account of each EndpointMonitor, so
+	// that the monitor can still be found once the EndpointMonitor has been deleted
+	monitorRecords sync.Map
+
+	// deletionPolicies remembers the deletion policy of each EndpointMonitor for the same reason, see handleDelete
+	deletionPolicies sync.Map
+
+	// providerServices holds the monitor services set up for MonitorProviders and ClusterMonitorProviders by their ID
+	providerServices sync.Map
 }
 
-//+kubebuilder:rbac:groups=endpointmonitor.stakater.com,resources=endpointmonitors,verbs=get;list;watch
+//+kubebuilder:rbac:groups=endpointmonitor.stakater.com,resources=endpointmonitors,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=endpointmonitor.stakater.com,resources=endpointmonitors/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=endpointmonitor.stakater.com,resources=endpointmonitors/finalizers,verbs=update
 //+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=endpointmonitor.stakater.com,resources=monitorproviders;clustermonitorproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=endpointmonitor.stakater.com,resources=endpointmonitortemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=list
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=probes,verbs=get;list;create;update;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;create;update;delete
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -62,102 +110,343 @@ func (r *EndpointMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	// Fetch the EndpointMonitor instance
 	instance := &endpointmonitorv1alpha1.EndpointMonitor{}
 
-	var monitorName string
-	format, err := util.GetNameTemplateFormat(config.GetControllerConfig().MonitorNameTemplate)
-	if err != nil {
-		log.Error(err, "Failed to parse MonitorNameTemplate, using default template `{{.Name}}-{{.Namespace}}`")
-		monitorName = req.Name + "-" + req.Namespace
-	} else {
-		monitorName = fmt.Sprintf(format, req.Name, req.Namespace)
-	}
-
-	err = r.Get(context.TODO(), req.NamespacedName, instance)
+	err := r.Get(context.TODO(), req.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
-			return r.handleDelete(req, instance, monitorName)
+			uncached, err := r.getUncachedEndpointMonitor(req)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if uncached != nil {
+				log.Info("EndpointMonitor no longer matches the selectors of this controller, releasing it")
+				return r.releaseOutOfScope(req, uncached)
+			}
+			inScope, err := r.isDeletedInScope(req)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if !inScope {
+				log.Info("EndpointMonitor is no longer handled by this controller, skipping deletion of its monitor")
+				r.monitorRecords.Delete(req.NamespacedName)
+				return reconcile.Result{}, nil
+			}
+			return r.handleDelete(req, instance)
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
+	if !instance.DeletionTimestamp.IsZero() {
+		// Nothing but the EndpointMonitor itself is needed, its ingress or Secrets may be gone already. It is
+		// finalized even when it left the scope, as no other controller instance removes the finalizer.
+		return r.handleFinalize(req, instance)
+	}
+
+	inScope, err := r.isInScope(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !inScope {
+		log.Info("EndpointMonitor does not match the selectors of this controller, skipping")
+		r.monitorRecords.Delete(req.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+	if err := r.reconcileFinalizer(req, instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	monitorService, defaults, err := r.getMonitorService(instance)
+	if err != nil {
+		log.Error(err, "Failed to select the provider")
+		return reconcile.Result{}, err
+	}
+	if err := monitorService.Healthy(); err != nil {
+		log.Info("Provider is not ready, requeuing", "error", err.Error())
+		return reconcile.Result{RequeueAfter: providerNotReadyRequeueTime}, r.setDegraded(instance, err)
+	}
+
+	// Merge spec.check, the EndpointMonitorTemplates and the defaults of the provider into a copy of the spec
+	checkConfig, unsupportedCheckFields := translateCheck(instance, monitorService)
+	certificate, certificateNotFound, err := r.lookupCertificate(instance)
+	if err != nil {
+		log.Error(err, "Failed to read the certificate")
+		return reconcile.Result{}, err
+	}
+	derivedConfigs := []*endpointmonitorv1alpha1.MonitorProviderDefaults{checkConfig, translateCertificateAlert(certificate, monitorService)}
+	effectiveSpec, appliedTemplates, err := r.getEffectiveSpec(instance, derivedConfigs, defaults, monitorService.GetType())
+	if err != nil {
+		log.Error(err, "Failed to merge the EndpointMonitorTemplates")
+		return reconcile.Result{}, err
+	}
+	if err := r.updateEffectiveConfig(instance, effectiveSpec, appliedTemplates, unsupportedCheckFields, monitorService); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Resolve the values referenced from Secrets, which must not end up in the status
+	effective := instance.DeepCopy()
+	effective.Spec = effectiveSpec
+	spec, err := r.resolveSecretRefs(effective)
+	if err != nil {
+		log.Error(err, "Failed to resolve secret references")
+		return reconcile.Result{}, err
+	}
+	monitorName, err := r.getMonitorName(instance, monitorService)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	// The monitor is shown under its display name in the provider
+	displayName := getDisplayName(instance, monitorName, monitorService)
+
+	// Never touch a monitor owned by another EndpointMonitor
+	owner, err := r.findNameConflict(instance, monitorName, monitorService)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if owner != nil {
+		log.Info("Monitor name "+monitorName+" is already used by another EndpointMonitor, skipping", "owner", owner.Namespace+"/"+owner.Name)
+		return reconcile.Result{RequeueAfter: config.ReconciliationRequeueTime}, r.setNameConflict(instance, monitorName, owner)
+	}
+
+	// The CheckTypeSupported condition explains why no monitor is created
+	if !monitorService.SupportsCheckType(getCheckType(instance)) {
+		log.Info(string(getCheckType(instance)) + " checks are not supported by provider " + monitorService.GetType() + ", skipping")
+		return reconcile.Result{}, nil
+	}
+
+	// Claim the name before the monitor is created or renamed, EndpointMonitors claiming it at the same time see each other
+	if err := r.claimMonitorName(instance, monitorName, monitorService); err != nil {
+		return reconcile.Result{}, err
+	}
+	r.rememberMonitor(req, instance, displayName)
+
 	// Handle CreationDelay
 	createTime := instance.CreationTimestamp
 	delay := time.Until(createTime.Add(config.GetControllerConfig().CreationDelay))
 
-	monitorService := r.GetMonitorOfType(instance.Spec)
-	monitor, err := findMonitorByName(monitorService, monitorName)
-	if err != nil {
-		return reconcile.Result{}, err
+	var monitor *models.Monitor
+	if instance.Spec.Adopt != nil {
+		var failure *adoptionFailure
+		monitor, failure, err = r.findAdoptedMonitor(instance, displayName, monitorService)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if failure != nil {
+			log.Info("No monitor is adopted: " + failure.message)
+			return reconcile.Result{RequeueAfter: config.ReconciliationRequeueTime}, r.setAdoptionFailed(instance, failure)
+		}
+	} else {
+		monitor, err = findMonitorByName(monitorService, displayName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if monitor != nil {
+			// Another EndpointMonitor may show its monitor under the same display name
+			owner, err := r.findMonitorIDOwner(instance, monitor.ID, monitorService)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if owner != nil {
+				log.Info("Monitor "+displayName+" belongs to another EndpointMonitor, skipping", "owner", owner.Namespace+"/"+owner.Name)
+				return reconcile.Result{RequeueAfter: config.ReconciliationRequeueTime}, r.setNameConflict(instance, displayName, owner)
+			}
+		} else {
+			// The desired name may have changed, look for the monitor created previously
+			monitor, err = r.findRenamedMonitor(instance, monitorService)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
 	}
 	if monitor != nil {
+		if monitor.Name != displayName {
+			if delay := r.reserveRename(); delay > 0 {
+				log.Info("Rename of monitor "+monitor.Name+" to "+displayName+" is throttled, requeuing", "after", delay)
+				return reconcile.Result{RequeueAfter: delay}, nil
+			}
+			log.Info("Renaming monitor " + monitor.Name + " to " + displayName)
+		}
 		// Monitor already exists, update if required
-		err = r.handleUpdate(req, instance, *monitor, monitorService)
+		err = r.handleUpdate(req, instance, spec, displayName, *monitor, monitorService)
 	} else {
 		// Monitor doesn't exist, create monitor
 		if delay.Nanoseconds() > 0 {
 			// Requeue request to add creation delay
-			log.Info("Requeuing request to add monitor " + monitorName + " for " + fmt.Sprintf("%+v", config.GetControllerConfig().CreationDelay) + " seconds")
+			log.Info("Requeuing request to add monitor " + displayName + " for " + fmt.Sprintf("%+v", config.GetControllerConfig().CreationDelay) + " seconds")
 			return reconcile.Result{RequeueAfter: delay}, nil
 		}
-		err = r.handleCreate(req, instance, monitorName, monitorService)
+		// Hold the creation until the endpoint is live
+		if requeueAfter, err := r.gateOnEndpointReadiness(instance, certificateNotFound); err != nil || requeueAfter > 0 {
+			return reconcile.Result{RequeueAfter: requeueAfter}, err
+		}
+		err = r.handleCreate(req, instance, spec, displayName, monitorService)
+	}
+	// The monitor ID is known once the monitor has been created
+	r.rememberMonitor(req, instance, displayName)
+	if err == nil {
+		err = r.reconcileCertificateMonitor(instance, spec, displayName, certificate, certificateNotFound, monitorService)
+	}
+	if err == nil {
+		err = r.reconcileAlertRule(instance, spec, displayName, monitorService)
 	}
-	return reconcile.Result{RequeueAfter: config.ReconciliationRequeueTime}, err
+	requeueAfter := config.ReconciliationRequeueTime
+	if err == nil {
+		var interval time.Duration
+		if interval, err = r.updateProbeStatus(instance, displayName, monitorService); interval > 0 {
+			requeueAfter = min(requeueAfter, interval)
+		}
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, err
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *EndpointMonitorReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	r.renameLimiter = newRenameLimiter(config.GetControllerConfig().MonitorRename)
+
+	// Index EndpointMonitors by the monitor name recorded in their status to detect name conflicts
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &endpointmonitorv1alpha1.EndpointMonitor{}, monitorNameIndexField, monitorNameIndexer); err != nil {
+		return err
+	}
+
+	// Index EndpointMonitors by the monitor ID recorded in their status to detect adoption conflicts
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &endpointmonitorv1alpha1.EndpointMonitor{}, monitorIDIndexField, monitorIDIndexer); err != nil {
+		return err
+	}
+
+	// Index EndpointMonitors by the Secrets they reference to reconcile them when a Secret changes
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &endpointmonitorv1alpha1.EndpointMonitor{}, secretRefIndexField, secretRefIndexer); err != nil {
+		return err
+	}
+
+	// Index EndpointMonitors by the MonitorProvider or ClusterMonitorProvider they reference to reconcile them when it
+	// changes
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &endpointmonitorv1alpha1.EndpointMonitor{}, providerRefIndexField, providerRefIndexer); err != nil {
+		return err
+	}
+
+	var forOptions []builder.ForOption
+	if r.EndpointMonitorSelector != nil {
+		// The cache is usually filtered by the selector already, the predicate keeps it correct when it is not
+		forOptions = append(forOptions, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return r.EndpointMonitorSelector.Matches(labels.Set(obj.GetLabels()))
+		})))
+	}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles,
 		}).
-		For(&endpointmonitorv1alpha1.EndpointMonitor{}).
-		Complete(r)
+		For(&endpointmonitorv1alpha1.EndpointMonitor{}, forOptions...).
+		// Only the metadata of Secrets is cached, their data is read through the APIReader
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.endpointMonitorsForSecret)).
+		Watches(&endpointmonitorv1alpha1.MonitorProvider{}, handler.EnqueueRequestsFromMapFunc(r.endpointMonitorsForProvider)).
+		Watches(&endpointmonitorv1alpha1.EndpointMonitorTemplate{}, handler.EnqueueRequestsFromMapFunc(r.endpointMonitorsForTemplate))
+	if r.ClusterScoped {
+		controllerBuilder = controllerBuilder.Watches(&endpointmonitorv1alpha1.ClusterMonitorProvider{}, handler.EnqueueRequestsFromMapFunc(r.endpointMonitorsForProvider))
+	}
+	if r.NamespaceSelector != nil {
+		// Namespaces opt in and out by their labels
+		controllerBuilder = controllerBuilder.Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.endpointMonitorsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}))
+	}
+	return controllerBuilder.Complete(r)
 }
 
-func (r *EndpointMonitorReconciler) GetMonitorOfType(spec endpointmonitorv1alpha1.EndpointMonitorSpec) *monitors.MonitorServiceProxy {
+// GetMonitorOfType returns the account selected through spec.providerID, or the default account of
+// the provider type whose config is set on the spec
+func (r *EndpointMonitorReconciler) GetMonitorOfType(spec endpointmonitorv1alpha1.EndpointMonitorSpec) (*monitors.MonitorServiceProxy, error) {
 	if len(r.MonitorServices) == 0 {
-		panic("No monitor services found")
+		return nil, fmt.Errorf("no providers found in the controller config, set spec.providerRef to use a MonitorProvider")
 	}
-	if spec.PingdomTransactionConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypePingdomTransaction)
-	}
-	if spec.PingdomConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypePingdom)
-	}
-	if spec.UptimeRobotConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypeUptimeRobot)
-	}
-	if spec.StatusCakeConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypeStatusCake)
+	monitorType := getMonitorTypeOfSpec(spec)
+
+	if len(spec.ProviderID) > 0 {
+		monitorService := r.GetMonitorServiceWithID(spec.ProviderID)
+		if monitorService == nil {
+			return nil, fmt.Errorf("provider %s not found in the controller config", spec.ProviderID)
+		}
+		if len(monitorType) > 0 && monitorService.GetType() != monitorType {
+			return nil, fmt.Errorf("provider %s is of type %s but the EndpointMonitor configures %s", spec.ProviderID, monitorService.GetType(), monitorType)
+		}
+		return monitorService, nil
 	}
-	if spec.UptimeConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypeUptime)
+
+	// If no provider config is set, use the first monitor service
+	if len(monitorType) == 0 {
+		return r.MonitorServices[0], nil
 	}
-	if spec.UpdownConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypeUpdown)
+	monitorService := r.GetMonitorServiceOfType(monitorType)
+	if monitorService == nil {
+		return nil, fmt.Errorf("no provider of type %s found in the controller config", monitorType)
 	}
-	if spec.AppInsightsConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypeAppInsights)
+	return monitorService, nil
+}
+
+// getMonitorTypeOfSpec returns the provider type whose config is set on the spec, or an empty string
+func getMonitorTypeOfSpec(spec endpointmonitorv1alpha1.EndpointMonitorSpec) string {
+	switch {
+	case spec.PingdomTransactionConfig != nil:
+		return monitors.TypePingdomTransaction
+	case spec.PingdomConfig != nil:
+		return monitors.TypePingdom
+	case spec.UptimeRobotConfig != nil:
+		return monitors.TypeUptimeRobot
+	case spec.StatusCakeConfig != nil:
+		return monitors.TypeStatusCake
+	case spec.UptimeConfig != nil:
+		return monitors.TypeUptime
+	case spec.UpdownConfig != nil:
+		return monitors.TypeUpdown
+	case spec.AppInsightsConfig != nil:
+		return monitors.TypeAppInsights
+	case spec.GCloudConfig != nil:
+		return monitors.TypeGCloud
+	case spec.GrafanaConfig != nil:
+		return monitors.TypeGrafana
+	case spec.PluginConfig != nil:
+		return monitors.TypePlugin
+	case spec.WebhookConfig != nil:
+		return monitors.TypeWebhook
+	case spec.HealthchecksConfig != nil:
+		return monitors.TypeHealthchecks
+	case spec.LocalConfig != nil:
+		return monitors.TypeLocal
+	case spec.BlackboxConfig != nil:
+		return monitors.TypeBlackbox
+	case spec.BetterStackConfig != nil:
+		return monitors.TypeBetterStack
 	}
-	if spec.GCloudConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypeGCloud)
+	return ""
+}
+
+// GetMonitorServiceOfType returns the default account of the provider type
+func (r *EndpointMonitorReconciler) GetMonitorServiceOfType(monitorType string) *monitors.MonitorServiceProxy {
+	var found *monitors.MonitorServiceProxy
+	for _, monitorService := range r.MonitorServices {
+		if monitorService.GetType() != monitorType {
+			continue
+		}
+		if monitorService.IsDefault() {
+			return monitorService
+		}
+		if found == nil {
+			found = monitorService
+		}
 	}
-	if spec.GrafanaConfig != nil {
-		return r.GetMonitorServiceOfType(monitors.TypeGrafana)
+	if found == nil {
+		log.Info("Error could not find monitor service " + monitorType + " in list of monitor services")
 	}
-	// If none of the above, return the first monitor service
-	return r.MonitorServices[0]
+	return found
 }
 
-func (r *EndpointMonitorReconciler) GetMonitorServiceOfType(monitorType string) *monitors.MonitorServiceProxy {
+// GetMonitorServiceWithID returns the account with the given ID
+func (r *EndpointMonitorReconciler) GetMonitorServiceWithID(id string) *monitors.MonitorServiceProxy {
 	for _, monitorService := range r.MonitorServices {
-		if monitorService.GetType() == monitorType {
+		if monitorService.GetID() == id {
 			return monitorService
 		}
 	}
-	log.Info("Error could not find monitor service " + monitorType + " in list of monitor services")
 	return nil
 }
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestGetMonitorOfTypeSelectsAccount(t *testing.T) {
+	monitorServices, err := monitors.SetupMonitorServicesForProviders([]config.Provider{
+		{Name: monitors.TypeUptimeRobot, ID: "retail"},
+		{Name: monitors.TypeUptimeRobot, ID: "payments", Default: true},
+		{Name: monitors.TypeStatusCake, ID: "retail-statuscake"},
+	})
+	assert.NilError(t, err)
+	r := &EndpointMonitorReconciler{MonitorServices: monitorServices}
+
+	monitorService, err := r.GetMonitorOfType(endpointmonitorv1alpha1.EndpointMonitorSpec{
+		UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, monitorService.GetID(), "payments")
+
+	monitorService, err = r.GetMonitorOfType(endpointmonitorv1alpha1.EndpointMonitorSpec{
+		ProviderID:        "retail",
+		UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, monitorService.GetID(), "retail")
+
+	monitorService, err = r.GetMonitorOfType(endpointmonitorv1alpha1.EndpointMonitorSpec{ProviderID: "retail-statuscake"})
+	assert.NilError(t, err)
+	assert.Equal(t, monitorService.GetType(), monitors.TypeStatusCake)
+
+	_, err = r.GetMonitorOfType(endpointmonitorv1alpha1.EndpointMonitorSpec{
+		ProviderID:       "retail",
+		StatusCakeConfig: &endpointmonitorv1alpha1.StatusCakeConfig{},
+	})
+	assert.ErrorContains(t, err, "is of type UptimeRobot")
+
+	_, err = r.GetMonitorOfType(endpointmonitorv1alpha1.EndpointMonitorSpec{ProviderID: "unknown"})
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = r.GetMonitorOfType(endpointmonitorv1alpha1.EndpointMonitorSpec{PingdomConfig: &endpointmonitorv1alpha1.PingdomConfig{}})
+	assert.ErrorContains(t, err, "no provider of type Pingdom")
+}
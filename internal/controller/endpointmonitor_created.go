@@ -9,24 +9,43 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-func (r *EndpointMonitorReconciler) handleCreate(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, monitorService *monitors.MonitorServiceProxy) error {
+func (r *EndpointMonitorReconciler) handleCreate(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor, spec endpointmonitorv1alpha1.EndpointMonitorSpec, monitorName string, monitorService *monitors.MonitorServiceProxy) error {
 	log := r.Log.WithValues("Namespace", instance.ObjectMeta.Namespace)
 
 	log.Info("Creating Monitor: "+monitorName, "MonitorType", monitorService.GetType())
 
-	url, err := util.GetMonitorURL(r.Client, instance)
+	url, err := util.GetMonitorURL(r.Client, r.apiReader(), instance)
 	if err != nil {
 		return err
 	}
 
 	// Extract provider specific configuration
-	providerConfig := monitorService.ExtractConfig(instance.Spec)
+	providerConfig := monitorService.ExtractConfig(spec)
 
 	// Create monitor Model
-	monitor := models.Monitor{Name: monitorName, URL: url, Config: providerConfig}
+	monitor := models.Monitor{Name: monitorName, URL: url, Config: providerConfig, Labels: instance.Labels, GRPC: spec.GRPC}
+	if monitor.Heartbeat, err = r.getHeartbeat(instance); err != nil {
+		return err
+	}
 
 	// Add monitor for provider
 	monitorService.Add(monitor)
 
-	return nil
+	// Record the ID assigned by the provider so the monitor can be renamed later on
+	createdMonitor, err := monitorService.GetByName(monitorName)
+	if err != nil {
+		log.Error(err, "Failed to get created monitor: "+monitorName)
+		return nil
+	}
+	if createdMonitor == nil {
+		return nil
+	}
+	if err := r.publishPingURL(instance, createdMonitor.URL); err != nil {
+		return err
+	}
+	monitor.ID = createdMonitor.ID
+	if err := r.publishOutputs(instance, monitor, createdMonitor.URL, monitorService); err != nil {
+		return err
+	}
+	return r.updateMonitorStatus(instance, *createdMonitor, monitorService)
 }
@@ -1,58 +1,282 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+
 	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
 	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
 
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-func (r *EndpointMonitorReconciler) handleDelete(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string) (reconcile.Result, error) {
+// monitorCleanupFinalizer holds back the deletion of EndpointMonitors whose monitor is deleted or
+// paused with them until their deletion policy has been applied
+const monitorCleanupFinalizer = "endpointmonitor.stakater.com/monitor-cleanup"
+
+// getDeletionPolicy returns the deletion policy of an EndpointMonitor, which defaults to the
+// enableMonitorDeletion setting of the controller config
+func getDeletionPolicy(instance *endpointmonitorv1alpha1.EndpointMonitor) endpointmonitorv1alpha1.DeletionPolicy {
+	if len(instance.Spec.DeletionPolicy) > 0 {
+		return instance.Spec.DeletionPolicy
+	}
+	if config.GetControllerConfig().EnableMonitorDeletion {
+		return endpointmonitorv1alpha1.DeletionPolicyDelete
+	}
+	return endpointmonitorv1alpha1.DeletionPolicyRetain
+}
+
+// reconcileFinalizer adds the finalizer to EndpointMonitors whose monitor is deleted or paused with
+// them and removes it from the others. The deletion policy is remembered for EndpointMonitors that are
+// deleted without the finalizer.
+func (r *EndpointMonitorReconciler) reconcileFinalizer(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor) error {
+	policy := getDeletionPolicy(instance)
+	r.deletionPolicies.Store(request.NamespacedName, policy)
+
+	var changed bool
+	if policy == endpointmonitorv1alpha1.DeletionPolicyRetain {
+		changed = controllerutil.RemoveFinalizer(instance, monitorCleanupFinalizer)
+	} else {
+		changed = controllerutil.AddFinalizer(instance, monitorCleanupFinalizer)
+	}
+	if !changed {
+		return nil
+	}
+	return r.Update(context.TODO(), instance)
+}
+
+// monitorRecord is what is known about the monitor of an EndpointMonitor to clean it up: the monitor name
+// claimed in its status, the name the monitor is shown under and the provider account holding it
+type monitorRecord struct {
+	monitorName string
+	displayName string
+	provider    string
+	providerID  string
+	monitorID   string
+}
+
+// newMonitorRecord returns the monitor record of an EndpointMonitor from its status
+func newMonitorRecord(instance *endpointmonitorv1alpha1.EndpointMonitor, displayName string) monitorRecord {
+	return monitorRecord{
+		monitorName: instance.Status.MonitorName,
+		displayName: displayName,
+		provider:    instance.Status.Provider,
+		providerID:  instance.Status.ProviderID,
+		monitorID:   instance.Status.MonitorID,
+	}
+}
+
+// rememberMonitor records the monitor of an EndpointMonitor, see handleDelete
+func (r *EndpointMonitorReconciler) rememberMonitor(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor, displayName string) {
+	r.monitorRecords.Store(request.NamespacedName, newMonitorRecord(instance, displayName))
+}
+
+// handleFinalize applies the deletion policy of an EndpointMonitor that is being deleted and releases it. Only the
+// status and spec are used, as the ingress, route or Secrets of the EndpointMonitor are often deleted along with it.
+// When its provider can't be resolved any more, the EndpointMonitor is released without applying the policy.
+func (r *EndpointMonitorReconciler) handleFinalize(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor) (reconcile.Result, error) {
+	log := r.Log.WithValues("endpointMonitor", request.NamespacedName)
+	if !controllerutil.ContainsFinalizer(instance, monitorCleanupFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	policy := getDeletionPolicy(instance)
+	if policy == endpointmonitorv1alpha1.DeletionPolicyRetain {
+		log.Info("Deletion policy is " + string(policy) + ". Skipping deletion for monitor: " + instance.Status.MonitorName)
+	} else if monitorService, _, err := r.getMonitorService(instance); err != nil {
+		// The provider may be gone already, e.g. when the namespace of a MonitorProvider is deleted
+		log.Error(err, "Failed to select the provider, leaving monitor "+instance.Status.MonitorName+" as is")
+	} else if !recordedInAccount(instance.Status.Provider, instance.Status.ProviderID, monitorService) {
+		log.Info("No monitor was recorded in provider " + monitorService.GetID() + ", skipping " + string(policy))
+	} else {
+		if err := monitorService.Healthy(); err != nil {
+			log.Info("Provider is not ready, requeuing", "error", err.Error())
+			return reconcile.Result{RequeueAfter: providerNotReadyRequeueTime}, r.setDegraded(instance, err)
+		}
+		record := newMonitorRecord(instance, getDisplayName(instance, instance.Status.MonitorName, monitorService))
+		if policy == endpointmonitorv1alpha1.DeletionPolicyDelete {
+			err = r.deleteMonitor(request, instance, record, monitorService)
+			if err == nil {
+				err = removeCertificateMonitor(instance, monitorService)
+			}
+		} else {
+			err = r.pauseMonitor(instance, r.getPausedSpec(instance, monitorService), record, monitorService)
+		}
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// The policy has been applied, nothing is left to do once the EndpointMonitor is gone
+	r.deletionPolicies.Store(request.NamespacedName, endpointmonitorv1alpha1.DeletionPolicyRetain)
+	r.monitorRecords.Delete(request.NamespacedName)
+	controllerutil.RemoveFinalizer(instance, monitorCleanupFinalizer)
+	return reconcile.Result{}, r.Update(context.TODO(), instance)
+}
+
+// handleDelete handles EndpointMonitors that are already gone, their monitor is only deleted when
+// their deletion policy was Delete and the monitor was recorded while they existed
+func (r *EndpointMonitorReconciler) handleDelete(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor) (reconcile.Result, error) {
 	log := r.Log.WithValues("endpointMonitor", request.Namespace)
 	if instance == nil {
 		// Instance not found, nothing to do
 		return reconcile.Result{}, nil
 	}
 
-	if !config.GetControllerConfig().EnableMonitorDeletion {
-		log.Info("Monitor deletion is disabled. Skipping deletion for monitor: " + monitorName)
+	value, ok := r.monitorRecords.Load(request.NamespacedName)
+	if !ok {
+		log.Info("No monitor was recorded for EndpointMonitor " + request.Name + ", skipping deletion")
+		r.deletionPolicies.Delete(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
+	record := value.(monitorRecord)
 
-	// in case of multiple providers we need to iterate over all of them
-	monitorServices, err := findMonitorServicesThatContainsMonitor(r.MonitorServices, monitorName)
-	if err != nil {
-		log.Error(err, "Failed to find monitor services containing monitor: "+monitorName)
-		return reconcile.Result{}, err
-	}
-	for _, monitorService := range monitorServices {
-		if err := r.removeMonitorIfExists(monitorService, monitorName); err != nil {
-			return reconcile.Result{}, err
-		}
+	policy := getDeletionPolicy(instance)
+	if recorded, ok := r.deletionPolicies.LoadAndDelete(request.NamespacedName); ok {
+		policy = recorded.(endpointmonitorv1alpha1.DeletionPolicy)
 	}
-	if len(monitorServices) < 1 {
-		log.Info("Cannot find monitor service that contains monitor: " + monitorName)
+	if policy != endpointmonitorv1alpha1.DeletionPolicyDelete {
+		log.Info("Deletion policy is " + string(policy) + ". Skipping deletion for monitor: " + record.displayName)
+		r.monitorRecords.Delete(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
+
+	if err := r.deleteMonitor(request, instance, record, r.getRecordedMonitorService(record)); err != nil {
+		// Remember the policy for the retry
+		r.deletionPolicies.Store(request.NamespacedName, policy)
+		return reconcile.Result{}, err
+	}
+	r.monitorRecords.Delete(request.NamespacedName)
 	return reconcile.Result{}, nil
 }
 
-func (r *EndpointMonitorReconciler) removeMonitorIfExists(monitorService *monitors.MonitorServiceProxy, monitorName string) error {
-	log := r.Log.WithValues("monitor", monitorName)
+// deleteMonitor removes the recorded monitor from monitorService, the provider account recorded with it, unless
+// it belongs to another EndpointMonitor. The monitor is looked up by its ID when known, and by its name otherwise.
+func (r *EndpointMonitorReconciler) deleteMonitor(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor, record monitorRecord, monitorService *monitors.MonitorServiceProxy) error {
+	log := r.Log.WithValues("endpointMonitor", request.Namespace)
 
-	monitor, err := monitorService.GetByName(monitorName)
+	if monitorService == nil {
+		log.Info("Cannot find provider " + record.providerID + " of monitor " + record.displayName + ", skipping deletion")
+		return nil
+	}
+	if err := monitorService.Healthy(); err != nil {
+		log.Info("Monitor " + record.displayName + " can't be deleted while its provider is not ready, requeuing")
+		return err
+	}
+
+	monitor, err := r.findOwnedMonitor(instance, record, monitorService)
+	if err != nil || monitor == nil {
+		return err
+	}
+	log.Info("Removing monitor " + monitor.Name + " from provider: " + monitorService.GetID())
+	return monitorService.Remove(*monitor)
+}
+
+// findOwnedMonitor returns the recorded monitor by its ID, or by its name when the ID isn't known. Nothing is
+// returned when the monitor can't be found or belongs to another EndpointMonitor by now.
+func (r *EndpointMonitorReconciler) findOwnedMonitor(instance *endpointmonitorv1alpha1.EndpointMonitor, record monitorRecord, monitorService *monitors.MonitorServiceProxy) (*models.Monitor, error) {
+	log := r.Log.WithValues("monitor", record.displayName)
+
+	// The monitor may now belong to another EndpointMonitor using the same name
+	claimant, err := r.findOtherMonitorNameClaimant(instance, record.monitorName, monitorService)
+	if err != nil {
+		return nil, err
+	}
+	if claimant != nil {
+		log.Info("Monitor " + record.monitorName + " is used by EndpointMonitor " + claimant.Namespace + "/" + claimant.Name + ", leaving it as is")
+		return nil, nil
+	}
+
+	var monitor *models.Monitor
+	if len(record.monitorID) > 0 {
+		monitor, err = monitorService.GetByID(record.monitorID)
+	} else if len(record.displayName) > 0 {
+		monitor, err = findMonitorByName(monitorService, record.displayName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if monitor == nil {
+		log.Info("Cannot find monitor " + record.displayName + " in provider: " + monitorService.GetID())
+		return nil, nil
+	}
+
+	owner, err := r.findMonitorIDOwner(instance, monitor.ID, monitorService)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil {
+		log.Info("Monitor " + monitor.Name + " belongs to EndpointMonitor " + owner.Namespace + "/" + owner.Name + ", leaving it as is")
+		return nil, nil
+	}
+	return monitor, nil
+}
+
+// findOtherMonitorNameClaimant returns an EndpointMonitor other than instance whose status records monitorName
+// in the provider account of monitorService
+func (r *EndpointMonitorReconciler) findOtherMonitorNameClaimant(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, monitorService *monitors.MonitorServiceProxy) (*endpointmonitorv1alpha1.EndpointMonitor, error) {
+	if len(monitorName) == 0 {
+		return nil, nil
+	}
+	claimants, err := r.listMonitorNameClaimants(monitorName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range claimants {
+		if claimants[i].UID != instance.UID && recordedInAccount(claimants[i].Status.Provider, claimants[i].Status.ProviderID, monitorService) {
+			return &claimants[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// getPausedSpec returns the spec of an EndpointMonitor being deleted with the provider config recorded in its
+// status, so that the monitor keeps its settings when paused. Secret references are resolved as far as the
+// Secrets still exist.
+func (r *EndpointMonitorReconciler) getPausedSpec(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorService *monitors.MonitorServiceProxy) endpointmonitorv1alpha1.EndpointMonitorSpec {
+	log := r.Log.WithValues("endpointMonitor", instance.Namespace+"/"+instance.Name)
+
+	effective := instance.DeepCopy()
+	if fieldName, ok := providerConfigFields[monitorService.GetType()]; ok && instance.Status.EffectiveConfig != nil {
+		field := reflect.ValueOf(&effective.Spec).Elem().FieldByName(fieldName)
+		providerConfig := reflect.New(field.Type().Elem())
+		if err := json.Unmarshal(instance.Status.EffectiveConfig.Raw, providerConfig.Interface()); err != nil {
+			log.Error(err, "Failed to read the effective config, pausing the monitor with the config of the spec")
+		} else {
+			field.Set(providerConfig)
+		}
+	}
+	spec, err := r.resolveSecretRefs(effective)
 	if err != nil {
-		log.Error(err, "Failed to get monitor by name: "+monitorName+" for provider: "+monitorService.GetType())
+		log.Info("Pausing the monitor without the values of missing Secrets", "error", err.Error())
+	}
+	return spec
+}
+
+// pauseMonitor disables the recorded monitor in its provider. Monitors of providers that can't pause
+// monitors are retained.
+func (r *EndpointMonitorReconciler) pauseMonitor(instance *endpointmonitorv1alpha1.EndpointMonitor, spec endpointmonitorv1alpha1.EndpointMonitorSpec, record monitorRecord, monitorService *monitors.MonitorServiceProxy) error {
+	log := r.Log.WithValues("monitor", record.displayName)
+
+	monitor, err := r.findOwnedMonitor(instance, record, monitorService)
+	if err != nil || monitor == nil {
 		return err
 	}
-	// Monitor Exists
-	if monitor != nil {
-		// Monitor Exists, remove the monitor
-		log.Info("Removing monitor " + monitorName + " from provider: " + monitorService.GetType())
-		monitorService.Remove(*monitor)
-	} else {
-		log.Info("Cannot find monitor with name: " + monitorName + " for provider: " + monitorService.GetType())
+
+	// The URL may no longer be resolvable, the ingress or route is often deleted along with the EndpointMonitor
+	pausedMonitor := models.Monitor{Name: monitor.Name, ID: monitor.ID, URL: monitor.URL, Config: monitorService.ExtractConfig(spec), Labels: instance.Labels}
+	if err := monitorService.Pause(pausedMonitor); err != nil {
+		if errors.Is(err, monitors.ErrPauseNotSupported) {
+			log.Info("Retaining monitor " + monitor.Name + ": " + err.Error())
+			return nil
+		}
+		return err
 	}
+	log.Info("Paused monitor " + monitor.Name + " in provider: " + monitorService.GetType())
 	return nil
 }
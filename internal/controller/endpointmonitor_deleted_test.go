@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+// fakeHealthchecks is a Healthchecks.io project holding the names of its checks by their UUID
+type fakeHealthchecks struct {
+	mu     sync.Mutex
+	checks map[string]string
+}
+
+func (f *fakeHealthchecks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	uuid := strings.TrimPrefix(r.URL.Path, "/api/v3/checks/")
+	switch {
+	case r.Method == http.MethodGet && len(uuid) == 0:
+		checks := []map[string]string{}
+		for uuid, name := range f.checks {
+			checks = append(checks, map[string]string{"uuid": uuid, "name": name})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"checks": checks})
+	case r.Method == http.MethodGet && len(f.checks[uuid]) > 0:
+		_ = json.NewEncoder(w).Encode(map[string]string{"uuid": uuid, "name": f.checks[uuid]})
+	case r.Method == http.MethodDelete:
+		delete(f.checks, uuid)
+		_, _ = w.Write([]byte("{}"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeHealthchecks) names() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for _, name := range f.checks {
+		names = append(names, name)
+	}
+	return names
+}
+
+// newFakeHealthchecks returns a Healthchecks.io account holding checks, named by their UUID
+func newFakeHealthchecks(t *testing.T, id string, checks map[string]string) (*monitors.MonitorServiceProxy, *fakeHealthchecks) {
+	fake := &fakeHealthchecks{checks: checks}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeHealthchecks, ID: id, ApiKey: "api-key", ApiURL: server.URL}), fake
+}
+
+func TestGetDeletionPolicy(t *testing.T) {
+	defer func(c config.Config) { config.IngressMonitorControllerConfig = c }(config.IngressMonitorControllerConfig)
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{}
+
+	config.IngressMonitorControllerConfig.EnableMonitorDeletion = true
+	assert.Equal(t, getDeletionPolicy(instance), endpointmonitorv1alpha1.DeletionPolicyDelete)
+
+	config.IngressMonitorControllerConfig.EnableMonitorDeletion = false
+	assert.Equal(t, getDeletionPolicy(instance), endpointmonitorv1alpha1.DeletionPolicyRetain)
+
+	instance.Spec.DeletionPolicy = endpointmonitorv1alpha1.DeletionPolicyPause
+	assert.Equal(t, getDeletionPolicy(instance), endpointmonitorv1alpha1.DeletionPolicyPause)
+}
+
+func TestReconcileFinalizer(t *testing.T) {
+	defer func(c config.Config) { config.IngressMonitorControllerConfig = c }(config.IngressMonitorControllerConfig)
+	config.IngressMonitorControllerConfig.EnableMonitorDeletion = true
+
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "shop"}}
+	r := newProviderTestReconciler(t, nil, instance)
+
+	// Monitors deleted by default are cleaned up through the finalizer
+	assert.NilError(t, r.reconcileFinalizer(request, instance))
+	stored := &endpointmonitorv1alpha1.EndpointMonitor{}
+	assert.NilError(t, r.Get(context.TODO(), request.NamespacedName, stored))
+	assert.Assert(t, controllerutil.ContainsFinalizer(stored, monitorCleanupFinalizer))
+
+	// Retained monitors don't need it
+	stored.Spec.DeletionPolicy = endpointmonitorv1alpha1.DeletionPolicyRetain
+	assert.NilError(t, r.reconcileFinalizer(request, stored))
+	assert.NilError(t, r.Get(context.TODO(), request.NamespacedName, stored))
+	assert.Assert(t, !controllerutil.ContainsFinalizer(stored, monitorCleanupFinalizer))
+
+	// The policy recorded before the deletion wins over the default of the controller config
+	r.monitorRecords.Store(request.NamespacedName, monitorRecord{monitorName: "checkout-shop", displayName: "checkout-shop"})
+	result, err := r.handleDelete(request, &endpointmonitorv1alpha1.EndpointMonitor{})
+	assert.NilError(t, err)
+	assert.Equal(t, result, reconcile.Result{})
+	_, remembered := r.monitorRecords.Load(request.NamespacedName)
+	assert.Assert(t, !remembered)
+	_, remembered = r.deletionPolicies.Load(request.NamespacedName)
+	assert.Assert(t, !remembered)
+}
+
+func TestHandleDeleteOnlyDeletesFromRecordedAccount(t *testing.T) {
+	defer func(c config.Config) { config.IngressMonitorControllerConfig = c }(config.IngressMonitorControllerConfig)
+	config.IngressMonitorControllerConfig.EnableMonitorDeletion = true
+
+	// Both accounts hold a monitor of the same name
+	team, teamChecks := newFakeHealthchecks(t, "team", map[string]string{"1": "checkout-shop"})
+	other, otherChecks := newFakeHealthchecks(t, "other-team", map[string]string{"2": "checkout-shop"})
+	r := newProviderTestReconciler(t, nil)
+	r.MonitorServices = []*monitors.MonitorServiceProxy{team, other}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "shop"}}
+	r.monitorRecords.Store(request.NamespacedName, monitorRecord{
+		monitorName: "checkout-shop",
+		displayName: "checkout-shop",
+		provider:    monitors.TypeHealthchecks,
+		providerID:  "other-team",
+	})
+	_, err := r.handleDelete(request, &endpointmonitorv1alpha1.EndpointMonitor{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, teamChecks.names(), []string{"checkout-shop"})
+	assert.Equal(t, len(otherChecks.names()), 0)
+
+	// Nothing is deleted for EndpointMonitors whose monitor wasn't recorded
+	_, err = r.handleDelete(request, &endpointmonitorv1alpha1.EndpointMonitor{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, teamChecks.names(), []string{"checkout-shop"})
+}
+
+func TestHandleDeleteUsesRecordedMonitorID(t *testing.T) {
+	defer func(c config.Config) { config.IngressMonitorControllerConfig = c }(config.IngressMonitorControllerConfig)
+	config.IngressMonitorControllerConfig.EnableMonitorDeletion = true
+
+	// The monitor was renamed in the provider since it was created
+	healthchecks, fake := newFakeHealthchecks(t, "", map[string]string{"1": "checkout (renamed)", "2": "checkout-shop"})
+	r := newProviderTestReconciler(t, nil)
+	r.MonitorServices = []*monitors.MonitorServiceProxy{healthchecks}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "shop"}}
+	r.monitorRecords.Store(request.NamespacedName, monitorRecord{
+		monitorName: "checkout-shop",
+		displayName: "checkout-shop",
+		provider:    monitors.TypeHealthchecks,
+		providerID:  monitors.TypeHealthchecks,
+		monitorID:   "1",
+	})
+	_, err := r.handleDelete(request, &endpointmonitorv1alpha1.EndpointMonitor{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, fake.names(), []string{"checkout-shop"})
+}
+
+func TestHandleFinalizeOnlyDeletesFromResolvedProvider(t *testing.T) {
+	team, teamChecks := newFakeHealthchecks(t, "team", map[string]string{"1": "checkout"})
+	other, otherChecks := newFakeHealthchecks(t, "other-team", map[string]string{"2": "checkout"})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", Finalizers: []string{monitorCleanupFinalizer}},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			ProviderID:         "team",
+			HealthchecksConfig: &endpointmonitorv1alpha1.HealthchecksConfig{},
+			DeletionPolicy:     endpointmonitorv1alpha1.DeletionPolicyDelete,
+		},
+		// The status refers to an account the EndpointMonitor doesn't use
+		Status: endpointmonitorv1alpha1.EndpointMonitorStatus{
+			MonitorName: "checkout",
+			Provider:    monitors.TypeHealthchecks,
+			ProviderID:  "other-team",
+		},
+	}
+	r := newProviderTestReconciler(t, nil, instance)
+	r.MonitorServices = []*monitors.MonitorServiceProxy{team, other}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "shop"}}
+	_, err := r.handleFinalize(request, instance)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, teamChecks.names(), []string{"checkout"})
+	assert.DeepEqual(t, otherChecks.names(), []string{"checkout"})
+	assert.Assert(t, !controllerutil.ContainsFinalizer(instance, monitorCleanupFinalizer))
+
+	// Monitors recorded in the account the EndpointMonitor uses are deleted
+	instance.Finalizers = []string{monitorCleanupFinalizer}
+	instance.Status.ProviderID = "team"
+	_, err = r.handleFinalize(request, instance)
+	assert.NilError(t, err)
+	assert.Equal(t, len(teamChecks.names()), 0)
+	assert.DeepEqual(t, otherChecks.names(), []string{"checkout"})
+}
+
+func TestReconcileFinalizesWithoutIngressOrSecrets(t *testing.T) {
+	healthchecks, fake := newFakeHealthchecks(t, "team", map[string]string{"1": "checkout-shop", "2": "blog-shop"})
+	deleted := metav1.Now()
+	newInstance := func(name string, spec endpointmonitorv1alpha1.EndpointMonitorSpec, monitorID string) *endpointmonitorv1alpha1.EndpointMonitor {
+		spec.DeletionPolicy = endpointmonitorv1alpha1.DeletionPolicyDelete
+		// The Ingress the URL was read from is gone
+		spec.URLFrom = &endpointmonitorv1alpha1.URLSource{IngressRef: &endpointmonitorv1alpha1.IngressURLSource{Name: name}}
+		return &endpointmonitorv1alpha1.EndpointMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "shop", Finalizers: []string{monitorCleanupFinalizer}, DeletionTimestamp: &deleted,
+			},
+			Spec: spec,
+			Status: endpointmonitorv1alpha1.EndpointMonitorStatus{
+				MonitorName: name + "-shop",
+				MonitorID:   monitorID,
+				Provider:    monitors.TypeHealthchecks,
+				ProviderID:  "team",
+			},
+		}
+	}
+	checkout := newInstance("checkout", endpointmonitorv1alpha1.EndpointMonitorSpec{
+		ProviderID:         "team",
+		HealthchecksConfig: &endpointmonitorv1alpha1.HealthchecksConfig{},
+	}, "1")
+	// The credentials Secret of the MonitorProvider is gone as well
+	provider := &endpointmonitorv1alpha1.MonitorProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "healthchecks", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.MonitorProviderSpec{
+			Type:                 monitors.TypeHealthchecks,
+			CredentialsSecretRef: &endpointmonitorv1alpha1.ProviderSecretReference{Name: "healthchecks"},
+		},
+	}
+	blog := newInstance("blog", endpointmonitorv1alpha1.EndpointMonitorSpec{
+		ProviderRef: &endpointmonitorv1alpha1.ProviderReference{Kind: endpointmonitorv1alpha1.MonitorProviderKind, Name: "healthchecks"},
+	}, "2")
+	r := newProviderTestReconciler(t, nil, checkout, blog, provider)
+	r.MonitorServices = []*monitors.MonitorServiceProxy{healthchecks}
+
+	for _, name := range []string{"checkout", "blog"} {
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "shop"}}
+		_, err := r.Reconcile(context.TODO(), request)
+		assert.NilError(t, err)
+		err = r.Get(context.TODO(), request.NamespacedName, &endpointmonitorv1alpha1.EndpointMonitor{})
+		assert.Assert(t, errors.IsNotFound(err), "EndpointMonitor %s is released", name)
+	}
+	// The monitor of the provider that can't be set up any more is left as is
+	assert.DeepEqual(t, fake.names(), []string{"blog-shop"})
+}
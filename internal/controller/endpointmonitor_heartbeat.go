@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+const (
+	// pingURLKey is the key of the ping URL in the Secret published for heartbeat monitors
+	pingURLKey = "pingURL"
+
+	// defaultHeartbeatGracePeriod is the time the jobs have to ping the monitor when the cronJobRef sets none
+	defaultHeartbeatGracePeriod = 5 * time.Minute
+
+	// maxScheduleRuns bounds the runs of a schedule looked at to find the longest gap between two of them
+	maxScheduleRuns = 10000
+)
+
+// scheduleReference is where the runs of a schedule start to be looked at, a fixed start keeps the period
+// derived from the schedule stable between reconciles
+var scheduleReference = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// getHeartbeat reads the CronJob pinging the heartbeat monitor of the EndpointMonitor, nil is returned
+// for the other check types
+func (r *EndpointMonitorReconciler) getHeartbeat(instance *endpointmonitorv1alpha1.EndpointMonitor) (*models.Heartbeat, error) {
+	if getCheckType(instance) != endpointmonitorv1alpha1.CheckTypeHeartbeat || instance.Spec.URLFrom == nil || instance.Spec.URLFrom.CronJobRef == nil {
+		return nil, nil
+	}
+	cronJobRef := instance.Spec.URLFrom.CronJobRef
+
+	cronJob := &batchv1.CronJob{}
+	if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: cronJobRef.Name, Namespace: instance.Namespace}, cronJob); err != nil {
+		return nil, fmt.Errorf("failed to get CronJob %s: %w", cronJobRef.Name, err)
+	}
+
+	heartbeat := &models.Heartbeat{Schedule: cronJob.Spec.Schedule, Grace: int(defaultHeartbeatGracePeriod.Seconds())}
+	if cronJob.Spec.TimeZone != nil {
+		heartbeat.TimeZone = *cronJob.Spec.TimeZone
+	}
+	if cronJobRef.GracePeriod != nil {
+		heartbeat.Grace = int(cronJobRef.GracePeriod.Seconds())
+	}
+	period, err := schedulePeriod(heartbeat.Schedule, heartbeat.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule of CronJob %s: %w", cronJobRef.Name, err)
+	}
+	heartbeat.Period = int(period.Seconds())
+	return heartbeat, nil
+}
+
+// schedulePeriod returns the longest time between two runs of a cron schedule over a year, which is how
+// long a heartbeat monitor has to wait for the next ping
+func schedulePeriod(schedule string, timeZone string) (time.Duration, error) {
+	location := time.UTC
+	if len(timeZone) > 0 {
+		var err error
+		if location, err = time.LoadLocation(timeZone); err != nil {
+			return 0, err
+		}
+	}
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return 0, err
+	}
+
+	start := scheduleReference.In(location)
+	end := start.AddDate(1, 0, 0)
+	var period time.Duration
+	previous := parsed.Next(start)
+	for i := 0; i < maxScheduleRuns && !previous.IsZero() && previous.Before(end); i++ {
+		next := parsed.Next(previous)
+		if next.IsZero() {
+			break
+		}
+		period = max(period, next.Sub(previous))
+		previous = next
+	}
+	if period == 0 {
+		return 0, fmt.Errorf("schedule %q doesn't run twice", schedule)
+	}
+	return period, nil
+}
+
+// heartbeatSecretName returns the name of the Secret the ping URL of the heartbeat monitor is published in
+func heartbeatSecretName(instance *endpointmonitorv1alpha1.EndpointMonitor) string {
+	if cronJobRef := instance.Spec.URLFrom.CronJobRef; len(cronJobRef.SecretName) > 0 {
+		return cronJobRef.SecretName
+	}
+	return instance.Name + "-heartbeat"
+}
+
+// publishPingURL writes the ping URL of the heartbeat monitor into a Secret owned by the EndpointMonitor,
+// which the jobs of the CronJob read to ping the monitor. A Secret of that name created by others is left
+// untouched and reported through the PingURLPublished condition.
+func (r *EndpointMonitorReconciler) publishPingURL(instance *endpointmonitorv1alpha1.EndpointMonitor, pingURL string) error {
+	if getCheckType(instance) != endpointmonitorv1alpha1.CheckTypeHeartbeat || len(pingURL) == 0 {
+		return nil
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: heartbeatSecretName(instance), Namespace: instance.Namespace}}
+	result, err := r.applyOwnedObject(instance, secret, func() {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[pingURLKey] = []byte(pingURL)
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to publish the ping URL in Secret %s: %w", secret.Name, err)
+		if errors.Is(err, errObjectNotOwned) {
+			if statusErr := r.setPingURLPublished(instance, metav1.ConditionFalse, endpointmonitorv1alpha1.ReasonSecretNotOwned, err.Error()); statusErr != nil {
+				return statusErr
+			}
+		}
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		r.Log.Info("Published the ping URL of monitor "+instance.Status.MonitorName, "Secret", secret.Name, "Operation", result)
+	}
+	return r.setPingURLPublished(instance, metav1.ConditionTrue, endpointmonitorv1alpha1.ReasonPingURLPublished, "The ping URL is published in Secret "+secret.Name)
+}
+
+// setPingURLPublished reports in the status of the EndpointMonitor whether the ping URL is published
+func (r *EndpointMonitorReconciler) setPingURLPublished(instance *endpointmonitorv1alpha1.EndpointMonitor, status metav1.ConditionStatus, reason string, message string) error {
+	if !meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypePingURLPublished,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	}) {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), instance)
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+func TestSchedulePeriod(t *testing.T) {
+	period, err := schedulePeriod("0 * * * *", "")
+	assert.NilError(t, err)
+	assert.Equal(t, period, time.Hour)
+
+	// The weekend is the longest gap of jobs running on weekdays
+	period, err = schedulePeriod("0 2 * * 1-5", "")
+	assert.NilError(t, err)
+	assert.Equal(t, period, 72*time.Hour)
+
+	_, err = schedulePeriod("0 2 * *", "")
+	assert.ErrorContains(t, err, "expected exactly 5 fields")
+	_, err = schedulePeriod("@daily", "Mars/Olympus")
+	assert.ErrorContains(t, err, "unknown time zone")
+}
+
+func TestGetHeartbeatAndPublishPingURL(t *testing.T) {
+	timeZone := "Europe/Berlin"
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "shop"},
+		Spec:       batchv1.CronJobSpec{Schedule: "30 1 * * *", TimeZone: &timeZone},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "shop", UID: "backup-uid"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			CheckType: endpointmonitorv1alpha1.CheckTypeHeartbeat,
+			URLFrom: &endpointmonitorv1alpha1.URLSource{
+				CronJobRef: &endpointmonitorv1alpha1.CronJobURLSource{Name: "backup", GracePeriod: &metav1.Duration{Duration: 10 * time.Minute}},
+			},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, instance, cronJob)
+
+	heartbeat, err := r.getHeartbeat(instance)
+	assert.NilError(t, err)
+	assert.Equal(t, heartbeat.Schedule, "30 1 * * *")
+	assert.Equal(t, heartbeat.TimeZone, "Europe/Berlin")
+	// Daylight saving time makes one day of the year 25 hours long
+	assert.Equal(t, heartbeat.Period, 25*3600)
+	assert.Equal(t, heartbeat.Grace, 600)
+
+	assert.NilError(t, r.publishPingURL(instance, "https://hc-ping.com/uuid-backup"))
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.Get(context.TODO(), types.NamespacedName{Name: "backup-heartbeat", Namespace: "shop"}, secret))
+	assert.Equal(t, string(secret.Data[pingURLKey]), "https://hc-ping.com/uuid-backup")
+	assert.Equal(t, secret.OwnerReferences[0].UID, instance.UID)
+
+	// Other check types have no heartbeat
+	instance.Spec.CheckType = endpointmonitorv1alpha1.CheckTypeHTTP
+	heartbeat, err = r.getHeartbeat(instance)
+	assert.NilError(t, err)
+	assert.Assert(t, heartbeat == nil)
+}
+
+func TestPublishPingURLDoesNotTakeOverSecrets(t *testing.T) {
+	// The Secret was created by someone else, e.g. holding the credentials of the job
+	other := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-heartbeat", Namespace: "shop"},
+		Data:       map[string][]byte{"password": []byte("backup-password")},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "shop", UID: "backup-uid"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			CheckType: endpointmonitorv1alpha1.CheckTypeHeartbeat,
+			URLFrom:   &endpointmonitorv1alpha1.URLSource{CronJobRef: &endpointmonitorv1alpha1.CronJobURLSource{Name: "backup"}},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, instance, other)
+
+	err := r.publishPingURL(instance, "https://hc-ping.com/uuid-backup")
+	assert.ErrorContains(t, err, "isn't controlled by the EndpointMonitor")
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.Get(context.TODO(), types.NamespacedName{Name: "backup-heartbeat", Namespace: "shop"}, secret))
+	assert.DeepEqual(t, secret.Data, map[string][]byte{"password": []byte("backup-password")})
+	assert.Equal(t, len(secret.OwnerReferences), 0)
+
+	stored := &endpointmonitorv1alpha1.EndpointMonitor{}
+	assert.NilError(t, r.Get(context.TODO(), types.NamespacedName{Name: "backup", Namespace: "shop"}, stored))
+	condition := meta.FindStatusCondition(stored.Status.Conditions, endpointmonitorv1alpha1.ConditionTypePingURLPublished)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, endpointmonitorv1alpha1.ReasonSecretNotOwned)
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+// Keys of the outputs published in the object named by spec.outputRef
+const (
+	outputProviderKey      = "provider"
+	outputMonitorIDKey     = "monitorID"
+	outputMonitorNameKey   = "monitorName"
+	outputDashboardURLKey  = "dashboardURL"
+	outputStatusPageURLKey = "statusPageURL"
+	outputPingURLKey       = "pingURL"
+)
+
+// monitorOutputs returns the outputs of the monitor, the ping URL is only published for heartbeat monitors
+func monitorOutputs(instance *endpointmonitorv1alpha1.EndpointMonitor, monitor models.Monitor, pingURL string, links models.MonitorLinks, monitorService *monitors.MonitorServiceProxy) map[string]string {
+	outputs := map[string]string{
+		outputProviderKey:      monitorService.GetType(),
+		outputMonitorIDKey:     monitor.ID,
+		outputMonitorNameKey:   monitor.Name,
+		outputDashboardURLKey:  links.DashboardURL,
+		outputStatusPageURLKey: links.StatusPageURL,
+	}
+	if getCheckType(instance) == endpointmonitorv1alpha1.CheckTypeHeartbeat {
+		outputs[outputPingURLKey] = pingURL
+	}
+	for key, value := range outputs {
+		if len(value) == 0 {
+			delete(outputs, key)
+		}
+	}
+	return outputs
+}
+
+// errObjectNotOwned is returned when an object the EndpointMonitor publishes to exists already and isn't
+// controlled by it
+var errObjectNotOwned = errors.New("object exists and isn't controlled by the EndpointMonitor")
+
+// outputObject returns an empty ConfigMap or Secret named by the outputRef
+func outputObject(ref *endpointmonitorv1alpha1.OutputReference, namespace string) client.Object {
+	objectMeta := metav1.ObjectMeta{Name: ref.Name, Namespace: namespace}
+	if outputKind(ref) == endpointmonitorv1alpha1.OutputKindSecret {
+		return &corev1.Secret{ObjectMeta: objectMeta}
+	}
+	return &corev1.ConfigMap{ObjectMeta: objectMeta}
+}
+
+// publishOutputs writes the ID and links of the monitor into the ConfigMap or Secret named by spec.outputRef,
+// which is owned by the EndpointMonitor. The object published previously is deleted when spec.outputRef
+// changes. An object of that name created by others is left untouched and reported through the
+// OutputsPublished condition.
+func (r *EndpointMonitorReconciler) publishOutputs(instance *endpointmonitorv1alpha1.EndpointMonitor, monitor models.Monitor, pingURL string, monitorService *monitors.MonitorServiceProxy) error {
+	var changed bool
+	ref := instance.Spec.OutputRef
+	if ref != nil {
+		links, err := monitorService.MonitorLinks(monitor)
+		if err != nil {
+			// The ID of the monitor is still worth publishing
+			r.Log.Error(err, "Failed to get the links of monitor "+monitor.Name)
+		}
+		outputs := monitorOutputs(instance, monitor, pingURL, links, monitorService)
+
+		object := outputObject(ref, instance.Namespace)
+		result, err := r.applyOwnedObject(instance, object, func() {
+			switch object := object.(type) {
+			case *corev1.Secret:
+				object.Data = map[string][]byte{}
+				for key, value := range outputs {
+					object.Data[key] = []byte(value)
+				}
+			case *corev1.ConfigMap:
+				object.Data = outputs
+			}
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to publish the outputs in %s %s: %w", outputKind(ref), ref.Name, err)
+			if errors.Is(err, errObjectNotOwned) {
+				if statusErr := r.setOutputsPublished(instance, metav1.ConditionFalse, endpointmonitorv1alpha1.ReasonOutputNotOwned, err.Error()); statusErr != nil {
+					return statusErr
+				}
+			}
+			return err
+		}
+		if result != controllerutil.OperationResultNone {
+			r.Log.Info("Published the outputs of monitor "+monitor.Name, "Kind", outputKind(ref), "Name", ref.Name, "Operation", result)
+		}
+		changed = meta.SetStatusCondition(&instance.Status.Conditions, outputsPublishedCondition(instance, metav1.ConditionTrue,
+			endpointmonitorv1alpha1.ReasonOutputsPublished, "The outputs are published in "+string(outputKind(ref))+" "+ref.Name))
+	} else {
+		changed = meta.RemoveStatusCondition(&instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeOutputsPublished)
+	}
+
+	previous := instance.Status.OutputRef
+	if !equalOutputRef(previous, ref) {
+		if previous != nil {
+			if err := r.removeOutputs(instance, previous); err != nil {
+				return err
+			}
+		}
+		instance.Status.OutputRef = ref.DeepCopy()
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), instance)
+}
+
+// setOutputsPublished reports in the status of the EndpointMonitor whether the outputs are published
+func (r *EndpointMonitorReconciler) setOutputsPublished(instance *endpointmonitorv1alpha1.EndpointMonitor, status metav1.ConditionStatus, reason string, message string) error {
+	if !meta.SetStatusCondition(&instance.Status.Conditions, outputsPublishedCondition(instance, status, reason, message)) {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), instance)
+}
+
+func outputsPublishedCondition(instance *endpointmonitorv1alpha1.EndpointMonitor, status metav1.ConditionStatus, reason string, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeOutputsPublished,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	}
+}
+
+// outputKind returns the kind of the object named by the outputRef, which defaults to a ConfigMap
+func outputKind(ref *endpointmonitorv1alpha1.OutputReference) endpointmonitorv1alpha1.OutputKind {
+	if ref.Kind == endpointmonitorv1alpha1.OutputKindSecret {
+		return endpointmonitorv1alpha1.OutputKindSecret
+	}
+	return endpointmonitorv1alpha1.OutputKindConfigMap
+}
+
+// removeOutputs deletes the ConfigMap or Secret the outputs were published in, unless another object
+// took it over
+func (r *EndpointMonitorReconciler) removeOutputs(instance *endpointmonitorv1alpha1.EndpointMonitor, ref *endpointmonitorv1alpha1.OutputReference) error {
+	object := outputObject(ref, instance.Namespace)
+	if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: instance.Namespace}, object); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(object, instance) {
+		return nil
+	}
+	if err := r.Delete(context.TODO(), object); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the outputs in %s %s: %w", ref.Kind, ref.Name, err)
+	}
+	return nil
+}
+
+// applyOwnedObject creates or updates an object owned by the EndpointMonitor with the changes of mutate. The
+// object is read through the apiReader, so that the ConfigMaps and Secrets of the cluster aren't cached. Existing
+// objects that aren't controlled by the EndpointMonitor are left untouched and errObjectNotOwned is returned.
+func (r *EndpointMonitorReconciler) applyOwnedObject(instance *endpointmonitorv1alpha1.EndpointMonitor, object client.Object, mutate func()) (controllerutil.OperationResult, error) {
+	if err := r.apiReader().Get(context.TODO(), client.ObjectKeyFromObject(object), object); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return controllerutil.OperationResultNone, err
+		}
+		mutate()
+		if err := controllerutil.SetControllerReference(instance, object, r.Client.Scheme()); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+		return controllerutil.OperationResultCreated, r.Create(context.TODO(), object)
+	}
+
+	// Objects created by others are never taken over, they would be garbage collected with the EndpointMonitor
+	if !metav1.IsControlledBy(object, instance) {
+		return controllerutil.OperationResultNone, errObjectNotOwned
+	}
+	existing := object.DeepCopyObject()
+	mutate()
+	if equality.Semantic.DeepEqual(existing, object) {
+		return controllerutil.OperationResultNone, nil
+	}
+	return controllerutil.OperationResultUpdated, r.Update(context.TODO(), object)
+}
+
+func equalOutputRef(a, b *endpointmonitorv1alpha1.OutputReference) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestPublishOutputs(t *testing.T) {
+	updown := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeUpdown, ApiKey: "api-key"})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", UID: "checkout-uid"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			URL:       "https://checkout.example.com",
+			OutputRef: &endpointmonitorv1alpha1.OutputReference{Kind: endpointmonitorv1alpha1.OutputKindConfigMap, Name: "checkout-monitor"},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, instance)
+	monitor := models.Monitor{
+		Name:   "shop-checkout",
+		ID:     "a1b2",
+		Config: &endpointmonitorv1alpha1.UpdownConfig{PublishPage: true},
+	}
+
+	assert.NilError(t, r.publishOutputs(instance, monitor, "", updown))
+	configMap := &corev1.ConfigMap{}
+	assert.NilError(t, r.Get(context.TODO(), types.NamespacedName{Name: "checkout-monitor", Namespace: "shop"}, configMap))
+	assert.DeepEqual(t, configMap.Data, map[string]string{
+		outputProviderKey:      monitors.TypeUpdown,
+		outputMonitorIDKey:     "a1b2",
+		outputMonitorNameKey:   "shop-checkout",
+		outputDashboardURLKey:  "https://updown.io/a1b2",
+		outputStatusPageURLKey: "https://updown.io/a1b2",
+	})
+	assert.Equal(t, configMap.OwnerReferences[0].UID, instance.UID)
+	assert.Equal(t, instance.Status.OutputRef.Name, "checkout-monitor")
+	assert.Assert(t, meta.IsStatusConditionTrue(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeOutputsPublished))
+
+	// Moving the outputs into a Secret deletes the ConfigMap
+	instance.Spec.OutputRef = &endpointmonitorv1alpha1.OutputReference{Kind: endpointmonitorv1alpha1.OutputKindSecret, Name: "checkout-monitor"}
+	assert.NilError(t, r.publishOutputs(instance, monitor, "", updown))
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.Get(context.TODO(), types.NamespacedName{Name: "checkout-monitor", Namespace: "shop"}, secret))
+	assert.Equal(t, string(secret.Data[outputMonitorIDKey]), "a1b2")
+	err := r.Get(context.TODO(), types.NamespacedName{Name: "checkout-monitor", Namespace: "shop"}, &corev1.ConfigMap{})
+	assert.Assert(t, errors.IsNotFound(err))
+	assert.Equal(t, instance.Status.OutputRef.Kind, endpointmonitorv1alpha1.OutputKindSecret)
+}
+
+func TestPublishOutputsDoesNotTakeOverObjects(t *testing.T) {
+	updown := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeUpdown, ApiKey: "api-key"})
+	controller := true
+	other := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "checkout-monitor", Namespace: "shop",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "checkout", UID: "deployment-uid", Controller: &controller}},
+		},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", UID: "checkout-uid"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			OutputRef: &endpointmonitorv1alpha1.OutputReference{Name: "checkout-monitor"},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, instance, other)
+
+	err := r.publishOutputs(instance, models.Monitor{Name: "shop-checkout", ID: "a1b2"}, "", updown)
+	assert.ErrorContains(t, err, "isn't controlled by the EndpointMonitor")
+}
+
+func TestPublishOutputsDoesNotAdoptObjects(t *testing.T) {
+	updown := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeUpdown, ApiKey: "api-key"})
+	// The Secret exists without an owner, e.g. created by hand
+	other := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout-credentials", Namespace: "shop"},
+		Data:       map[string][]byte{"password": []byte("checkout-password")},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", UID: "checkout-uid"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			OutputRef: &endpointmonitorv1alpha1.OutputReference{Kind: endpointmonitorv1alpha1.OutputKindSecret, Name: "checkout-credentials"},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, instance, other)
+
+	err := r.publishOutputs(instance, models.Monitor{Name: "shop-checkout", ID: "a1b2"}, "", updown)
+	assert.ErrorContains(t, err, "isn't controlled by the EndpointMonitor")
+	secret := &corev1.Secret{}
+	assert.NilError(t, r.Get(context.TODO(), types.NamespacedName{Name: "checkout-credentials", Namespace: "shop"}, secret))
+	assert.DeepEqual(t, secret.Data, map[string][]byte{"password": []byte("checkout-password")})
+	assert.Equal(t, len(secret.OwnerReferences), 0)
+
+	stored := &endpointmonitorv1alpha1.EndpointMonitor{}
+	assert.NilError(t, r.Get(context.TODO(), types.NamespacedName{Name: "checkout", Namespace: "shop"}, stored))
+	condition := meta.FindStatusCondition(stored.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeOutputsPublished)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, endpointmonitorv1alpha1.ReasonOutputNotOwned)
+	assert.Assert(t, stored.Status.OutputRef == nil)
+}
+
+func TestMonitorOutputsOfHeartbeats(t *testing.T) {
+	healthchecks := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeHealthchecks, ApiKey: "api-key"})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{CheckType: endpointmonitorv1alpha1.CheckTypeHeartbeat},
+	}
+	outputs := monitorOutputs(instance, models.Monitor{Name: "backup", ID: "uuid"}, "https://hc-ping.com/uuid",
+		models.MonitorLinks{DashboardURL: "https://healthchecks.io/checks/uuid/details/"}, healthchecks)
+	assert.DeepEqual(t, outputs, map[string]string{
+		outputProviderKey:     monitors.TypeHealthchecks,
+		outputMonitorIDKey:    "uuid",
+		outputMonitorNameKey:  "backup",
+		outputDashboardURLKey: "https://healthchecks.io/checks/uuid/details/",
+		outputPingURLKey:      "https://hc-ping.com/uuid",
+	})
+}
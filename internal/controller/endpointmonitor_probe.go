@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+// updateProbeStatus records the result of the last probe of the monitor in status.probe, for providers
+// probing the endpoint from inside the cluster. It returns the interval of the probes, so that the status
+// follows them, 0 for the other providers.
+func (r *EndpointMonitorReconciler) updateProbeStatus(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, monitorService *monitors.MonitorServiceProxy) (time.Duration, error) {
+	result := monitorService.ProbeResult(monitorName)
+	if result == nil {
+		return 0, nil
+	}
+	probe := probeStatus(result)
+	if equality.Semantic.DeepEqual(instance.Status.Probe, probe) {
+		return result.Interval, nil
+	}
+	instance.Status.Probe = probe
+	return result.Interval, r.Status().Update(context.TODO(), instance)
+}
+
+// probeStatus converts the result of a probe, the times are truncated to the seconds kept by the API
+func probeStatus(result *models.ProbeResult) *endpointmonitorv1alpha1.ProbeStatus {
+	probe := &endpointmonitorv1alpha1.ProbeStatus{
+		Success:    result.Success,
+		Message:    result.Message,
+		StatusCode: result.StatusCode,
+	}
+	if !result.LastTransitionTime.IsZero() {
+		lastTransitionTime := metav1.NewTime(result.LastTransitionTime).Rfc3339Copy()
+		probe.LastTransitionTime = &lastTransitionTime
+	}
+	if !result.CertificateNotAfter.IsZero() {
+		notAfter := metav1.NewTime(result.CertificateNotAfter).Rfc3339Copy()
+		probe.CertificateNotAfter = &notAfter
+	}
+	return probe
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestUpdateProbeStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	local := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeLocal, ID: "probe-status"})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"},
+		Spec:       endpointmonitorv1alpha1.EndpointMonitorSpec{URL: server.URL},
+	}
+	r := newProviderTestReconciler(t, nil, instance)
+
+	// Nothing is recorded before the first probe
+	interval, err := r.updateProbeStatus(instance, "shop-orders", local)
+	assert.NilError(t, err)
+	assert.Equal(t, interval, time.Duration(0))
+	assert.Assert(t, instance.Status.Probe == nil)
+
+	local.Add(models.Monitor{Name: "shop-orders", URL: server.URL, Config: &endpointmonitorv1alpha1.LocalConfig{Interval: 15}})
+	defer func() {
+		monitor, _ := local.GetByName("shop-orders")
+		local.Remove(*monitor)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for local.ProbeResult("shop-orders") == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	interval, err = r.updateProbeStatus(instance, "shop-orders", local)
+	assert.NilError(t, err)
+	assert.Equal(t, interval, 15*time.Second)
+	assert.Equal(t, instance.Status.Probe.Success, false)
+	assert.Equal(t, instance.Status.Probe.Message, "status 503")
+	assert.Equal(t, instance.Status.Probe.StatusCode, http.StatusServiceUnavailable)
+	assert.Assert(t, instance.Status.Probe.LastTransitionTime != nil)
+}
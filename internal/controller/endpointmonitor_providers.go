@@ -0,0 +1,382 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+const (
+	providerRefIndexField = "spec.providerRef"
+
+	// useVerb is checked through a SubjectAccessReview before a namespace may use a ClusterMonitorProvider
+	useVerb = "use"
+)
+
+// providerConfigFields maps the provider types to the name of their config field on the EndpointMonitorSpec
+// and MonitorProviderDefaults
+var providerConfigFields = map[string]string{
+	monitors.TypeUptimeRobot:        "UptimeRobotConfig",
+	monitors.TypePingdom:            "PingdomConfig",
+	monitors.TypePingdomTransaction: "PingdomTransactionConfig",
+	monitors.TypeStatusCake:         "StatusCakeConfig",
+	monitors.TypeUptime:             "UptimeConfig",
+	monitors.TypeUpdown:             "UpdownConfig",
+	monitors.TypeAppInsights:        "AppInsightsConfig",
+	monitors.TypeGCloud:             "GCloudConfig",
+	monitors.TypeGrafana:            "GrafanaConfig",
+	monitors.TypePlugin:             "PluginConfig",
+	monitors.TypeWebhook:            "WebhookConfig",
+	monitors.TypeHealthchecks:       "HealthchecksConfig",
+	monitors.TypeLocal:              "LocalConfig",
+	monitors.TypeBlackbox:           "BlackboxConfig",
+	monitors.TypeBetterStack:        "BetterStackConfig",
+}
+
+// clusterOnlyTypes are the provider types acting with the identity of the controller, they are only offered to
+// namespaces through ClusterMonitorProviders. AppInsights and GCloud authenticate with the credentials of the
+// controller instead of a Secret, Plugin and Webhook call any address from the network of the controller.
+var clusterOnlyTypes = map[string]string{
+	monitors.TypeAppInsights: "uses the credentials of the controller",
+	monitors.TypeGCloud:      "uses the credentials of the controller",
+	monitors.TypePlugin:      "calls its address from the network of the controller",
+	monitors.TypeWebhook:     "calls its URLs from the network of the controller",
+}
+
+// providerSpecConfig holds the keys of a provider in config.yaml that may be set in spec.config of a provider.
+// Credentials, files and the account settings of the controller config can't be set there.
+type providerSpecConfig struct {
+	AppInsightsConfig config.AppInsights `yaml:"appInsightsConfig"`
+	GcloudConfig      config.Gcloud      `yaml:"gcloudConfig"`
+	GrafanaConfig     config.Grafana     `yaml:"grafanaConfig"`
+	PluginConfig      config.Plugin      `yaml:"pluginConfig"`
+	WebhookConfig     config.Webhook     `yaml:"webhookConfig"`
+	BlackboxConfig    config.Blackbox    `yaml:"blackboxConfig"`
+}
+
+// providerService is a monitor service set up for a MonitorProvider or ClusterMonitorProvider
+type providerService struct {
+	generation     int64
+	provider       config.Provider
+	monitorService *monitors.MonitorServiceProxy
+}
+
+// getMonitorService returns the monitor service of the provider referenced by spec.providerRef together
+// with its defaults, or the account of the controller config selected by the spec
+func (r *EndpointMonitorReconciler) getMonitorService(instance *endpointmonitorv1alpha1.EndpointMonitor) (*monitors.MonitorServiceProxy, *endpointmonitorv1alpha1.MonitorProviderDefaults, error) {
+	ref := instance.Spec.ProviderRef
+	if ref == nil {
+		monitorService, err := r.GetMonitorOfType(instance.Spec)
+		return monitorService, nil, err
+	}
+	if len(instance.Spec.ProviderID) > 0 {
+		return nil, nil, fmt.Errorf("providerID and providerRef can't be set at the same time")
+	}
+
+	id, generation, providerSpec, secretNamespace, err := r.getProviderSpec(instance.Namespace, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	if monitorType := getMonitorTypeOfSpec(instance.Spec); len(monitorType) > 0 && monitorType != providerSpec.Type {
+		return nil, nil, fmt.Errorf("%s is of type %s but the EndpointMonitor configures %s", id, providerSpec.Type, monitorType)
+	}
+
+	provider, err := r.buildProviderConfig(id, providerSpec, secretNamespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to set up %s: %w", id, err)
+	}
+	if ref.Kind != endpointmonitorv1alpha1.ClusterMonitorProviderKind {
+		if err := pinProviderNamespace(&provider, instance.Namespace); err != nil {
+			return nil, nil, fmt.Errorf("unable to set up %s: %w", id, err)
+		}
+	}
+	return r.getProviderService(id, generation, provider), providerSpec.Defaults, nil
+}
+
+// getProviderSpec reads the provider referenced by an EndpointMonitor in namespace and returns its ID, generation,
+// spec and the namespace of its credentials Secret
+func (r *EndpointMonitorReconciler) getProviderSpec(namespace string, ref *endpointmonitorv1alpha1.ProviderReference) (string, int64, *endpointmonitorv1alpha1.MonitorProviderSpec, string, error) {
+	if ref.Kind == endpointmonitorv1alpha1.ClusterMonitorProviderKind {
+		id := endpointmonitorv1alpha1.ClusterMonitorProviderKind + "/" + ref.Name
+		if err := r.checkClusterProviderAccess(namespace, ref.Name); err != nil {
+			return "", 0, nil, "", err
+		}
+		provider := &endpointmonitorv1alpha1.ClusterMonitorProvider{}
+		if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: ref.Name}, provider); err != nil {
+			return "", 0, nil, "", fmt.Errorf("unable to get %s: %w", id, err)
+		}
+		secretNamespace := ""
+		if provider.Spec.CredentialsSecretRef != nil {
+			secretNamespace = provider.Spec.CredentialsSecretRef.Namespace
+		}
+		return id, provider.Generation, &provider.Spec, secretNamespace, nil
+	}
+
+	id := endpointmonitorv1alpha1.MonitorProviderKind + "/" + namespace + "/" + ref.Name
+	provider := &endpointmonitorv1alpha1.MonitorProvider{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, provider); err != nil {
+		return "", 0, nil, "", fmt.Errorf("unable to get %s: %w", id, err)
+	}
+	if reason, ok := clusterOnlyTypes[provider.Spec.Type]; ok {
+		return "", 0, nil, "", fmt.Errorf("%s can't be of type %s, which %s, use a ClusterMonitorProvider instead", id, provider.Spec.Type, reason)
+	}
+	return id, provider.Generation, &provider.Spec, namespace, nil
+}
+
+// checkClusterProviderAccess checks that the service accounts of namespace may use the ClusterMonitorProvider,
+// which is granted through the "use" verb on the clustermonitorproviders resource in that namespace
+func (r *EndpointMonitorReconciler) checkClusterProviderAccess(namespace string, name string) error {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			Groups: []string{"system:serviceaccounts:" + namespace},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      useVerb,
+				Group:     endpointmonitorv1alpha1.GroupVersion.Group,
+				Resource:  "clustermonitorproviders",
+				Name:      name,
+			},
+		},
+	}
+	if err := r.Create(context.TODO(), review); err != nil {
+		return fmt.Errorf("unable to check access to ClusterMonitorProvider %s: %w", name, err)
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("namespace %s is not allowed to use ClusterMonitorProvider %s", namespace, name)
+	}
+	return nil
+}
+
+// buildProviderConfig converts the spec of a provider into the provider config used by the monitor services
+func (r *EndpointMonitorReconciler) buildProviderConfig(id string, spec *endpointmonitorv1alpha1.MonitorProviderSpec, secretNamespace string) (config.Provider, error) {
+	provider := config.Provider{}
+	if spec.Config != nil && len(spec.Config.Raw) > 0 {
+		// The config uses the keys of a provider in config.yaml
+		specConfig := providerSpecConfig{}
+		if err := yaml.UnmarshalStrict(spec.Config.Raw, &specConfig); err != nil {
+			return provider, fmt.Errorf("invalid config: %w", err)
+		}
+		provider.AppInsightsConfig = specConfig.AppInsightsConfig
+		provider.GcloudConfig = specConfig.GcloudConfig
+		provider.GrafanaConfig = specConfig.GrafanaConfig
+		provider.PluginConfig = specConfig.PluginConfig
+		provider.WebhookConfig = specConfig.WebhookConfig
+		provider.BlackboxConfig = specConfig.BlackboxConfig
+	}
+	provider.Name = spec.Type
+	provider.ID = id
+	provider.ApiURL = spec.APIURL
+	provider.AlertContacts = spec.AlertContacts
+	provider.AlertIntegrations = spec.AlertIntegrations
+	provider.TeamAlertContacts = spec.TeamAlertContacts
+	provider.Username = spec.Username
+	provider.AccountEmail = spec.AccountEmail
+
+	if ref := spec.CredentialsSecretRef; ref != nil {
+		if len(secretNamespace) == 0 {
+			return provider, fmt.Errorf("credentialsSecretRef of a ClusterMonitorProvider must set a namespace")
+		}
+		secret := &corev1.Secret{}
+		if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: secretNamespace}, secret); err != nil {
+			return provider, fmt.Errorf("unable to read credentials secret %s/%s: %w", secretNamespace, ref.Name, err)
+		}
+		provider.ApiKey = string(secret.Data["apiKey"])
+		provider.ApiToken = string(secret.Data["apiToken"])
+		provider.Password = string(secret.Data["password"])
+	}
+	return provider, nil
+}
+
+// pinProviderNamespace keeps the objects a MonitorProvider creates in the cluster, the Probes of Blackbox, in the
+// namespace of the MonitorProvider
+func pinProviderNamespace(provider *config.Provider, namespace string) error {
+	if provider.Name != monitors.TypeBlackbox {
+		return nil
+	}
+	if len(provider.BlackboxConfig.Namespace) > 0 && provider.BlackboxConfig.Namespace != namespace {
+		return fmt.Errorf("blackboxConfig.namespace of a MonitorProvider must be its own namespace %s", namespace)
+	}
+	provider.BlackboxConfig.Namespace = namespace
+	return nil
+}
+
+// getProviderService returns the monitor service of a provider, which is only set up again when the
+// provider or its credentials change
+func (r *EndpointMonitorReconciler) getProviderService(id string, generation int64, provider config.Provider) *monitors.MonitorServiceProxy {
+	if cached, ok := r.providerServices.Load(id); ok {
+		service := cached.(*providerService)
+		if service.generation == generation && reflect.DeepEqual(service.provider, provider) {
+			_ = service.monitorService.RetrySetup()
+			return service.monitorService
+		}
+	}
+
+	log.Info("Setting up monitor service for " + id)
+	service := &providerService{
+		generation:     generation,
+		provider:       provider,
+		monitorService: monitors.CreateMonitorService(&provider),
+	}
+	r.providerServices.Store(id, service)
+	return service.monitorService
+}
+
+// getRecordedMonitorService returns the monitor service of the provider account recorded for a monitor, nil when
+// the account is no longer configured or its provider hasn't been set up since the controller started
+func (r *EndpointMonitorReconciler) getRecordedMonitorService(record monitorRecord) *monitors.MonitorServiceProxy {
+	if cached, ok := r.providerServices.Load(record.providerID); ok {
+		return cached.(*providerService).monitorService
+	}
+	for _, monitorService := range r.MonitorServices {
+		if recordedInAccount(record.provider, record.providerID, monitorService) {
+			return monitorService
+		}
+	}
+	return nil
+}
+
+// applyProviderDefaults fills the fields of the provider config on spec that aren't set from the
+// defaults. Nested objects are merged as well, lists are taken as a whole.
+func applyProviderDefaults(spec *endpointmonitorv1alpha1.EndpointMonitorSpec, defaults *endpointmonitorv1alpha1.MonitorProviderDefaults, monitorType string) error {
+	if !hasProviderDefaults(defaults, monitorType) {
+		return nil
+	}
+	fieldName := providerConfigFields[monitorType]
+	defaultsField := reflect.ValueOf(defaults).Elem().FieldByName(fieldName)
+	specField := reflect.ValueOf(spec).Elem().FieldByName(fieldName)
+	if specField.IsNil() {
+		specField.Set(reflect.New(specField.Type().Elem()))
+	}
+
+	target := specField.Interface()
+	current, err := toJSONObject(target)
+	if err != nil {
+		return err
+	}
+	values, err := toJSONObject(defaultsField.Interface())
+	if err != nil {
+		return err
+	}
+	missing := missingFields(current, values)
+
+	if raw, ok := target.(*runtime.RawExtension); ok {
+		// A RawExtension is replaced as a whole when unmarshalled, so merge into the current fields
+		merged, err := json.Marshal(mergeFields(current, missing))
+		if err != nil {
+			return err
+		}
+		raw.Raw = merged
+		return nil
+	}
+
+	// Unmarshalling only the missing fields keeps the values that aren't serialized, e.g. resolved passwords
+	patch, err := json.Marshal(missing)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(patch, target)
+}
+
+// hasProviderDefaults returns whether defaults holds a provider config for monitorType
+func hasProviderDefaults(defaults *endpointmonitorv1alpha1.MonitorProviderDefaults, monitorType string) bool {
+	fieldName, ok := providerConfigFields[monitorType]
+	if defaults == nil || !ok {
+		return false
+	}
+	return !reflect.ValueOf(defaults).Elem().FieldByName(fieldName).IsNil()
+}
+
+// missingFields returns the fields of defaults that aren't set in current, descending into objects set in both
+func missingFields(current map[string]interface{}, defaults map[string]interface{}) map[string]interface{} {
+	missing := map[string]interface{}{}
+	for key, value := range defaults {
+		currentValue, ok := current[key]
+		if !ok {
+			missing[key] = value
+			continue
+		}
+		currentObject, currentIsObject := currentValue.(map[string]interface{})
+		defaultObject, defaultIsObject := value.(map[string]interface{})
+		if currentIsObject && defaultIsObject {
+			if nested := missingFields(currentObject, defaultObject); len(nested) > 0 {
+				missing[key] = nested
+			}
+		}
+	}
+	return missing
+}
+
+// mergeFields adds the fields of missing to current, descending into objects set in both
+func mergeFields(current map[string]interface{}, missing map[string]interface{}) map[string]interface{} {
+	for key, value := range missing {
+		currentObject, currentIsObject := current[key].(map[string]interface{})
+		missingObject, missingIsObject := value.(map[string]interface{})
+		if currentIsObject && missingIsObject {
+			current[key] = mergeFields(currentObject, missingObject)
+			continue
+		}
+		current[key] = value
+	}
+	return current
+}
+
+func toJSONObject(value interface{}) (map[string]interface{}, error) {
+	object := map[string]interface{}{}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return object, nil
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// providerRefIndexer indexes EndpointMonitors by the name of the MonitorProvider they reference, or by the kind and
+// name of the ClusterMonitorProvider
+func providerRefIndexer(obj client.Object) []string {
+	ref := obj.(*endpointmonitorv1alpha1.EndpointMonitor).Spec.ProviderRef
+	if ref == nil {
+		return nil
+	}
+	if ref.Kind == endpointmonitorv1alpha1.ClusterMonitorProviderKind {
+		return []string{endpointmonitorv1alpha1.ClusterMonitorProviderKind + "/" + ref.Name}
+	}
+	return []string{ref.Name}
+}
+
+// endpointMonitorsForProvider enqueues the EndpointMonitors referencing a MonitorProvider or ClusterMonitorProvider
+// when it changes
+func (r *EndpointMonitorReconciler) endpointMonitorsForProvider(ctx context.Context, provider client.Object) []reconcile.Request {
+	endpointMonitors := &endpointmonitorv1alpha1.EndpointMonitorList{}
+	opts := []client.ListOption{client.InNamespace(provider.GetNamespace()), client.MatchingFields{providerRefIndexField: provider.GetName()}}
+	if _, ok := provider.(*endpointmonitorv1alpha1.ClusterMonitorProvider); ok {
+		opts = []client.ListOption{client.MatchingFields{providerRefIndexField: endpointmonitorv1alpha1.ClusterMonitorProviderKind + "/" + provider.GetName()}}
+	}
+	if err := r.List(ctx, endpointMonitors, opts...); err != nil {
+		log.Error(err, "Failed to list EndpointMonitors referencing provider "+provider.GetNamespace()+"/"+provider.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(endpointMonitors.Items))
+	for _, endpointMonitor := range endpointMonitors.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: endpointMonitor.Name, Namespace: endpointMonitor.Namespace}})
+	}
+	return requests
+}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func newProviderTestReconciler(t *testing.T, allowedNamespaces []string, objects ...client.Object) *EndpointMonitorReconciler {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NilError(t, endpointmonitorv1alpha1.AddToScheme(scheme))
+
+	// Answer SubjectAccessReviews like RBAC granting "use" to the allowed namespaces
+	funcs := interceptor.Funcs{Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+		if review, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+			for _, namespace := range allowedNamespaces {
+				if review.Spec.ResourceAttributes.Namespace == namespace && review.Spec.ResourceAttributes.Verb == useVerb {
+					review.Status.Allowed = true
+				}
+			}
+			return nil
+		}
+		return c.Create(ctx, obj, opts...)
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithStatusSubresource(&endpointmonitorv1alpha1.EndpointMonitor{}, &endpointmonitorv1alpha1.ControllerStatus{}).
+		WithIndex(&endpointmonitorv1alpha1.EndpointMonitor{}, monitorIDIndexField, monitorIDIndexer).
+		WithIndex(&endpointmonitorv1alpha1.EndpointMonitor{}, monitorNameIndexField, monitorNameIndexer).
+		WithIndex(&endpointmonitorv1alpha1.EndpointMonitor{}, providerRefIndexField, providerRefIndexer).
+		WithInterceptorFuncs(funcs).Build()
+	return &EndpointMonitorReconciler{Client: c}
+}
+
+func TestGetMonitorServiceForMonitorProvider(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "uptimerobot", Namespace: "shop"},
+		Data:       map[string][]byte{"apiKey": []byte("team-api-key")},
+	}
+	provider := &endpointmonitorv1alpha1.MonitorProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "uptimerobot", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.MonitorProviderSpec{
+			Type:                 monitors.TypeUptimeRobot,
+			APIURL:               "https://api.uptimerobot.com/v2/",
+			CredentialsSecretRef: &endpointmonitorv1alpha1.ProviderSecretReference{Name: "uptimerobot"},
+			Defaults: &endpointmonitorv1alpha1.MonitorProviderDefaults{
+				UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{Interval: 300, AlertContacts: "team"},
+			},
+		},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			ProviderRef: &endpointmonitorv1alpha1.ProviderReference{Kind: endpointmonitorv1alpha1.MonitorProviderKind, Name: "uptimerobot"},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, secret, provider)
+
+	monitorService, defaults, err := r.getMonitorService(instance)
+	assert.NilError(t, err)
+	assert.Equal(t, monitorService.GetType(), monitors.TypeUptimeRobot)
+	assert.Equal(t, monitorService.GetID(), "MonitorProvider/shop/uptimerobot")
+
+	// The monitor service is reused until the provider changes
+	again, _, err := r.getMonitorService(instance)
+	assert.NilError(t, err)
+	assert.Assert(t, again == monitorService)
+
+	spec := *instance.Spec.DeepCopy()
+	assert.NilError(t, applyProviderDefaults(&spec, defaults, monitorService.GetType()))
+	assert.Equal(t, spec.UptimeRobotConfig.Interval, 300)
+
+	instance.Spec.StatusCakeConfig = &endpointmonitorv1alpha1.StatusCakeConfig{}
+	_, _, err = r.getMonitorService(instance)
+	assert.ErrorContains(t, err, "is of type UptimeRobot")
+}
+
+func TestGetMonitorServiceForClusterMonitorProvider(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "statuscake", Namespace: "monitoring"},
+		Data:       map[string][]byte{"apiKey": []byte("shared-api-key")},
+	}
+	provider := &endpointmonitorv1alpha1.ClusterMonitorProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "statuscake"},
+		Spec: endpointmonitorv1alpha1.MonitorProviderSpec{
+			Type:                 monitors.TypeStatusCake,
+			CredentialsSecretRef: &endpointmonitorv1alpha1.ProviderSecretReference{Name: "statuscake", Namespace: "monitoring"},
+		},
+	}
+	newInstance := func(namespace string) *endpointmonitorv1alpha1.EndpointMonitor {
+		return &endpointmonitorv1alpha1.EndpointMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: namespace},
+			Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+				ProviderRef: &endpointmonitorv1alpha1.ProviderReference{Kind: endpointmonitorv1alpha1.ClusterMonitorProviderKind, Name: "statuscake"},
+			},
+		}
+	}
+	r := newProviderTestReconciler(t, []string{"shop"}, secret, provider)
+
+	monitorService, _, err := r.getMonitorService(newInstance("shop"))
+	assert.NilError(t, err)
+	assert.Equal(t, monitorService.GetID(), "ClusterMonitorProvider/statuscake")
+
+	_, _, err = r.getMonitorService(newInstance("blog"))
+	assert.ErrorContains(t, err, "namespace blog is not allowed to use ClusterMonitorProvider statuscake")
+}
+
+func TestGetMonitorServiceLimitsMonitorProviderConfig(t *testing.T) {
+	newProvider := func(name string, providerType string, config string) *endpointmonitorv1alpha1.MonitorProvider {
+		return &endpointmonitorv1alpha1.MonitorProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: endpointmonitorv1alpha1.MonitorProviderSpec{
+				Type:   providerType,
+				Config: &runtime.RawExtension{Raw: []byte(config)},
+			},
+		}
+	}
+	newInstance := func(provider string) *endpointmonitorv1alpha1.EndpointMonitor {
+		return &endpointmonitorv1alpha1.EndpointMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+			Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+				ProviderRef: &endpointmonitorv1alpha1.ProviderReference{Kind: endpointmonitorv1alpha1.MonitorProviderKind, Name: provider},
+			},
+		}
+	}
+	r := newProviderTestReconciler(t, nil,
+		newProvider("blackbox-file", monitors.TypeBlackbox, `{"apiKeyFile":"/var/run/secrets/kubernetes.io/serviceaccount/token"}`),
+		newProvider("appinsights", monitors.TypeAppInsights, `{"appInsightsConfig":{"subscriptionId":"controller"}}`),
+		newProvider("webhook", monitors.TypeWebhook, `{"webhookConfig":{"url":"http://127.0.0.1:8080/monitors"}}`),
+		newProvider("plugin", monitors.TypePlugin, `{"pluginConfig":{"address":"unix:///var/run/plugin.sock"}}`),
+		newProvider("blackbox", monitors.TypeBlackbox, `{"blackboxConfig":{"proberURL":"http://blackbox-exporter.shop.svc:9115"}}`),
+		newProvider("blackbox-monitoring", monitors.TypeBlackbox, `{"blackboxConfig":{"proberURL":"http://blackbox-exporter.shop.svc:9115","namespace":"monitoring"}}`),
+	)
+
+	// Credentials of the controller can't be reached through the config of a MonitorProvider
+	_, _, err := r.getMonitorService(newInstance("blackbox-file"))
+	assert.ErrorContains(t, err, "field apiKeyFile not found")
+
+	_, _, err = r.getMonitorService(newInstance("appinsights"))
+	assert.ErrorContains(t, err, "MonitorProvider/shop/appinsights can't be of type AppInsights")
+
+	// Nor can the network of the controller
+	_, _, err = r.getMonitorService(newInstance("webhook"))
+	assert.ErrorContains(t, err, "MonitorProvider/shop/webhook can't be of type Webhook")
+	_, _, err = r.getMonitorService(newInstance("plugin"))
+	assert.ErrorContains(t, err, "MonitorProvider/shop/plugin can't be of type Plugin")
+
+	// The Probes of a MonitorProvider are kept in its own namespace
+	_, _, err = r.getMonitorService(newInstance("blackbox"))
+	assert.NilError(t, err)
+	cached, ok := r.providerServices.Load("MonitorProvider/shop/blackbox")
+	assert.Assert(t, ok)
+	assert.Equal(t, cached.(*providerService).provider.BlackboxConfig.Namespace, "shop")
+
+	_, _, err = r.getMonitorService(newInstance("blackbox-monitoring"))
+	assert.ErrorContains(t, err, "blackboxConfig.namespace of a MonitorProvider must be its own namespace shop")
+}
+
+func TestEndpointMonitorsForProvider(t *testing.T) {
+	newInstance := func(namespace string, kind string, provider string) *endpointmonitorv1alpha1.EndpointMonitor {
+		return &endpointmonitorv1alpha1.EndpointMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: kind + "-" + provider, Namespace: namespace},
+			Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+				ProviderRef: &endpointmonitorv1alpha1.ProviderReference{Kind: kind, Name: provider},
+			},
+		}
+	}
+	r := newProviderTestReconciler(t, nil,
+		newInstance("shop", endpointmonitorv1alpha1.MonitorProviderKind, "statuscake"),
+		newInstance("blog", endpointmonitorv1alpha1.MonitorProviderKind, "statuscake"),
+		newInstance("shop", endpointmonitorv1alpha1.ClusterMonitorProviderKind, "statuscake"),
+		newInstance("blog", endpointmonitorv1alpha1.ClusterMonitorProviderKind, "statuscake"),
+	)
+
+	requests := r.endpointMonitorsForProvider(context.TODO(), &endpointmonitorv1alpha1.MonitorProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "statuscake", Namespace: "shop"},
+	})
+	assert.DeepEqual(t, requests, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "MonitorProvider-statuscake", Namespace: "shop"}},
+	})
+
+	// A ClusterMonitorProvider is referenced from every namespace
+	requests = r.endpointMonitorsForProvider(context.TODO(), &endpointmonitorv1alpha1.ClusterMonitorProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "statuscake"},
+	})
+	assert.DeepEqual(t, requests, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "ClusterMonitorProvider-statuscake", Namespace: "blog"}},
+		{NamespacedName: types.NamespacedName{Name: "ClusterMonitorProvider-statuscake", Namespace: "shop"}},
+	})
+}
+
+func TestApplyProviderDefaults(t *testing.T) {
+	spec := endpointmonitorv1alpha1.EndpointMonitorSpec{
+		PingdomConfig: &endpointmonitorv1alpha1.PingdomConfig{
+			Resolution:        5,
+			BasicAuthPassword: "resolved",
+		},
+		WebhookConfig: &runtime.RawExtension{Raw: []byte(`{"team":"checkout","labels":{"tier":"frontend"}}`)},
+	}
+	defaults := &endpointmonitorv1alpha1.MonitorProviderDefaults{
+		PingdomConfig: &endpointmonitorv1alpha1.PingdomConfig{Resolution: 1, SendNotificationWhenDown: 3},
+		WebhookConfig: &runtime.RawExtension{Raw: []byte(`{"team":"platform","severity":"page","labels":{"tier":"backend","env":"prod"}}`)},
+	}
+
+	assert.NilError(t, applyProviderDefaults(&spec, defaults, monitors.TypePingdom))
+	assert.Equal(t, spec.PingdomConfig.Resolution, 5)
+	assert.Equal(t, spec.PingdomConfig.SendNotificationWhenDown, 3)
+	assert.Equal(t, spec.PingdomConfig.BasicAuthPassword, "resolved")
+
+	assert.NilError(t, applyProviderDefaults(&spec, defaults, monitors.TypeWebhook))
+	assert.Equal(t, string(spec.WebhookConfig.Raw), `{"labels":{"env":"prod","tier":"frontend"},"severity":"page","team":"checkout"}`)
+}
@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	kubeutil "github.com/stakater/IngressMonitorController/v2/pkg/kube/util"
+	"github.com/stakater/IngressMonitorController/v2/pkg/util"
+)
+
+const (
+	// defaultReadinessMaxWait is how long the creation of a monitor is held when the readinessGate sets no maxWait
+	defaultReadinessMaxWait = 15 * time.Minute
+
+	// readinessRequeueTime is how often the endpoint is checked while the creation of the monitor is held
+	readinessRequeueTime = 20 * time.Second
+
+	// readinessProbeTimeout bounds the HTTP probe of the url, kept short as the probe blocks the reconcile loop and
+	// an endpoint that is slow to answer is checked again after readinessRequeueTime
+	readinessProbeTimeout = 2 * time.Second
+)
+
+// gateOnEndpointReadiness holds the creation of the monitor until the endpoint is live, see spec.readinessGate.
+// It returns when to check the endpoint again while the creation is held, 0 once the monitor can be created.
+func (r *EndpointMonitorReconciler) gateOnEndpointReadiness(instance *endpointmonitorv1alpha1.EndpointMonitor, certificateNotFound error) (time.Duration, error) {
+	gate := instance.Spec.ReadinessGate
+	if gate == nil {
+		return 0, nil
+	}
+	waitingFor, err := r.endpointWaitingFor(instance, certificateNotFound)
+	if err != nil {
+		return 0, err
+	}
+
+	condition := metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeEndpointReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             endpointmonitorv1alpha1.ReasonEndpointReady,
+		Message:            "The endpoint is live",
+		ObservedGeneration: instance.Generation,
+	}
+	var requeueAfter time.Duration
+	if len(waitingFor) > 0 {
+		maxWait := defaultReadinessMaxWait
+		if gate.MaxWait != nil {
+			maxWait = gate.MaxWait.Duration
+		}
+		remaining := time.Until(instance.CreationTimestamp.Add(maxWait))
+		condition.Status = metav1.ConditionFalse
+		if remaining > 0 {
+			condition.Reason = endpointmonitorv1alpha1.ReasonWaitingForEndpoint
+			condition.Message = "Waiting for " + strings.Join(waitingFor, "; ")
+			requeueAfter = min(readinessRequeueTime, remaining)
+		} else {
+			condition.Reason = endpointmonitorv1alpha1.ReasonReadinessTimeout
+			condition.Message = "Created the monitor after waiting " + maxWait.String() + " for " + strings.Join(waitingFor, "; ")
+		}
+	}
+	if meta.SetStatusCondition(&instance.Status.Conditions, condition) {
+		if err := r.Status().Update(context.TODO(), instance); err != nil {
+			return 0, err
+		}
+	}
+	return requeueAfter, nil
+}
+
+// endpointWaitingFor returns what the endpoint of the EndpointMonitor still waits for, nothing once it is live
+func (r *EndpointMonitorReconciler) endpointWaitingFor(instance *endpointmonitorv1alpha1.EndpointMonitor, certificateNotFound error) ([]string, error) {
+	var waitingFor []string
+	if urlFrom := instance.Spec.URLFrom; urlFrom != nil {
+		var err error
+		switch {
+		case urlFrom.IngressRef != nil:
+			waitingFor, err = r.ingressWaitingFor(urlFrom.IngressRef.Name, instance.Namespace)
+		case urlFrom.RouteRef != nil:
+			waitingFor, err = r.routeWaitingFor(urlFrom.RouteRef.Name, instance.Namespace)
+		case urlFrom.ServiceRef != nil:
+			waitingFor, err = r.serviceWaitingFor(urlFrom.ServiceRef.Name, instance.Namespace, true)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if certificateNotFound != nil {
+		waitingFor = append(waitingFor, certificateNotFound.Error())
+	}
+	// The url can't answer before its resources are provisioned
+	if len(waitingFor) > 0 || instance.Spec.ReadinessGate.SkipProbe || getCheckType(instance) != endpointmonitorv1alpha1.CheckTypeHTTP {
+		return waitingFor, nil
+	}
+
+	url, err := kubeutil.GetMonitorURL(r.Client, r.apiReader(), instance)
+	if err != nil {
+		return []string{"the url: " + err.Error()}, nil
+	}
+	if err := probeURL(url); err != nil {
+		return []string{"a successful HTTP probe of " + url + ": " + err.Error()}, nil
+	}
+	return nil, nil
+}
+
+// ingressWaitingFor returns what the Ingress waits for, its load-balancer address, the ready endpoints of its
+// backend Services and its TLS Secrets
+func (r *EndpointMonitorReconciler) ingressWaitingFor(name string, namespace string) ([]string, error) {
+	ingress := &networkingv1.Ingress{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, ingress); err != nil {
+		if errors.IsNotFound(err) {
+			return []string{"Ingress " + name + " to be created"}, nil
+		}
+		return nil, err
+	}
+
+	var waitingFor []string
+	if len(ingress.Status.LoadBalancer.Ingress) == 0 {
+		waitingFor = append(waitingFor, "a load-balancer address of Ingress "+name)
+	}
+
+	var services []string
+	addService := func(backend *networkingv1.IngressBackend) {
+		if backend != nil && backend.Service != nil && !util.ContainsString(services, backend.Service.Name) {
+			services = append(services, backend.Service.Name)
+		}
+	}
+	addService(ingress.Spec.DefaultBackend)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			addService(&path.Backend)
+		}
+	}
+	for _, service := range services {
+		serviceWaitingFor, err := r.serviceWaitingFor(service, namespace, false)
+		if err != nil {
+			return nil, err
+		}
+		waitingFor = append(waitingFor, serviceWaitingFor...)
+	}
+
+	for _, tls := range ingress.Spec.TLS {
+		if len(tls.SecretName) == 0 {
+			continue
+		}
+		if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: tls.SecretName, Namespace: namespace}, &corev1.Secret{}); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			waitingFor = append(waitingFor, "TLS Secret "+tls.SecretName)
+		}
+	}
+	return waitingFor, nil
+}
+
+// routeWaitingFor returns what the Route waits for, to be admitted by a router and the ready endpoints of its
+// Service
+func (r *EndpointMonitorReconciler) routeWaitingFor(name string, namespace string) ([]string, error) {
+	route := &routev1.Route{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, route); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return []string{"Route " + name + " to be created"}, nil
+		}
+		return nil, err
+	}
+
+	var waitingFor []string
+	if !routeAdmitted(route) {
+		waitingFor = append(waitingFor, "Route "+name+" to be admitted")
+	}
+	if route.Spec.To.Kind == "Service" {
+		serviceWaitingFor, err := r.serviceWaitingFor(route.Spec.To.Name, namespace, false)
+		if err != nil {
+			return nil, err
+		}
+		waitingFor = append(waitingFor, serviceWaitingFor...)
+	}
+	return waitingFor, nil
+}
+
+func routeAdmitted(route *routev1.Route) bool {
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// serviceWaitingFor returns what the Service waits for, ready endpoints and the load-balancer address of
+// LoadBalancer Services monitored through their address
+func (r *EndpointMonitorReconciler) serviceWaitingFor(name string, namespace string, needsAddress bool) ([]string, error) {
+	service := &corev1.Service{}
+	if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, service); err != nil {
+		if errors.IsNotFound(err) {
+			return []string{"Service " + name + " to be created"}, nil
+		}
+		return nil, err
+	}
+
+	var waitingFor []string
+	if needsAddress && service.Spec.Type == corev1.ServiceTypeLoadBalancer && len(service.Status.LoadBalancer.Ingress) == 0 {
+		waitingFor = append(waitingFor, "a load-balancer address of Service "+name)
+	}
+	// ExternalName Services have no endpoints
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return waitingFor, nil
+	}
+
+	endpointSlices := &discoveryv1.EndpointSliceList{}
+	if err := r.apiReader().List(context.TODO(), endpointSlices, client.InNamespace(namespace), client.MatchingLabels{discoveryv1.LabelServiceName: name}); err != nil {
+		return nil, err
+	}
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return waitingFor, nil
+			}
+		}
+	}
+	return append(waitingFor, "ready endpoints of Service "+name), nil
+}
+
+// probeURL sends a GET request to the url, which succeeds when it is answered without an error status
+func probeURL(url string) error {
+	httpClient := &http.Client{Timeout: readinessProbeTimeout}
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("status %d", response.StatusCode)
+	}
+	return nil
+}
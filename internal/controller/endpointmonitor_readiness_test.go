@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+func newReadinessTestObjects(serverURL string) (*endpointmonitorv1alpha1.EndpointMonitor, *networkingv1.Ingress, *corev1.Service) {
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", CreationTimestamp: metav1.Now()},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			URL:           serverURL,
+			URLFrom:       &endpointmonitorv1alpha1.URLSource{IngressRef: &endpointmonitorv1alpha1.IngressURLSource{Name: "checkout"}},
+			ReadinessGate: &endpointmonitorv1alpha1.ReadinessGate{},
+		},
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{Hosts: []string{"checkout.example.com"}, SecretName: "checkout-tls"}},
+			Rules: []networkingv1.IngressRule{{
+				Host: "checkout.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:    "/",
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "checkout"}},
+					}},
+				}},
+			}},
+		},
+	}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}}
+	return instance, ingress, service
+}
+
+func TestReadinessGateWaitsForIngress(t *testing.T) {
+	instance, ingress, service := newReadinessTestObjects("https://checkout.example.com")
+	r := newProviderTestReconciler(t, nil, instance, ingress, service)
+
+	requeueAfter, err := r.gateOnEndpointReadiness(instance, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, requeueAfter, readinessRequeueTime)
+	condition := meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeEndpointReady)
+	assert.Equal(t, condition.Reason, endpointmonitorv1alpha1.ReasonWaitingForEndpoint)
+	assert.Equal(t, condition.Message, "Waiting for a load-balancer address of Ingress checkout; ready endpoints of Service checkout; TLS Secret checkout-tls")
+
+	// The monitor is created anyway after maxWait
+	instance.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	requeueAfter, err = r.gateOnEndpointReadiness(instance, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, requeueAfter, time.Duration(0))
+	condition = meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeEndpointReady)
+	assert.Equal(t, condition.Reason, endpointmonitorv1alpha1.ReasonReadinessTimeout)
+}
+
+func TestReadinessGateProbesURL(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	instance, ingress, service := newReadinessTestObjects(server.URL)
+	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "203.0.113.10"}}
+	ready := true
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta:  metav1.ObjectMeta{Name: "checkout-abc", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "checkout"}},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.7"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "checkout-tls", Namespace: "shop"}}
+	r := newProviderTestReconciler(t, nil, instance, ingress, service, endpointSlice, secret)
+
+	requeueAfter, err := r.gateOnEndpointReadiness(instance, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, requeueAfter > 0)
+	condition := meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeEndpointReady)
+	assert.Equal(t, condition.Message, "Waiting for a successful HTTP probe of "+server.URL+": status 503")
+
+	status = http.StatusOK
+	requeueAfter, err = r.gateOnEndpointReadiness(instance, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, requeueAfter, time.Duration(0))
+	condition = meta.FindStatusCondition(instance.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeEndpointReady)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+}
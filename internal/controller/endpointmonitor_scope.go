@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// isInScope returns whether the EndpointMonitor is handled by this controller instance
+func (r *EndpointMonitorReconciler) isInScope(instance *endpointmonitorv1alpha1.EndpointMonitor) (bool, error) {
+	if r.EndpointMonitorSelector != nil && !r.EndpointMonitorSelector.Matches(labels.Set(instance.Labels)) {
+		return false, nil
+	}
+	if r.NamespaceSelector == nil {
+		return true, nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: instance.Namespace}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return r.NamespaceSelector.Matches(labels.Set(namespace.Labels)), nil
+}
+
+// getUncachedEndpointMonitor reads an EndpointMonitor missing from the cache from the API server. An EndpointMonitor
+// whose labels no longer match the selector is dropped from the cache but still exists, nil is returned for the others.
+func (r *EndpointMonitorReconciler) getUncachedEndpointMonitor(request reconcile.Request) (*endpointmonitorv1alpha1.EndpointMonitor, error) {
+	if r.EndpointMonitorSelector == nil {
+		return nil, nil
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{}
+	if err := r.apiReader().Get(context.TODO(), request.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return instance, nil
+}
+
+// releaseOutOfScope lets go of an EndpointMonitor that was dropped from the cache, as its deletion would go unseen.
+// One that is being deleted is finalized, the monitor of the others is left to the controller instance now handling
+// them.
+func (r *EndpointMonitorReconciler) releaseOutOfScope(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor) (reconcile.Result, error) {
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.handleFinalize(request, instance)
+	}
+	r.monitorRecords.Delete(request.NamespacedName)
+	r.deletionPolicies.Delete(request.NamespacedName)
+	if !controllerutil.RemoveFinalizer(instance, monitorCleanupFinalizer) {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, r.Update(context.TODO(), instance)
+}
+
+// isDeletedInScope returns whether an EndpointMonitor that is gone was deleted while it was handled by this
+// controller instance
+func (r *EndpointMonitorReconciler) isDeletedInScope(request reconcile.Request) (bool, error) {
+	if r.NamespaceSelector == nil {
+		return true, nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: request.Namespace}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			// The namespace is gone as well, only clean up the monitors handled before
+			_, handled := r.monitorRecords.Load(request.NamespacedName)
+			return handled, nil
+		}
+		return false, err
+	}
+	return r.NamespaceSelector.Matches(labels.Set(namespace.Labels)), nil
+}
+
+// endpointMonitorsForNamespace enqueues the EndpointMonitors of a namespace when its labels change, so
+// that they are picked up once the namespace matches the namespace selector
+func (r *EndpointMonitorReconciler) endpointMonitorsForNamespace(ctx context.Context, namespace client.Object) []reconcile.Request {
+	endpointMonitors := &endpointmonitorv1alpha1.EndpointMonitorList{}
+	if err := r.List(ctx, endpointMonitors, client.InNamespace(namespace.GetName())); err != nil {
+		log.Error(err, "Failed to list EndpointMonitors for Namespace "+namespace.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(endpointMonitors.Items))
+	for _, endpointMonitor := range endpointMonitors.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: endpointMonitor.Name, Namespace: endpointMonitor.Namespace}})
+	}
+	return requests
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestIsInScope(t *testing.T) {
+	prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"env": "prod"}}}
+	dev := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sandbox", Labels: map[string]string{"env": "dev"}}}
+	newInstance := func(namespace string, instanceLabels map[string]string) *endpointmonitorv1alpha1.EndpointMonitor {
+		return &endpointmonitorv1alpha1.EndpointMonitor{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: namespace, Labels: instanceLabels}}
+	}
+
+	r := newProviderTestReconciler(t, nil, prod, dev)
+	inScope, err := r.isInScope(newInstance("sandbox", nil))
+	assert.NilError(t, err)
+	assert.Assert(t, inScope, "everything is in scope without selectors")
+
+	r.NamespaceSelector = labels.SelectorFromSet(labels.Set{"env": "prod"})
+	r.EndpointMonitorSelector = labels.SelectorFromSet(labels.Set{"team": "checkout"})
+	tests := []struct {
+		name     string
+		instance *endpointmonitorv1alpha1.EndpointMonitor
+		want     bool
+	}{
+		{"matching", newInstance("shop", map[string]string{"team": "checkout"}), true},
+		{"namespace not matching", newInstance("sandbox", map[string]string{"team": "checkout"}), false},
+		{"labels not matching", newInstance("shop", map[string]string{"team": "blog"}), false},
+		{"namespace missing", newInstance("gone", map[string]string{"team": "checkout"}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inScope, err := r.isInScope(tt.instance)
+			assert.NilError(t, err)
+			assert.Equal(t, inScope, tt.want)
+		})
+	}
+}
+
+func TestIsDeletedInScope(t *testing.T) {
+	prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"env": "prod"}}}
+	newRequest := func(namespace, name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	}
+
+	r := newProviderTestReconciler(t, nil, prod)
+	r.NamespaceSelector = labels.SelectorFromSet(labels.Set{"env": "prod"})
+
+	inScope, err := r.isDeletedInScope(newRequest("shop", "checkout"))
+	assert.NilError(t, err)
+	assert.Assert(t, inScope)
+
+	inScope, err = r.isDeletedInScope(newRequest("gone", "checkout"))
+	assert.NilError(t, err)
+	assert.Assert(t, !inScope, "nothing was handled in the deleted namespace")
+
+	r.monitorRecords.Store(types.NamespacedName{Name: "checkout", Namespace: "gone"}, monitorRecord{displayName: "checkout-gone"})
+	inScope, err = r.isDeletedInScope(newRequest("gone", "checkout"))
+	assert.NilError(t, err)
+	assert.Assert(t, inScope, "monitors handled in a deleted namespace are cleaned up")
+}
+
+func TestReconcileReleasesOutOfScope(t *testing.T) {
+	healthchecks, fake := newFakeHealthchecks(t, "team", map[string]string{"1": "checkout-shop", "2": "blog-shop"})
+	deleted := metav1.Now()
+	// Both were relabeled, so they are no longer cached by this instance but still exist
+	newInstance := func(name string, monitorID string) *endpointmonitorv1alpha1.EndpointMonitor {
+		return &endpointmonitorv1alpha1.EndpointMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "shop", Labels: map[string]string{"team": "other"}, Finalizers: []string{monitorCleanupFinalizer},
+			},
+			Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+				ProviderID:         "team",
+				DeletionPolicy:     endpointmonitorv1alpha1.DeletionPolicyDelete,
+				HealthchecksConfig: &endpointmonitorv1alpha1.HealthchecksConfig{},
+			},
+			Status: endpointmonitorv1alpha1.EndpointMonitorStatus{
+				MonitorName: name + "-shop",
+				MonitorID:   monitorID,
+				Provider:    monitors.TypeHealthchecks,
+				ProviderID:  "team",
+			},
+		}
+	}
+	checkout := newInstance("checkout", "1")
+	checkout.DeletionTimestamp = &deleted
+	blog := newInstance("blog", "2")
+	r := newProviderTestReconciler(t, nil, checkout, blog)
+	r.MonitorServices = []*monitors.MonitorServiceProxy{healthchecks}
+	r.EndpointMonitorSelector = labels.SelectorFromSet(labels.Set{"team": "checkout"})
+
+	// The cache only holds the EndpointMonitors matching the selector
+	r.APIReader = r.Client
+	r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if err := c.Get(ctx, key, obj, opts...); err != nil {
+				return err
+			}
+			if _, ok := obj.(*endpointmonitorv1alpha1.EndpointMonitor); ok && !r.EndpointMonitorSelector.Matches(labels.Set(obj.GetLabels())) {
+				return errors.NewNotFound(endpointmonitorv1alpha1.GroupVersion.WithResource("endpointmonitors").GroupResource(), key.Name)
+			}
+			return nil
+		},
+	})
+
+	// The EndpointMonitor being deleted is still finalized
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "shop"}})
+	assert.NilError(t, err)
+	err = r.APIReader.Get(context.TODO(), types.NamespacedName{Name: "checkout", Namespace: "shop"}, &endpointmonitorv1alpha1.EndpointMonitor{})
+	assert.Assert(t, errors.IsNotFound(err), "EndpointMonitor checkout is released")
+	assert.DeepEqual(t, fake.names(), []string{"blog-shop"})
+
+	// The other one is released to the controller instance now handling it, its deletion would go unseen
+	_, err = r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "blog", Namespace: "shop"}})
+	assert.NilError(t, err)
+	stored := &endpointmonitorv1alpha1.EndpointMonitor{}
+	assert.NilError(t, r.APIReader.Get(context.TODO(), types.NamespacedName{Name: "blog", Namespace: "shop"}, stored))
+	assert.Assert(t, !controllerutil.ContainsFinalizer(stored, monitorCleanupFinalizer))
+	assert.DeepEqual(t, fake.names(), []string{"blog-shop"})
+}
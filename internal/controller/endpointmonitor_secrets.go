@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+const secretRefIndexField = "spec.secretRefs"
+
+// secretField is a sensitive spec field whose value is read from a Secret
+type secretField struct {
+	source *endpointmonitorv1alpha1.ValueSource
+	// apply sets the resolved value on the spec
+	apply func(value string) error
+}
+
+// secretFields returns all fields of spec that reference a Secret
+func secretFields(spec *endpointmonitorv1alpha1.EndpointMonitorSpec) []secretField {
+	var fields []secretField
+	add := func(source *endpointmonitorv1alpha1.ValueSource, apply func(value string) error) {
+		if source != nil && source.SecretKeyRef != nil {
+			fields = append(fields, secretField{source: source, apply: apply})
+		}
+	}
+	setString := func(target *string) func(string) error {
+		return func(value string) error {
+			*target = value
+			return nil
+		}
+	}
+
+	if c := spec.UptimeRobotConfig; c != nil {
+		add(c.KeywordValueFrom, setString(&c.KeywordValue))
+	}
+	if c := spec.StatusCakeConfig; c != nil {
+		add(c.BasicAuthPasswordFrom, setString(&c.BasicAuthPassword))
+		add(c.FindStringFrom, setString(&c.FindString))
+		add(c.RawPostDataFrom, setString(&c.RawPostData))
+	}
+	if c := spec.PingdomConfig; c != nil {
+		add(c.RequestHeadersFrom, func(value string) error {
+			headers, err := mergeJSONObjects(c.RequestHeaders, value)
+			if err != nil {
+				return fmt.Errorf("requestHeadersFrom must hold a JSON object: %w", err)
+			}
+			c.RequestHeaders = headers
+			return nil
+		})
+		add(c.BasicAuthPasswordFrom, setString(&c.BasicAuthPassword))
+		add(c.ShouldContainFrom, setString(&c.ShouldContain))
+		add(c.PostDataFrom, setString(&c.PostData))
+	}
+	if c := spec.PingdomTransactionConfig; c != nil {
+		for i := range c.Steps {
+			step := &c.Steps[i]
+			for key := range step.ArgsFrom {
+				source := step.ArgsFrom[key]
+				add(&source, func(value string) error {
+					if step.Args == nil {
+						step.Args = map[string]string{}
+					}
+					step.Args[key] = value
+					return nil
+				})
+			}
+		}
+	}
+	return fields
+}
+
+// mergeJSONObjects merges the keys of the JSON object override into the JSON object base
+func mergeJSONObjects(base string, override string) (string, error) {
+	merged := map[string]string{}
+	if len(base) > 0 {
+		if err := json.Unmarshal([]byte(base), &merged); err != nil {
+			return "", err
+		}
+	}
+	overrides := map[string]string{}
+	if err := json.Unmarshal([]byte(override), &overrides); err != nil {
+		return "", err
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	result, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// resolveSecretRefs returns a copy of the spec of the EndpointMonitor with the values of all
+// Secret references filled in. The EndpointMonitor itself is left untouched so resolved values
+// are never written back to the cluster.
+func (r *EndpointMonitorReconciler) resolveSecretRefs(instance *endpointmonitorv1alpha1.EndpointMonitor) (endpointmonitorv1alpha1.EndpointMonitorSpec, error) {
+	spec := *instance.Spec.DeepCopy()
+	for _, field := range secretFields(&spec) {
+		ref := field.source.SecretKeyRef
+		value, err := r.readSecretKey(instance.Namespace, ref)
+		if err != nil {
+			if ref.Optional != nil && *ref.Optional {
+				continue
+			}
+			return spec, err
+		}
+		if err := field.apply(value); err != nil {
+			return spec, fmt.Errorf("secret %s/%s key %s: %w", instance.Namespace, ref.Name, ref.Key, err)
+		}
+	}
+	return spec, nil
+}
+
+// readSecretKey reads a key of a Secret. Secrets are read without the cache so that the
+// controller doesn't have to keep every Secret of the cluster in memory.
+func (r *EndpointMonitorReconciler) readSecretKey(namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.apiReader().Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("secret %s/%s referenced by the EndpointMonitor not found", namespace, ref.Name)
+		}
+		return "", err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s does not contain key %s", namespace, ref.Name, ref.Key)
+	}
+	return string(value), nil
+}
+
+// apiReader returns the reader for objects that aren't cached, falling back to the Client
+func (r *EndpointMonitorReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// secretRefIndexer indexes EndpointMonitors by the names of the Secrets they reference
+func secretRefIndexer(obj client.Object) []string {
+	endpointMonitor := obj.(*endpointmonitorv1alpha1.EndpointMonitor)
+	spec := endpointMonitor.Spec.DeepCopy()
+
+	var names []string
+	seen := map[string]bool{}
+	for _, field := range secretFields(spec) {
+		name := field.source.SecretKeyRef.Name
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// endpointMonitorsForSecret enqueues the EndpointMonitors referencing a Secret when it changes
+func (r *EndpointMonitorReconciler) endpointMonitorsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	endpointMonitors := &endpointmonitorv1alpha1.EndpointMonitorList{}
+	if err := r.List(ctx, endpointMonitors, client.InNamespace(secret.GetNamespace()), client.MatchingFields{secretRefIndexField: secret.GetName()}); err != nil {
+		log.Error(err, "Failed to list EndpointMonitors referencing secret "+secret.GetNamespace()+"/"+secret.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(endpointMonitors.Items))
+	for _, endpointMonitor := range endpointMonitors.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: endpointMonitor.Name, Namespace: endpointMonitor.Namespace}})
+	}
+	return requests
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+func newSecretRef(name, key string) *endpointmonitorv1alpha1.ValueSource {
+	return &endpointmonitorv1alpha1.ValueSource{SecretKeyRef: &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}}
+}
+
+func newSecretTestReconciler(t *testing.T, objects ...runtime.Object) *EndpointMonitorReconciler {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NilError(t, endpointmonitorv1alpha1.AddToScheme(scheme))
+	return &EndpointMonitorReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()}
+}
+
+func TestResolveSecretRefs(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout-monitor", Namespace: "shop"},
+		Data: map[string][]byte{
+			"password": []byte("s3cr3t"),
+			"headers":  []byte(`{"Authorization":"Bearer token"}`),
+			"postData": []byte("user=monitor"),
+		},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			PingdomConfig: &endpointmonitorv1alpha1.PingdomConfig{
+				BasicAuthUser:         "monitor",
+				BasicAuthPasswordFrom: newSecretRef("checkout-monitor", "password"),
+				RequestHeaders:        `{"X-Team":"checkout"}`,
+				RequestHeadersFrom:    newSecretRef("checkout-monitor", "headers"),
+				PostDataFrom:          newSecretRef("checkout-monitor", "postData"),
+			},
+		},
+	}
+	r := newSecretTestReconciler(t, secret)
+
+	spec, err := r.resolveSecretRefs(instance)
+	assert.NilError(t, err)
+	assert.Equal(t, spec.PingdomConfig.BasicAuthPassword, "s3cr3t")
+	assert.Equal(t, spec.PingdomConfig.PostData, "user=monitor")
+	assert.Equal(t, spec.PingdomConfig.RequestHeaders, `{"Authorization":"Bearer token","X-Team":"checkout"}`)
+
+	// The EndpointMonitor itself must never hold resolved values
+	assert.Equal(t, instance.Spec.PingdomConfig.BasicAuthPassword, "")
+	assert.Equal(t, instance.Spec.PingdomConfig.RequestHeaders, `{"X-Team":"checkout"}`)
+
+	assert.DeepEqual(t, secretRefIndexer(instance), []string{"checkout-monitor"})
+}
+
+func TestResolveSecretRefsForTransactionSteps(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "login", Namespace: "shop"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			PingdomTransactionConfig: &endpointmonitorv1alpha1.PingdomTransactionConfig{
+				Steps: []endpointmonitorv1alpha1.PingdomStep{{
+					Function: "basic_auth",
+					Args:     map[string]string{"user": "admin"},
+					ArgsFrom: map[string]endpointmonitorv1alpha1.ValueSource{"password": *newSecretRef("login", "password")},
+				}},
+			},
+		},
+	}
+	r := newSecretTestReconciler(t, secret)
+
+	spec, err := r.resolveSecretRefs(instance)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, spec.PingdomTransactionConfig.Steps[0].Args, map[string]string{"user": "admin", "password": "s3cr3t"})
+	assert.Equal(t, len(instance.Spec.PingdomTransactionConfig.Steps[0].Args), 1)
+}
+
+func TestResolveSecretRefsWithMissingSecret(t *testing.T) {
+	optional := true
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{
+				KeywordValue:     "fallback",
+				KeywordValueFrom: newSecretRef("missing", "keyword"),
+			},
+		},
+	}
+	r := newSecretTestReconciler(t)
+
+	_, err := r.resolveSecretRefs(instance)
+	assert.ErrorContains(t, err, "shop/missing")
+
+	instance.Spec.UptimeRobotConfig.KeywordValueFrom.SecretKeyRef.Optional = &optional
+	spec, err := r.resolveSecretRefs(instance)
+	assert.NilError(t, err)
+	assert.Equal(t, spec.UptimeRobotConfig.KeywordValue, "fallback")
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+// getEffectiveSpec returns a copy of the spec of the EndpointMonitor with the provider config derived from
+// spec.check and spec.tlsCertificate, the provider config of the matching EndpointMonitorTemplates and the defaults of the provider merged
+// under it, together with the names of the templates that were applied. Templates are applied in the order of their
+// names, so the first one wins when several set the same field.
+func (r *EndpointMonitorReconciler) getEffectiveSpec(instance *endpointmonitorv1alpha1.EndpointMonitor, derivedConfigs []*endpointmonitorv1alpha1.MonitorProviderDefaults, defaults *endpointmonitorv1alpha1.MonitorProviderDefaults, monitorType string) (endpointmonitorv1alpha1.EndpointMonitorSpec, []string, error) {
+	spec := *instance.Spec.DeepCopy()
+	for _, derivedConfig := range derivedConfigs {
+		if err := applyProviderDefaults(&spec, derivedConfig, monitorType); err != nil {
+			return spec, nil, err
+		}
+	}
+
+	templates, err := r.listMatchingTemplates(instance)
+	if err != nil {
+		return spec, nil, err
+	}
+	var applied []string
+	for i := range templates {
+		template := &templates[i]
+		if !hasProviderDefaults(&template.Spec.MonitorProviderDefaults, monitorType) {
+			continue
+		}
+		if err := applyProviderDefaults(&spec, &template.Spec.MonitorProviderDefaults, monitorType); err != nil {
+			return spec, nil, err
+		}
+		applied = append(applied, template.Name)
+	}
+
+	if err := applyProviderDefaults(&spec, defaults, monitorType); err != nil {
+		return spec, nil, err
+	}
+	return spec, applied, nil
+}
+
+// listMatchingTemplates lists the EndpointMonitorTemplates selecting the EndpointMonitor, sorted by name
+func (r *EndpointMonitorReconciler) listMatchingTemplates(instance *endpointmonitorv1alpha1.EndpointMonitor) ([]endpointmonitorv1alpha1.EndpointMonitorTemplate, error) {
+	templates := &endpointmonitorv1alpha1.EndpointMonitorTemplateList{}
+	if err := r.List(context.TODO(), templates, client.InNamespace(instance.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var matching []endpointmonitorv1alpha1.EndpointMonitorTemplate
+	for _, template := range templates.Items {
+		if template.Spec.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(template.Spec.Selector)
+			if err != nil {
+				log.Error(err, "Invalid selector of EndpointMonitorTemplate "+template.Namespace+"/"+template.Name)
+				continue
+			}
+			if !selector.Matches(labels.Set(instance.Labels)) {
+				continue
+			}
+		}
+		matching = append(matching, template)
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].Name < matching[j].Name
+	})
+	return matching, nil
+}
+
+// updateEffectiveConfig records the provider config the monitor is created with in the status of the EndpointMonitor,
+// together with the fields of spec.check the provider ignores and whether it runs the checkType
+func (r *EndpointMonitorReconciler) updateEffectiveConfig(instance *endpointmonitorv1alpha1.EndpointMonitor, spec endpointmonitorv1alpha1.EndpointMonitorSpec, appliedTemplates []string, unsupportedCheckFields []string, monitorService *monitors.MonitorServiceProxy) error {
+	var effectiveConfig *runtime.RawExtension
+	if config := monitorService.ExtractConfig(spec); config != nil && !reflect.ValueOf(config).IsNil() {
+		raw, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		effectiveConfig = &runtime.RawExtension{Raw: raw}
+	}
+
+	status := &instance.Status
+	conditionsChanged := setCheckSupported(instance, unsupportedCheckFields, monitorService)
+	conditionsChanged = setCheckTypeSupported(instance, monitorService) || conditionsChanged
+	if equalRawExtensions(status.EffectiveConfig, effectiveConfig) && reflect.DeepEqual(status.AppliedTemplates, appliedTemplates) && !conditionsChanged {
+		return nil
+	}
+	status.EffectiveConfig = effectiveConfig
+	status.AppliedTemplates = appliedTemplates
+	return r.Status().Update(context.TODO(), instance)
+}
+
+// equalRawExtensions compares the JSON of two RawExtensions, ignoring the order of keys which may
+// differ once stored by the API server
+func equalRawExtensions(a, b *runtime.RawExtension) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	var aValue, bValue interface{}
+	if json.Unmarshal(a.Raw, &aValue) != nil || json.Unmarshal(b.Raw, &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// endpointMonitorsForTemplate enqueues the EndpointMonitors in the namespace of an EndpointMonitorTemplate when
+// it changes, including those it no longer selects
+func (r *EndpointMonitorReconciler) endpointMonitorsForTemplate(ctx context.Context, template client.Object) []reconcile.Request {
+	endpointMonitors := &endpointmonitorv1alpha1.EndpointMonitorList{}
+	if err := r.List(ctx, endpointMonitors, client.InNamespace(template.GetNamespace())); err != nil {
+		log.Error(err, "Failed to list EndpointMonitors for EndpointMonitorTemplate "+template.GetNamespace()+"/"+template.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(endpointMonitors.Items))
+	for _, endpointMonitor := range endpointMonitors.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: endpointMonitor.Name, Namespace: endpointMonitor.Namespace}})
+	}
+	return requests
+}
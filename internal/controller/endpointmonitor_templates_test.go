@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestGetEffectiveSpec(t *testing.T) {
+	teamTemplate := &endpointmonitorv1alpha1.EndpointMonitorTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-checkout", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorTemplateSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "checkout"}},
+			MonitorProviderDefaults: endpointmonitorv1alpha1.MonitorProviderDefaults{
+				UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{AlertContacts: "checkout-team", Interval: 120},
+			},
+		},
+	}
+	namespaceTemplate := &endpointmonitorv1alpha1.EndpointMonitorTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "b-shop", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorTemplateSpec{
+			MonitorProviderDefaults: endpointmonitorv1alpha1.MonitorProviderDefaults{
+				UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{AlertContacts: "shop-team", StatusPages: "12345"},
+				StatusCakeConfig:  &endpointmonitorv1alpha1.StatusCakeConfig{CheckRate: 300},
+			},
+		},
+	}
+	otherTemplate := &endpointmonitorv1alpha1.EndpointMonitorTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "c-blog", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorTemplateSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "blog"}},
+			MonitorProviderDefaults: endpointmonitorv1alpha1.MonitorProviderDefaults{
+				UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{MonitorType: "keyword"},
+			},
+		},
+	}
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", Labels: map[string]string{"team": "checkout"}},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{Interval: 60},
+		},
+	}
+	r := newProviderTestReconciler(t, nil, teamTemplate, namespaceTemplate, otherTemplate)
+
+	defaults := &endpointmonitorv1alpha1.MonitorProviderDefaults{
+		UptimeRobotConfig: &endpointmonitorv1alpha1.UptimeRobotConfig{MaintenanceWindows: "provider-window", StatusPages: "67890"},
+	}
+	spec, applied, err := r.getEffectiveSpec(instance, nil, defaults, monitors.TypeUptimeRobot)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, applied, []string{"a-checkout", "b-shop"})
+	assert.DeepEqual(t, *spec.UptimeRobotConfig, endpointmonitorv1alpha1.UptimeRobotConfig{
+		Interval:           60,
+		AlertContacts:      "checkout-team",
+		StatusPages:        "12345",
+		MaintenanceWindows: "provider-window",
+	})
+	// Only the config of the selected provider type is merged
+	assert.Assert(t, spec.StatusCakeConfig == nil)
+	assert.Equal(t, instance.Spec.UptimeRobotConfig.AlertContacts, "")
+}
+
+func TestEqualRawExtensionsIgnoresKeyOrder(t *testing.T) {
+	a := &runtime.RawExtension{Raw: []byte(`{"interval":60,"alertContacts":"team"}`)}
+	b := &runtime.RawExtension{Raw: []byte(`{"alertContacts":"team","interval":60}`)}
+	assert.Assert(t, equalRawExtensions(a, b))
+	assert.Assert(t, !equalRawExtensions(a, nil))
+	assert.Assert(t, equalRawExtensions(nil, nil))
+}
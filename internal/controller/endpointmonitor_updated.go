@@ -9,21 +9,32 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-func (r *EndpointMonitorReconciler) handleUpdate(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor, monitor models.Monitor, monitorService *monitors.MonitorServiceProxy) error {
-	url, err := util.GetMonitorURL(r.Client, instance)
+func (r *EndpointMonitorReconciler) handleUpdate(request reconcile.Request, instance *endpointmonitorv1alpha1.EndpointMonitor, spec endpointmonitorv1alpha1.EndpointMonitorSpec, monitorName string, monitor models.Monitor, monitorService *monitors.MonitorServiceProxy) error {
+	url, err := util.GetMonitorURL(r.Client, r.apiReader(), instance)
 	if err != nil {
 		return err
 	}
 
 	// Extract provider specific configuration
-	config := monitorService.ExtractConfig(instance.Spec)
+	config := monitorService.ExtractConfig(spec)
 
 	// Create monitor Model
-	updatedMonitor := models.Monitor{Name: monitor.Name, ID: monitor.ID, URL: url, Config: config}
+	updatedMonitor := models.Monitor{Name: monitorName, ID: monitor.ID, URL: url, Config: config, Labels: instance.Labels, GRPC: spec.GRPC}
+	if updatedMonitor.Heartbeat, err = r.getHeartbeat(instance); err != nil {
+		return err
+	}
 
-	// Compare and Update monitor for provider if required
-	if !monitorService.Equal(monitor, updatedMonitor) {
+	// Compare and Update monitor for provider if required, a changed name is always applied
+	// since not every provider compares names
+	if monitor.Name != updatedMonitor.Name || !monitorService.Equal(monitor, updatedMonitor) {
 		monitorService.Update(updatedMonitor)
 	}
-	return nil
+	// The ping URL of heartbeat monitors is assigned by the provider and kept on update
+	if err := r.publishPingURL(instance, monitor.URL); err != nil {
+		return err
+	}
+	if err := r.publishOutputs(instance, updatedMonitor, monitor.URL, monitorService); err != nil {
+		return err
+	}
+	return r.updateMonitorStatus(instance, updatedMonitor, monitorService)
 }
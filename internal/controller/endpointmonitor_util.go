@@ -1,25 +1,267 @@
 package controllers
 
 import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	kubeutil "github.com/stakater/IngressMonitorController/v2/pkg/kube/util"
 	"github.com/stakater/IngressMonitorController/v2/pkg/models"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+	"github.com/stakater/IngressMonitorController/v2/pkg/util"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
 )
 
-func findMonitorByName(monitorService *monitors.MonitorServiceProxy, monitorName string) (*models.Monitor, error) {
-	return monitorService.GetByName(monitorName)
-}
+// getMonitorName returns the monitor name set on the EndpointMonitor, or renders the configured monitor
+// name template, and fits the result to the name length limit of the provider. The monitor name is
+// claimed in the status of the EndpointMonitor and must be unique for each provider.
+func (r *EndpointMonitorReconciler) getMonitorName(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorService *monitors.MonitorServiceProxy) (string, error) {
+	parts := util.MonitorNameTemplateParts{
+		Name:        instance.Name,
+		Namespace:   instance.Namespace,
+		Labels:      instance.Labels,
+		Annotations: instance.Annotations,
+		Provider:    monitorService.GetType(),
+		ClusterName: config.GetControllerConfig().ClusterName,
+	}
+
+	monitorURL, err := kubeutil.GetMonitorURL(r.Client, r.apiReader(), instance)
+	if err != nil {
+		return "", err
+	}
+	if parsedURL, err := url.Parse(monitorURL); err == nil {
+		parts.Host = parsedURL.Hostname()
+		parts.Path = parsedURL.Path
+	}
 
-// findMonitorServicesThatContainsMonitor iterates over all monitor services and returns the ones that contain the monitor
-func findMonitorServicesThatContainsMonitor(monitorServices []*monitors.MonitorServiceProxy, monitorName string) ([]*monitors.MonitorServiceProxy, error) {
-	var targetMonitorServices []*monitors.MonitorServiceProxy
-	for _, monitorService := range monitorServices {
-		monitor, err := monitorService.GetByName(monitorName)
+	monitorName := instance.Spec.MonitorName
+	if len(monitorName) == 0 {
+		monitorName, err = util.RenderMonitorName(config.GetControllerConfig().MonitorNameTemplate, parts)
 		if err != nil {
-			return nil, err
+			log.Error(err, "Failed to render MonitorNameTemplate, using default template `"+util.DefaultNameTemplate+"`")
+			monitorName = instance.Name + "-" + instance.Namespace
+		}
+	}
+	return util.SanitizeMonitorName(monitorName, monitorService.GetMaxNameLength()), nil
+}
+
+// getDisplayName returns the name the monitor is shown under in the provider, which is the display name
+// of the EndpointMonitor when set and its monitor name otherwise
+func getDisplayName(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, monitorService *monitors.MonitorServiceProxy) string {
+	if len(instance.Spec.DisplayName) == 0 {
+		return monitorName
+	}
+	return util.SanitizeMonitorName(instance.Spec.DisplayName, monitorService.GetMaxNameLength())
+}
+
+const (
+	defaultRenameRatePerMinute = 10
+	defaultRenameBurst         = 1
+)
+
+func newRenameLimiter(renameConfig config.MonitorRename) *rate.Limiter {
+	ratePerMinute := renameConfig.RatePerMinute
+	if ratePerMinute <= 0 {
+		ratePerMinute = defaultRenameRatePerMinute
+	}
+	burst := renameConfig.Burst
+	if burst <= 0 {
+		burst = defaultRenameBurst
+	}
+	return rate.NewLimiter(rate.Every(time.Minute/time.Duration(ratePerMinute)), burst)
+}
+
+// reserveRename returns how long to wait before the next rename is allowed, 0 if it may happen now
+func (r *EndpointMonitorReconciler) reserveRename() time.Duration {
+	if r.renameLimiter == nil {
+		return 0
+	}
+	reservation := r.renameLimiter.Reserve()
+	delay := reservation.Delay()
+	if delay > 0 {
+		// Give the slot back, the request is requeued and reserves again once the delay expired
+		reservation.Cancel()
+	}
+	return delay
+}
+
+// findRenamedMonitor returns the monitor previously created for the EndpointMonitor under a different
+// name, using the monitor ID recorded in its status
+func (r *EndpointMonitorReconciler) findRenamedMonitor(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorService *monitors.MonitorServiceProxy) (*models.Monitor, error) {
+	status := instance.Status
+	if len(status.MonitorID) == 0 || !recordedInAccount(status.Provider, status.ProviderID, monitorService) {
+		return nil, nil
+	}
+	if config.GetControllerConfig().MonitorRename.Disabled {
+		return nil, nil
+	}
+	if !monitorService.SupportsRename() {
+		log.Info("Provider " + monitorService.GetType() + " doesn't support renaming monitors, monitor " + status.MonitorName + " is left as is")
+		return nil, nil
+	}
+	return monitorService.GetByID(status.MonitorID)
+}
+
+// recordedInAccount returns whether the provider type and account recorded in a status refer to monitorService.
+// Statuses recorded before accounts had IDs refer to the default account of the type.
+func recordedInAccount(provider string, providerID string, monitorService *monitors.MonitorServiceProxy) bool {
+	if provider != monitorService.GetType() {
+		return false
+	}
+	if len(providerID) == 0 {
+		return monitorService.IsDefault()
+	}
+	return providerID == monitorService.GetID()
+}
+
+// claimMonitorName records monitorName and the provider account in the status of the EndpointMonitor. The
+// monitor ID recorded for another account is dropped.
+func (r *EndpointMonitorReconciler) claimMonitorName(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, monitorService *monitors.MonitorServiceProxy) error {
+	status := &instance.Status
+	changed := status.MonitorName != monitorName || status.Provider != monitorService.GetType() || status.ProviderID != monitorService.GetID()
+	if !recordedInAccount(status.Provider, status.ProviderID, monitorService) {
+		status.MonitorID = ""
+	}
+	status.MonitorName = monitorName
+	status.Provider = monitorService.GetType()
+	status.ProviderID = monitorService.GetID()
+
+	if meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeNameConflict,
+		Status:             metav1.ConditionFalse,
+		Reason:             endpointmonitorv1alpha1.ReasonNameAvailable,
+		Message:            "Monitor name " + monitorName + " is owned by this EndpointMonitor",
+		ObservedGeneration: instance.Generation,
+	}) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), instance)
+}
+
+// updateMonitorStatus records the ID of the monitor in the status of the EndpointMonitor, which has
+// claimed its monitor name already
+func (r *EndpointMonitorReconciler) updateMonitorStatus(instance *endpointmonitorv1alpha1.EndpointMonitor, monitor models.Monitor, monitorService *monitors.MonitorServiceProxy) error {
+	status := &instance.Status
+	changed := status.MonitorID != monitor.ID || status.Provider != monitorService.GetType() || status.ProviderID != monitorService.GetID()
+	status.MonitorID = monitor.ID
+	status.Provider = monitorService.GetType()
+	status.ProviderID = monitorService.GetID()
+
+	if instance.Spec.Adopt != nil && meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeAdopted,
+		Status:             metav1.ConditionTrue,
+		Reason:             endpointmonitorv1alpha1.ReasonMonitorAdopted,
+		Message:            "Monitor " + monitor.ID + " is adopted",
+		ObservedGeneration: instance.Generation,
+	}) {
+		changed = true
+	}
+	if meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             endpointmonitorv1alpha1.ReasonProviderReady,
+		Message:            "Provider " + monitorService.GetID() + " is ready",
+		ObservedGeneration: instance.Generation,
+	}) {
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), instance)
+}
+
+// findNameConflict returns another EndpointMonitor that owns monitorName in the same provider. When
+// both EndpointMonitors claim the name, the one created first keeps it.
+func (r *EndpointMonitorReconciler) findNameConflict(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, monitorService *monitors.MonitorServiceProxy) (*endpointmonitorv1alpha1.EndpointMonitor, error) {
+	claimants, err := r.listMonitorNameClaimants(monitorName)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := instance.Status.MonitorName == monitorName && recordedInAccount(instance.Status.Provider, instance.Status.ProviderID, monitorService)
+	for i := range claimants {
+		other := &claimants[i]
+		if other.UID == instance.UID || !recordedInAccount(other.Status.Provider, other.Status.ProviderID, monitorService) {
+			continue
 		}
-		if monitor != nil {
-			targetMonitorServices = append(targetMonitorServices, monitorService)
+		if claimed && !createdBefore(other, instance) {
+			continue
 		}
+		return other, nil
+	}
+	return nil, nil
+}
+
+// monitorNameIndexer indexes EndpointMonitors by the monitor name recorded in their status
+func monitorNameIndexer(obj client.Object) []string {
+	endpointMonitor := obj.(*endpointmonitorv1alpha1.EndpointMonitor)
+	if len(endpointMonitor.Status.MonitorName) == 0 {
+		return nil
 	}
-	return targetMonitorServices, nil
+	return []string{endpointMonitor.Status.MonitorName}
+}
+
+// listMonitorNameClaimants lists the EndpointMonitors whose status records monitorName
+func (r *EndpointMonitorReconciler) listMonitorNameClaimants(monitorName string) ([]endpointmonitorv1alpha1.EndpointMonitor, error) {
+	endpointMonitors := &endpointmonitorv1alpha1.EndpointMonitorList{}
+	if err := r.List(context.TODO(), endpointMonitors, client.MatchingFields{monitorNameIndexField: monitorName}); err != nil {
+		return nil, err
+	}
+	return endpointMonitors.Items, nil
+}
+
+func createdBefore(a, b *endpointmonitorv1alpha1.EndpointMonitor) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+}
+
+// setNameConflict reports in the status of the EndpointMonitor that its monitor name is owned by another
+// EndpointMonitor, and gives up the claim on the name
+func (r *EndpointMonitorReconciler) setNameConflict(instance *endpointmonitorv1alpha1.EndpointMonitor, monitorName string, owner *endpointmonitorv1alpha1.EndpointMonitor) error {
+	changed := meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeNameConflict,
+		Status:             metav1.ConditionTrue,
+		Reason:             endpointmonitorv1alpha1.ReasonNameInUse,
+		Message:            "Monitor name " + monitorName + " is already used by EndpointMonitor " + owner.Namespace + "/" + owner.Name,
+		ObservedGeneration: instance.Generation,
+	})
+	if instance.Status.MonitorName == monitorName {
+		instance.Status.MonitorName = ""
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), instance)
+}
+
+// setDegraded reports in the status of the EndpointMonitor that its provider failed to set up
+func (r *EndpointMonitorReconciler) setDegraded(instance *endpointmonitorv1alpha1.EndpointMonitor, providerErr error) error {
+	if !meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               endpointmonitorv1alpha1.ConditionTypeDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             endpointmonitorv1alpha1.ReasonProviderNotReady,
+		Message:            providerErr.Error(),
+		ObservedGeneration: instance.Generation,
+	}) {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), instance)
+}
+
+func findMonitorByName(monitorService *monitors.MonitorServiceProxy, monitorName string) (*models.Monitor, error) {
+	return monitorService.GetByName(monitorName)
 }
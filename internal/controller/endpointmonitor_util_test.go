@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors"
+)
+
+func TestGetMonitorName(t *testing.T) {
+	defer func(c config.Config) { config.IngressMonitorControllerConfig = c }(config.IngressMonitorControllerConfig)
+	config.IngressMonitorControllerConfig.MonitorNameTemplate = "{{.Name}}-{{.Namespace}}"
+
+	healthchecks := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeHealthchecks, ApiKey: "api-key"})
+	instance := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec:       endpointmonitorv1alpha1.EndpointMonitorSpec{URL: "https://checkout.example.com"},
+	}
+	r := newProviderTestReconciler(t, nil)
+
+	// The template is used without an override
+	monitorName, err := r.getMonitorName(instance, healthchecks)
+	assert.NilError(t, err)
+	assert.Equal(t, monitorName, "checkout-shop")
+	assert.Equal(t, getDisplayName(instance, monitorName, healthchecks), "checkout-shop")
+
+	// spec.monitorName takes precedence over the template
+	instance.Spec.MonitorName = "checkout-api-eu"
+	monitorName, err = r.getMonitorName(instance, healthchecks)
+	assert.NilError(t, err)
+	assert.Equal(t, monitorName, "checkout-api-eu")
+
+	// The display name only changes how the monitor is shown, and fits the name length limit as well
+	instance.Spec.DisplayName = "Checkout API (EU) " + strings.Repeat("x", 100)
+	monitorName, err = r.getMonitorName(instance, healthchecks)
+	assert.NilError(t, err)
+	assert.Equal(t, monitorName, "checkout-api-eu")
+	assert.Equal(t, getDisplayName(instance, monitorName, healthchecks), ("Checkout API (EU) " + strings.Repeat("x", 100))[:100])
+}
+
+func TestFindNameConflict(t *testing.T) {
+	healthchecks := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeHealthchecks, ID: "team", ApiKey: "api-key"})
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	newInstance := func(name string, creationTimestamp metav1.Time) *endpointmonitorv1alpha1.EndpointMonitor {
+		return &endpointmonitorv1alpha1.EndpointMonitor{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "shop", UID: types.UID(name), CreationTimestamp: creationTimestamp,
+		}}
+	}
+	first := newInstance("checkout", created)
+	second := newInstance("checkout-eu", metav1.NewTime(created.Add(time.Minute)))
+	r := newProviderTestReconciler(t, nil, first, second)
+
+	// The name claimed by the first EndpointMonitor is seen by the second before any monitor is created
+	owner, err := r.findNameConflict(first, "checkout-api", healthchecks)
+	assert.NilError(t, err)
+	assert.Assert(t, owner == nil)
+	assert.NilError(t, r.claimMonitorName(first, "checkout-api", healthchecks))
+	assert.Equal(t, first.Status.MonitorName, "checkout-api")
+	assert.Equal(t, first.Status.ProviderID, "team")
+
+	owner, err = r.findNameConflict(second, "checkout-api", healthchecks)
+	assert.NilError(t, err)
+	assert.Equal(t, owner.Name, "checkout")
+
+	// When both claimed the name at the same time, the EndpointMonitor created first keeps it
+	assert.NilError(t, r.claimMonitorName(second, "checkout-api", healthchecks))
+	owner, err = r.findNameConflict(first, "checkout-api", healthchecks)
+	assert.NilError(t, err)
+	assert.Assert(t, owner == nil)
+	owner, err = r.findNameConflict(second, "checkout-api", healthchecks)
+	assert.NilError(t, err)
+	assert.Equal(t, owner.Name, "checkout")
+
+	// The other one reports the conflict and gives up its claim
+	assert.NilError(t, r.setNameConflict(second, "checkout-api", owner))
+	stored := &endpointmonitorv1alpha1.EndpointMonitor{}
+	assert.NilError(t, r.Get(context.TODO(), types.NamespacedName{Name: "checkout-eu", Namespace: "shop"}, stored))
+	assert.Equal(t, stored.Status.MonitorName, "")
+	condition := meta.FindStatusCondition(stored.Status.Conditions, endpointmonitorv1alpha1.ConditionTypeNameConflict)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+	assert.Equal(t, condition.Message, "Monitor name checkout-api is already used by EndpointMonitor shop/checkout")
+
+	// Other accounts of the provider have names of their own
+	other := monitors.CreateMonitorService(&config.Provider{Name: monitors.TypeHealthchecks, ID: "other-team", ApiKey: "api-key"})
+	owner, err = r.findNameConflict(second, "checkout-api", other)
+	assert.NilError(t, err)
+	assert.Assert(t, owner == nil)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -24,15 +25,37 @@ var ReconciliationRequeueTime = getRequeueTime()
 
 var (
 	IngressMonitorControllerConfig Config
-	log                            = logf.Log.WithName("config")
+	// OperatorNamespace is the namespace the config was loaded from
+	OperatorNamespace string
+	log               = logf.Log.WithName("config")
 )
 
 type Config struct {
 	Providers             []Provider    `yaml:"providers"`
 	EnableMonitorDeletion bool          `yaml:"enableMonitorDeletion"`
 	MonitorNameTemplate   string        `yaml:"monitorNameTemplate"`
+	ClusterName           string        `yaml:"clusterName,omitempty"`
 	ResyncPeriod          int           `yaml:"resyncPeriod,omitempty"`
 	CreationDelay         time.Duration `yaml:"creationDelay,omitempty"`
+	MonitorRename         MonitorRename `yaml:"monitorRename,omitempty"`
+	// Interval in seconds at which provider credentials read from Secrets and files are refreshed
+	CredentialsRefreshInterval int `yaml:"credentialsRefreshInterval,omitempty"`
+	// Interval in seconds at which the connectivity of the providers is checked
+	ProviderCheckInterval int `yaml:"providerCheckInterval,omitempty"`
+	// Label selector of the namespaces whose EndpointMonitors are handled by this instance
+	NamespaceSelector string `yaml:"namespaceSelector,omitempty"`
+	// Label selector of the EndpointMonitors handled by this instance
+	EndpointMonitorSelector string `yaml:"endpointMonitorSelector,omitempty"`
+}
+
+// MonitorRename configures how existing monitors are renamed when their desired name changes
+type MonitorRename struct {
+	// Disabled turns off renaming, monitors whose name changed are created again instead
+	Disabled bool `yaml:"disabled"`
+	// RatePerMinute is the maximum number of renames per minute across all monitors, defaults to 10
+	RatePerMinute int `yaml:"ratePerMinute"`
+	// Burst is the number of renames that may happen at once, defaults to 1
+	Burst int `yaml:"burst"`
 }
 
 // UnmarshalYAML interface to deserialize specific types
@@ -58,7 +81,14 @@ func (c *Config) UnmarshalYAML(data []byte) error {
 }
 
 type Provider struct {
-	Name              string      `yaml:"name"`
+	Name string `yaml:"name"`
+	// ID identifies the account when several providers of the same type are configured,
+	// EndpointMonitors select it through spec.providerID
+	ID string `yaml:"id,omitempty"`
+	// Default marks the account used by EndpointMonitors that don't set spec.providerID,
+	// the first account of each type is used when none is marked
+	Default bool `yaml:"default,omitempty"`
+
 	ApiKey            string      `yaml:"apiKey"`
 	ApiToken          string      `yaml:"apiToken"`
 	ApiURL            string      `yaml:"apiURL"`
@@ -71,6 +101,21 @@ type Provider struct {
 	AppInsightsConfig AppInsights `yaml:"appInsightsConfig"`
 	GcloudConfig      Gcloud      `yaml:"gcloudConfig"`
 	GrafanaConfig     Grafana     `yaml:"grafanaConfig"`
+	PluginConfig      Plugin      `yaml:"pluginConfig"`
+	WebhookConfig     Webhook     `yaml:"webhookConfig"`
+	BlackboxConfig    Blackbox    `yaml:"blackboxConfig"`
+
+	// Credentials read from a Secret or a mounted file instead of being set inline,
+	// they take precedence over apiKey, apiToken and password
+	ApiKeySecretRef   *SecretRef `yaml:"apiKeySecretRef,omitempty"`
+	ApiKeyFile        string     `yaml:"apiKeyFile,omitempty"`
+	ApiTokenSecretRef *SecretRef `yaml:"apiTokenSecretRef,omitempty"`
+	ApiTokenFile      string     `yaml:"apiTokenFile,omitempty"`
+	PasswordSecretRef *SecretRef `yaml:"passwordSecretRef,omitempty"`
+	PasswordFile      string     `yaml:"passwordFile,omitempty"`
+
+	// credentialsErr is the error the credentials failed to resolve with
+	credentialsErr error
 }
 
 type AppInsights struct {
@@ -92,6 +137,53 @@ type Grafana struct {
 	Frequency int64 `yaml:"frequency"`
 }
 
+// Plugin holds the connection settings for an out-of-process provider plugin
+type Plugin struct {
+	// Address of the plugin gRPC endpoint, e.g. `plugin.monitoring.svc:9000` or
+	// `unix:///var/run/imc/plugin.sock` for a sidecar
+	Address string `yaml:"address"`
+	// Use TLS with the system root CAs when connecting to the plugin
+	TLS bool `yaml:"tls"`
+	// Timeout in seconds for each call made to the plugin
+	Timeout int `yaml:"timeout"`
+}
+
+// Webhook holds the endpoints and signing settings of the generic webhook provider
+type Webhook struct {
+	// Base URL of the webhook. Monitors are created with POST {url}, listed with GET {url},
+	// updated with PUT {url}/{id} and removed with DELETE {url}/{id}
+	URL string `yaml:"url"`
+	// Override the URL used to create monitors
+	CreateURL string `yaml:"createURL"`
+	// Override the URL used to update monitors, `{id}` is replaced by the monitor ID
+	UpdateURL string `yaml:"updateURL"`
+	// Override the URL used to remove monitors, `{id}` is replaced by the monitor ID
+	DeleteURL string `yaml:"deleteURL"`
+	// Override the URL used to list monitors
+	ListURL string `yaml:"listURL"`
+	// Shared secret used to sign request bodies with HMAC-SHA256
+	Secret string `yaml:"secret"`
+	// Additional headers sent with every request
+	Headers map[string]string `yaml:"headers"`
+	// Deprecated: failed requests are sent again by the next reconcile, the value is ignored
+	Retries *int `yaml:"retries"`
+	// Timeout in seconds for each request
+	Timeout int `yaml:"timeout"`
+}
+
+// Blackbox holds the blackbox_exporter probing the endpoints and where the Probes scraping it are created
+type Blackbox struct {
+	// URL of the blackbox_exporter, e.g. `http://blackbox-exporter.monitoring.svc:9115`. The path
+	// defaults to `/probe`
+	ProberURL string `yaml:"proberURL"`
+	// Module of HTTP checks, defaults to `http_2xx`
+	Module string `yaml:"module"`
+	// Namespace of the Probes, defaults to the namespace of the controller
+	Namespace string `yaml:"namespace"`
+	// Labels of the Probes, matched by the probeSelector of Prometheus
+	Labels map[string]string `yaml:"labels"`
+}
+
 type EmailAction struct {
 	SendToServiceOwners bool      `yaml:"send_to_service_owners"`
 	CustomEmails        []*string `yaml:"custom_emails"`
@@ -101,7 +193,9 @@ type WebhookAction struct {
 	ServiceURI string `yaml:"service_uri"`
 }
 
-func LoadControllerConfig(apiReader client.Reader) {
+// LoadControllerConfig loads the config from the config Secret of the controller. A provider whose
+// credentials can't be resolved is kept, it reports the error once it is set up.
+func LoadControllerConfig(apiReader client.Reader) error {
 	var config Config
 	log.Info("Loading YAML Configuration from secret")
 
@@ -110,11 +204,11 @@ func LoadControllerConfig(apiReader client.Reader) {
 	if len(operatorNamespace) == 0 {
 		operatorNamespaceTemp, err := util.GetOperatorNamespace()
 		if err != nil {
-			log.Error(err, "Unable to get operator namespace")
-			panic("Unable to get operator namespace")
+			return fmt.Errorf("unable to get operator namespace: %w", err)
 		}
 		operatorNamespace = operatorNamespaceTemp
 	}
+	OperatorNamespace = operatorNamespace
 
 	configSecretName, _ := os.LookupEnv("CONFIG_SECRET_NAME")
 	if len(configSecretName) == 0 {
@@ -125,15 +219,23 @@ func LoadControllerConfig(apiReader client.Reader) {
 	// Retrieve config key from secret
 	configKey, err := secret.LoadSecretData(apiReader, configSecretName, operatorNamespace, IngressMonitorControllerSecretConfigKey)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("unable to read config secret %s/%s: %w", operatorNamespace, configSecretName, err)
 	}
 
 	// Unmarshall
 	err = yaml.Unmarshal([]byte(configKey), &config)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("unable to parse config secret %s/%s: %w", operatorNamespace, configSecretName, err)
+	}
+
+	// Resolve provider credentials kept outside of the config
+	for index := range config.Providers {
+		if err := ResolveProviderCredentials(apiReader, operatorNamespace, &config.Providers[index]); err != nil {
+			log.Error(err, "Unable to resolve credentials of provider "+config.Providers[index].Name)
+		}
 	}
 	IngressMonitorControllerConfig = config
+	return nil
 }
 
 func GetControllerConfig() Config {
@@ -165,6 +267,13 @@ func ReadConfig(filePath string) Config {
 	if err != nil {
 		panic(err)
 	}
+
+	// Only credentials from files can be resolved without a cluster
+	for index := range config.Providers {
+		if err := ResolveProviderCredentials(nil, "", &config.Providers[index]); err != nil {
+			panic(err)
+		}
+	}
 	IngressMonitorControllerConfig = config
 	return config
 }
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stakater/IngressMonitorController/v2/pkg/secret"
+)
+
+const defaultCredentialsRefreshInterval = 60
+
+// SecretRef selects a key of a Secret holding a provider credential
+type SecretRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+	// Namespace of the Secret, defaults to the namespace of the controller
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// ResolveProviderCredentials sets the apiKey, apiToken and password of the provider from their
+// Secret references or files. Secret references take precedence over files, which take precedence
+// over inline values. The error is kept on the provider as well, see CredentialsError.
+func ResolveProviderCredentials(apiReader client.Reader, namespace string, p *Provider) error {
+	p.credentialsErr = resolveProviderCredentials(apiReader, namespace, p)
+	return p.credentialsErr
+}
+
+// CredentialsError returns the error the credentials of the provider failed to resolve with
+func (p *Provider) CredentialsError() error {
+	return p.credentialsErr
+}
+
+func resolveProviderCredentials(apiReader client.Reader, namespace string, p *Provider) error {
+	credentials := []struct {
+		name      string
+		secretRef *SecretRef
+		file      string
+		target    *string
+	}{
+		{"apiKey", p.ApiKeySecretRef, p.ApiKeyFile, &p.ApiKey},
+		{"apiToken", p.ApiTokenSecretRef, p.ApiTokenFile, &p.ApiToken},
+		{"password", p.PasswordSecretRef, p.PasswordFile, &p.Password},
+	}
+
+	for _, credential := range credentials {
+		switch {
+		case credential.secretRef != nil:
+			value, err := readSecretRef(apiReader, namespace, credential.secretRef)
+			if err != nil {
+				return fmt.Errorf("unable to read %s of provider %s: %w", credential.name, p.Name, err)
+			}
+			*credential.target = value
+		case len(credential.file) > 0:
+			value, err := os.ReadFile(credential.file)
+			if err != nil {
+				return fmt.Errorf("unable to read %s of provider %s: %w", credential.name, p.Name, err)
+			}
+			// Mounted files commonly end with a newline
+			*credential.target = strings.TrimRight(string(value), "\r\n")
+		}
+	}
+	return nil
+}
+
+// HasExternalCredentials returns whether any credential of the provider is read from a Secret or file
+func (p *Provider) HasExternalCredentials() bool {
+	return p.ApiKeySecretRef != nil || p.ApiTokenSecretRef != nil || p.PasswordSecretRef != nil ||
+		len(p.ApiKeyFile) > 0 || len(p.ApiTokenFile) > 0 || len(p.PasswordFile) > 0
+}
+
+func readSecretRef(apiReader client.Reader, namespace string, ref *SecretRef) (string, error) {
+	if apiReader == nil {
+		return "", fmt.Errorf("secret references are only supported when the config is loaded from a secret")
+	}
+	if len(ref.Namespace) > 0 {
+		namespace = ref.Namespace
+	}
+	return secret.LoadSecretData(apiReader, ref.Name, namespace, ref.Key)
+}
+
+// CredentialsWatcher periodically resolves the credentials of providers read from Secrets or files and
+// calls OnChange for every provider whose credentials changed, e.g. after a rotation by External Secrets
+type CredentialsWatcher struct {
+	APIReader client.Reader
+	Namespace string
+	Providers []Provider
+	Interval  time.Duration
+	// OnChange is called with the index of the provider in Providers and its new configuration
+	OnChange func(index int, p Provider)
+}
+
+// NewCredentialsWatcher returns a CredentialsWatcher for the providers of config
+func NewCredentialsWatcher(apiReader client.Reader, namespace string, config Config, onChange func(index int, p Provider)) *CredentialsWatcher {
+	interval := config.CredentialsRefreshInterval
+	if interval <= 0 {
+		interval = defaultCredentialsRefreshInterval
+	}
+	return &CredentialsWatcher{
+		APIReader: apiReader,
+		Namespace: namespace,
+		Providers: config.Providers,
+		Interval:  time.Duration(interval) * time.Second,
+		OnChange:  onChange,
+	}
+}
+
+// Start polls the credentials until ctx is done, it implements manager.Runnable
+func (w *CredentialsWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.Refresh()
+		}
+	}
+}
+
+// NeedLeaderElection returns false since every replica needs up to date credentials
+func (w *CredentialsWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Refresh resolves the credentials of all providers once and reports the ones that changed
+func (w *CredentialsWatcher) Refresh() {
+	for index := range w.Providers {
+		current := w.Providers[index]
+		if !current.HasExternalCredentials() {
+			continue
+		}
+
+		updated := current
+		if err := ResolveProviderCredentials(w.APIReader, w.Namespace, &updated); err != nil {
+			log.Error(err, "Unable to refresh credentials, keeping the current ones", "provider", current.Name)
+			continue
+		}
+		if current.CredentialsError() == nil &&
+			updated.ApiKey == current.ApiKey && updated.ApiToken == current.ApiToken && updated.Password == current.Password {
+			continue
+		}
+
+		log.Info("Credentials changed, reloading provider " + current.Name)
+		w.Providers[index] = updated
+		w.OnChange(index, updated)
+	}
+}
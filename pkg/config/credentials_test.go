@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newCredentialsSecret(namespace string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "provider-credentials", Namespace: namespace},
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func TestResolveProviderCredentials(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	assert.NilError(t, os.WriteFile(passwordFile, []byte("file-password\n"), 0600))
+
+	apiReader := fake.NewClientBuilder().WithObjects(
+		newCredentialsSecret("imc", map[string]string{"apiKey": "secret-api-key"}),
+		newCredentialsSecret("shared", map[string]string{"token": "secret-api-token"}),
+	).Build()
+
+	provider := Provider{
+		Name:              "Pingdom",
+		ApiKey:            "inline-api-key",
+		ApiKeySecretRef:   &SecretRef{Name: "provider-credentials", Key: "apiKey"},
+		ApiTokenSecretRef: &SecretRef{Name: "provider-credentials", Key: "token", Namespace: "shared"},
+		Password:          "inline-password",
+		PasswordFile:      passwordFile,
+	}
+	assert.NilError(t, ResolveProviderCredentials(apiReader, "imc", &provider))
+	assert.Equal(t, provider.ApiKey, "secret-api-key")
+	assert.Equal(t, provider.ApiToken, "secret-api-token")
+	assert.Equal(t, provider.Password, "file-password")
+}
+
+func TestResolveProviderCredentialsErrors(t *testing.T) {
+	provider := Provider{Name: "UptimeRobot", ApiKeySecretRef: &SecretRef{Name: "provider-credentials", Key: "apiKey"}}
+	err := ResolveProviderCredentials(nil, "imc", &provider)
+	assert.ErrorContains(t, err, "only supported when the config is loaded from a secret")
+
+	apiReader := fake.NewClientBuilder().WithObjects(newCredentialsSecret("imc", map[string]string{})).Build()
+	err = ResolveProviderCredentials(apiReader, "imc", &provider)
+	assert.ErrorContains(t, err, "did not contain key apiKey")
+
+	provider = Provider{Name: "UptimeRobot", ApiKeyFile: filepath.Join(t.TempDir(), "missing")}
+	err = ResolveProviderCredentials(nil, "imc", &provider)
+	assert.ErrorContains(t, err, "apiKey of provider UptimeRobot")
+	assert.Equal(t, provider.CredentialsError(), err)
+}
+
+func TestCredentialsWatcherRefresh(t *testing.T) {
+	apiKeyFile := filepath.Join(t.TempDir(), "apiKey")
+	assert.NilError(t, os.WriteFile(apiKeyFile, []byte("old-key"), 0600))
+
+	providers := []Provider{
+		{Name: "StatusCake", ApiKey: "inline"},
+		{Name: "UptimeRobot", ApiKeyFile: apiKeyFile},
+	}
+	assert.NilError(t, ResolveProviderCredentials(nil, "", &providers[1]))
+
+	var changed []int
+	watcher := NewCredentialsWatcher(nil, "", Config{Providers: providers}, func(index int, p Provider) {
+		changed = append(changed, index)
+		assert.Equal(t, p.ApiKey, "new-key")
+	})
+
+	watcher.Refresh()
+	assert.Equal(t, len(changed), 0)
+
+	assert.NilError(t, os.WriteFile(apiKeyFile, []byte("new-key"), 0600))
+	watcher.Refresh()
+	assert.DeepEqual(t, changed, []int{1})
+
+	// Unchanged credentials don't trigger a reload again
+	watcher.Refresh()
+	assert.DeepEqual(t, changed, []int{1})
+}
+
+func TestCredentialsWatcherRefreshAfterFailure(t *testing.T) {
+	apiKeyFile := filepath.Join(t.TempDir(), "apiKey")
+	providers := []Provider{{Name: "UptimeRobot", ApiKeyFile: apiKeyFile}}
+	assert.ErrorContains(t, ResolveProviderCredentials(nil, "", &providers[0]), "apiKey of provider UptimeRobot")
+
+	var changed []Provider
+	watcher := NewCredentialsWatcher(nil, "", Config{Providers: providers}, func(index int, p Provider) {
+		changed = append(changed, p)
+	})
+
+	watcher.Refresh()
+	assert.Equal(t, len(changed), 0)
+
+	// The provider is reloaded once its credentials resolve, even when they are empty
+	assert.NilError(t, os.WriteFile(apiKeyFile, []byte(""), 0600))
+	watcher.Refresh()
+	assert.Equal(t, len(changed), 1)
+	assert.NilError(t, changed[0].CredentialsError())
+}
@@ -3,11 +3,15 @@ package util
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stakater/IngressMonitorController/v2/pkg/kube"
 	"github.com/stakater/IngressMonitorController/v2/pkg/kube/wrappers"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,13 +22,18 @@ import (
 
 var log = logf.Log.WithName("config")
 
-func GetMonitorURL(client client.Client, ingressMonitor *endpointmonitorv1alpha1.EndpointMonitor) (string, error) {
+// GetMonitorURL returns the url of the EndpointMonitor, Services are read through apiReader as they aren't cached
+func GetMonitorURL(client client.Client, apiReader client.Reader, ingressMonitor *endpointmonitorv1alpha1.EndpointMonitor) (string, error) {
+	// Heartbeat monitors are pinged by the job instead of checking a URL
+	if ingressMonitor.Spec.CheckType == endpointmonitorv1alpha1.CheckTypeHeartbeat {
+		return "", nil
+	}
 	if ingressMonitor.Spec.StatusCakeConfig != nil && strings.EqualFold(ingressMonitor.Spec.StatusCakeConfig.TestType, "Heartbeat") {
 		return "", nil
 	}
 
 	if len(ingressMonitor.Spec.URL) == 0 {
-		return discoverURLFromRefs(client, ingressMonitor)
+		return discoverURLFromRefs(client, apiReader, ingressMonitor)
 	}
 	if ingressMonitor.Spec.URLFrom != nil {
 		log.V(1).Info("Both url and urlFrom fields are specified. Using url over urlFrom")
@@ -35,7 +44,7 @@ func GetMonitorURL(client client.Client, ingressMonitor *endpointmonitorv1alpha1
 	return ingressMonitor.Spec.URL, nil
 }
 
-func discoverURLFromIngressRef(client client.Client, ingressRef *endpointmonitorv1alpha1.IngressURLSource, namespace string, forceHttps bool, healthEndpoint string) (string, error) {
+func discoverURLFromIngressRef(client client.Client, ingressRef *endpointmonitorv1alpha1.IngressURLSource, namespace string, checkType endpointmonitorv1alpha1.CheckType, forceHttps bool, healthEndpoint string) (string, error) {
 	ingressObject := &v1.Ingress{}
 	err := client.Get(context.TODO(), types.NamespacedName{Name: ingressRef.Name, Namespace: namespace}, ingressObject)
 	if err != nil {
@@ -44,10 +53,13 @@ func discoverURLFromIngressRef(client client.Client, ingressRef *endpointmonitor
 	}
 
 	ingressWrapper := wrappers.NewIngressWrapper(ingressObject, client)
+	if checkType == endpointmonitorv1alpha1.CheckTypeGRPC {
+		return ingressWrapper.GetGRPCURL(forceHttps)
+	}
 	return ingressWrapper.GetURL(forceHttps, healthEndpoint), nil
 }
 
-func discoverURLFromRouteRef(client client.Client, routeRef *endpointmonitorv1alpha1.RouteURLSource, namespace string, forceHttps bool, healthEndpoint string) (string, error) {
+func discoverURLFromRouteRef(client client.Client, routeRef *endpointmonitorv1alpha1.RouteURLSource, namespace string, checkType endpointmonitorv1alpha1.CheckType, forceHttps bool, healthEndpoint string) (string, error) {
 	routeObject := &routev1.Route{}
 	err := client.Get(context.TODO(), types.NamespacedName{Name: routeRef.Name, Namespace: namespace}, routeObject)
 	if err != nil {
@@ -56,23 +68,100 @@ func discoverURLFromRouteRef(client client.Client, routeRef *endpointmonitorv1al
 	}
 
 	routeWrapper := wrappers.NewRouteWrapper(routeObject, client)
+	if checkType == endpointmonitorv1alpha1.CheckTypeGRPC {
+		return routeWrapper.GetGRPCURL(forceHttps)
+	}
 	return routeWrapper.GetURL(forceHttps, healthEndpoint), nil
 }
 
-func discoverURLFromRefs(client client.Client, ingressMonitor *endpointmonitorv1alpha1.EndpointMonitor) (string, error) {
+func discoverURLFromServiceRef(apiReader client.Reader, serviceRef *endpointmonitorv1alpha1.ServiceURLSource, namespace string, checkType endpointmonitorv1alpha1.CheckType, forceHttps bool, healthEndpoint string) (string, error) {
+	serviceObject := &corev1.Service{}
+	err := apiReader.Get(context.TODO(), types.NamespacedName{Name: serviceRef.Name, Namespace: namespace}, serviceObject)
+	if err != nil {
+		log.V(1).Info("Service not found with name " + serviceRef.Name)
+		return "", err
+	}
+
+	if serviceObject.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return "", fmt.Errorf("service %s is of type %s, only LoadBalancer services can be monitored", serviceRef.Name, serviceObject.Spec.Type)
+	}
+	address := getLoadBalancerAddress(serviceObject)
+	if address == "" {
+		return "", fmt.Errorf("service %s has no LoadBalancer address yet", serviceRef.Name)
+	}
+
+	if checkType == endpointmonitorv1alpha1.CheckTypeICMP || checkType == endpointmonitorv1alpha1.CheckTypeDNS {
+		return models.TargetURL(checkType, address, 0), nil
+	}
+
+	port, err := getServicePort(serviceObject, serviceRef)
+	if err != nil {
+		return "", err
+	}
+	if checkType == endpointmonitorv1alpha1.CheckTypeTCP || checkType == endpointmonitorv1alpha1.CheckTypeGRPC {
+		return models.TargetURL(checkType, address, port), nil
+	}
+
+	scheme := "http"
+	if forceHttps || port == 443 {
+		scheme = "https"
+	}
+	monitorURL := scheme + "://" + address
+	if port != 80 && port != 443 {
+		monitorURL += ":" + strconv.Itoa(port)
+	}
+	if len(healthEndpoint) > 0 {
+		monitorURL += "/" + strings.TrimPrefix(healthEndpoint, "/")
+	}
+	return monitorURL, nil
+}
+
+// getLoadBalancerAddress returns the first ip or hostname the load balancer of the service is reachable on
+func getLoadBalancerAddress(service *corev1.Service) string {
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+	}
+	return ""
+}
+
+// getServicePort returns the port selected by name or number in the serviceRef, or the first port of the service
+func getServicePort(service *corev1.Service, serviceRef *endpointmonitorv1alpha1.ServiceURLSource) (int, error) {
+	if len(service.Spec.Ports) == 0 {
+		return 0, fmt.Errorf("service %s has no ports", serviceRef.Name)
+	}
+	if serviceRef.Port == nil {
+		return int(service.Spec.Ports[0].Port), nil
+	}
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Name == serviceRef.Port.String() || int(servicePort.Port) == serviceRef.Port.IntValue() {
+			return int(servicePort.Port), nil
+		}
+	}
+	return 0, fmt.Errorf("service %s has no port %s", serviceRef.Name, serviceRef.Port.String())
+}
+
+func discoverURLFromRefs(client client.Client, apiReader client.Reader, ingressMonitor *endpointmonitorv1alpha1.EndpointMonitor) (string, error) {
 	urlFrom := ingressMonitor.Spec.URLFrom
 	if urlFrom == nil {
 		log.V(1).Info("No URL sources set for ingressMonitor: " + ingressMonitor.Name)
 		return "", errors.New("No URL sources set for ingressMonitor: " + ingressMonitor.Name)
 	}
 
-	if urlFrom.IngressRef != nil {
+	if urlFrom.ServiceRef != nil {
+		return discoverURLFromServiceRef(apiReader, urlFrom.ServiceRef, ingressMonitor.Namespace, ingressMonitor.Spec.CheckType, ingressMonitor.Spec.ForceHTTPS, ingressMonitor.Spec.HealthEndpoint)
+
+	} else if urlFrom.IngressRef != nil {
 		// if ingressRef is mentioned, it can be openshift or non openshift cluster
-		return discoverURLFromIngressRef(client, urlFrom.IngressRef, ingressMonitor.Namespace, ingressMonitor.Spec.ForceHTTPS, ingressMonitor.Spec.HealthEndpoint)
+		return discoverURLFromIngressRef(client, urlFrom.IngressRef, ingressMonitor.Namespace, ingressMonitor.Spec.CheckType, ingressMonitor.Spec.ForceHTTPS, ingressMonitor.Spec.HealthEndpoint)
 
 	} else if kube.IsOpenshift && urlFrom.RouteRef != nil {
 		// if routeRef is mentioned in openshift cluster
-		return discoverURLFromRouteRef(client, urlFrom.RouteRef, ingressMonitor.Namespace, ingressMonitor.Spec.ForceHTTPS, ingressMonitor.Spec.HealthEndpoint)
+		return discoverURLFromRouteRef(client, urlFrom.RouteRef, ingressMonitor.Namespace, ingressMonitor.Spec.CheckType, ingressMonitor.Spec.ForceHTTPS, ingressMonitor.Spec.HealthEndpoint)
 
 	}
 
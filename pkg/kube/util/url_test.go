@@ -0,0 +1,90 @@
+package util
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+func TestGetMonitorURLFromServiceRef(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{
+				{Name: "metrics", Port: 9187},
+				{Name: "postgres", Port: 5432},
+			},
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(service).Build()
+
+	postgresPort := intstr.FromString("postgres")
+	grpcPort := intstr.FromInt32(5432)
+	tests := []struct {
+		name      string
+		checkType endpointmonitorv1alpha1.CheckType
+		port      *intstr.IntOrString
+		expected  string
+	}{
+		{name: "tcp by port name", checkType: endpointmonitorv1alpha1.CheckTypeTCP, port: &postgresPort, expected: "tcp://203.0.113.10:5432"},
+		{name: "tcp first port", checkType: endpointmonitorv1alpha1.CheckTypeTCP, expected: "tcp://203.0.113.10:9187"},
+		{name: "icmp", checkType: endpointmonitorv1alpha1.CheckTypeICMP, expected: "icmp://203.0.113.10"},
+		{name: "grpc by port number", checkType: endpointmonitorv1alpha1.CheckTypeGRPC, port: &grpcPort, expected: "grpc://203.0.113.10:5432"},
+		{name: "http", checkType: endpointmonitorv1alpha1.CheckTypeHTTP, expected: "http://203.0.113.10:9187/metrics"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			endpointMonitor := &endpointmonitorv1alpha1.EndpointMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "shop"},
+				Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+					CheckType:      test.checkType,
+					HealthEndpoint: "/metrics",
+					URLFrom: &endpointmonitorv1alpha1.URLSource{
+						ServiceRef: &endpointmonitorv1alpha1.ServiceURLSource{Name: "postgres", Port: test.port},
+					},
+				},
+			}
+			monitorURL, err := GetMonitorURL(c, c, endpointMonitor)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if monitorURL != test.expected {
+				t.Errorf("Expected url %s, got %s", test.expected, monitorURL)
+			}
+		})
+	}
+}
+
+func TestGetMonitorURLFromServiceRefWithoutLoadBalancerAddress(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{{Port: 5432}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(service).Build()
+
+	endpointMonitor := &endpointmonitorv1alpha1.EndpointMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "shop"},
+		Spec: endpointmonitorv1alpha1.EndpointMonitorSpec{
+			CheckType: endpointmonitorv1alpha1.CheckTypeTCP,
+			URLFrom: &endpointmonitorv1alpha1.URLSource{
+				ServiceRef: &endpointmonitorv1alpha1.ServiceURLSource{Name: "postgres"},
+			},
+		},
+	}
+	if _, err := GetMonitorURL(c, c, endpointMonitor); err == nil {
+		t.Error("Expected an error while the load balancer has no address")
+	}
+}
@@ -0,0 +1,33 @@
+package wrappers
+
+import (
+	"net"
+	"strings"
+)
+
+const (
+	// BackendProtocolAnnotation marks Ingresses and Routes whose backends serve gRPC, with GRPC or GRPCS as value
+	BackendProtocolAnnotation = "endpointmonitor.stakater.com/backend-protocol"
+	// nginxBackendProtocolAnnotation is the annotation ingress-nginx proxies gRPC backends for
+	nginxBackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+)
+
+// isGRPCBackend returns whether the annotations mark the backend as serving gRPC
+func isGRPCBackend(annotations map[string]string) bool {
+	for _, annotation := range []string{BackendProtocolAnnotation, nginxBackendProtocolAnnotation} {
+		protocol := strings.ToUpper(annotations[annotation])
+		if protocol == "GRPC" || protocol == "GRPCS" {
+			return true
+		}
+	}
+	return false
+}
+
+// grpcTarget returns the grpc:// target of a host, exposed on 443 with TLS and on 80 otherwise
+func grpcTarget(host string, tls bool) string {
+	port := "80"
+	if tls {
+		port = "443"
+	}
+	return "grpc://" + net.JoinHostPort(host, port)
+}
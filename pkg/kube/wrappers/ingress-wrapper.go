@@ -111,6 +111,20 @@ func (iw *IngressWrapper) GetURL(forceHttps bool, healthEndpoint string) string
 	return u.String()
 }
 
+// GetGRPCURL returns the grpc:// target of an Ingress whose backends are annotated as serving gRPC
+func (iw *IngressWrapper) GetGRPCURL(forceHttps bool) (string, error) {
+	if !iw.rulesExist() {
+		return "", fmt.Errorf("no rules exist in ingress %s", iw.Ingress.GetName())
+	}
+	if !isGRPCBackend(iw.Ingress.Annotations) {
+		return "", fmt.Errorf("ingress %s is not annotated with %s for gRPC backends", iw.Ingress.GetName(), BackendProtocolAnnotation)
+	}
+	if iw.supportsTLS() {
+		return grpcTarget(iw.Ingress.Spec.TLS[0].Hosts[0], true), nil
+	}
+	return grpcTarget(iw.Ingress.Spec.Rules[0].Host, forceHttps), nil
+}
+
 func (iw *IngressWrapper) hasService() (string, bool) {
 	ingress := iw.Ingress
 	if ingress.Spec.Rules[0].HTTP != nil &&
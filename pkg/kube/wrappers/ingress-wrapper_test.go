@@ -124,3 +124,33 @@ func TestIngressWrapper_getURL(t *testing.T) {
 		})
 	}
 }
+
+func TestIngressWrapper_GetGRPCURL(t *testing.T) {
+	tlsIngress := createIngressObjectWithTLS("testIngress", "test", testUrl, "customtls.stackator.com")
+	tlsIngress.Annotations = map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "GRPC"}
+	plainIngress := util.CreateIngressObject("testIngress", "test", testUrl)
+	plainIngress.Annotations = map[string]string{BackendProtocolAnnotation: "grpc"}
+
+	tests := []struct {
+		name    string
+		ingress *v1.Ingress
+		want    string
+		wantErr bool
+	}{
+		{name: "TestGetGRPCURLWithTLS", ingress: tlsIngress, want: "grpc://customtls.stackator.com:443"},
+		{name: "TestGetGRPCURLWithoutTLS", ingress: plainIngress, want: "grpc://testurl.stackator.com:80"},
+		{name: "TestGetGRPCURLWithoutAnnotation", ingress: util.CreateIngressObject("testIngress", "test", testUrl), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iw := NewIngressWrapper(tt.ingress, fakekubeclient.NewClientBuilder().Build())
+			got, err := iw.GetGRPCURL(false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IngressWrapper.GetGRPCURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IngressWrapper.GetGRPCURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -98,6 +98,14 @@ func (rw *RouteWrapper) tryGetHealthEndpointFromRoute() (string, bool) {
 	return "", false
 }
 
+// GetGRPCURL returns the grpc:// target of a Route whose backend is annotated as serving gRPC
+func (rw *RouteWrapper) GetGRPCURL(forceHttps bool) (string, error) {
+	if !isGRPCBackend(rw.Route.Annotations) {
+		return "", fmt.Errorf("route %s is not annotated with %s for gRPC backends", rw.Route.GetName(), BackendProtocolAnnotation)
+	}
+	return grpcTarget(rw.Route.Spec.Host, rw.supportsTLS() || forceHttps), nil
+}
+
 func (rw *RouteWrapper) GetURL(forceHttps bool, healthEndpoint string) string {
 	var URL string
 
@@ -1,10 +1,65 @@
 package models
 
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
 type Monitor struct {
 	URL    string
 	Name   string
 	ID     string
 	Config interface{}
+	Labels map[string]string
+	// GRPC holds the options of GRPC checks
+	GRPC *endpointmonitorv1alpha1.GRPCCheck
+	// Heartbeat holds the schedule of heartbeat monitors, which have no URL to check
+	Heartbeat *Heartbeat
+}
+
+// MonitorLinks holds the URLs of a monitor in the provider, empty when the provider has none
+type MonitorLinks struct {
+	// DashboardURL is the page of the monitor in the dashboard of the provider
+	DashboardURL string
+	// StatusPageURL is the public status page showing the monitor
+	StatusPageURL string
+}
+
+// ProbeResult is the result of a probe run by a provider inside the cluster
+type ProbeResult struct {
+	// Success is whether the endpoint was up
+	Success bool
+	// Message is the reason the probe failed
+	Message string
+	// StatusCode of the HTTP response, 0 for TCP probes
+	StatusCode int
+	// Duration of the probe
+	Duration time.Duration
+	// Time the probe ran at
+	Time time.Time
+	// LastTransitionTime is when Success last changed
+	LastTransitionTime time.Time
+	// CertificateNotAfter is the expiry of the certificate of the endpoint that expires first, zero without TLS
+	CertificateNotAfter time.Time
+	// Interval between two probes
+	Interval time.Duration
+}
+
+// Heartbeat describes when the job pinging a heartbeat monitor runs
+type Heartbeat struct {
+	// Schedule is the cron schedule of the job, empty for heartbeats read from providers that only keep the period
+	Schedule string
+	// TimeZone of the schedule, UTC when empty
+	TimeZone string
+	// Period is the longest time in seconds between two runs of the job
+	Period int
+	// Grace is the time in seconds the job has to ping the monitor after it is scheduled
+	Grace int
 }
 
 func NewMonitor(monitorName string, id string, monitorUrl string, config interface{}) Monitor {
@@ -15,3 +70,56 @@ func NewMonitor(monitorName string, id string, monitorUrl string, config interfa
 		Config: config,
 	}
 }
+
+// CheckType returns the type of check the monitor runs, derived from the scheme of its URL
+// unless it is a heartbeat monitor
+func (m Monitor) CheckType() endpointmonitorv1alpha1.CheckType {
+	if m.Heartbeat != nil {
+		return endpointmonitorv1alpha1.CheckTypeHeartbeat
+	}
+	return CheckTypeOf(m.URL)
+}
+
+// Target returns the host and port of TCP, ICMP, DNS and GRPC monitors, port is 0 when the URL has none
+func (m Monitor) Target() (string, int) {
+	return TargetOf(m.URL)
+}
+
+// CheckTypeOf returns the type of check for a monitor URL, `tcp://`, `icmp://`, `dns://` and `grpc://`
+// targets select TCP, ICMP, DNS and GRPC checks and everything else is HTTP
+func CheckTypeOf(monitorURL string) endpointmonitorv1alpha1.CheckType {
+	scheme, _, found := strings.Cut(monitorURL, "://")
+	if !found {
+		return endpointmonitorv1alpha1.CheckTypeHTTP
+	}
+	switch strings.ToLower(scheme) {
+	case "tcp":
+		return endpointmonitorv1alpha1.CheckTypeTCP
+	case "icmp":
+		return endpointmonitorv1alpha1.CheckTypeICMP
+	case "dns":
+		return endpointmonitorv1alpha1.CheckTypeDNS
+	case "grpc":
+		return endpointmonitorv1alpha1.CheckTypeGRPC
+	}
+	return endpointmonitorv1alpha1.CheckTypeHTTP
+}
+
+// TargetOf returns the host and port of a monitor URL
+func TargetOf(monitorURL string) (string, int) {
+	u, err := url.Parse(monitorURL)
+	if err != nil || u.Host == "" {
+		return monitorURL, 0
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return u.Hostname(), port
+}
+
+// TargetURL builds the monitor URL of a TCP, ICMP, DNS or GRPC check from its host and port
+func TargetURL(checkType endpointmonitorv1alpha1.CheckType, host string, port int) string {
+	scheme := strings.ToLower(string(checkType))
+	if port > 0 {
+		return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port))
+	}
+	return scheme + "://" + host
+}
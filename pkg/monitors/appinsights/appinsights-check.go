@@ -0,0 +1,42 @@
+package appinsights
+
+import (
+	"slices"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// appInsightsFrequencies are the check intervals in seconds accepted by AppInsights
+var appInsightsFrequencies = []int{300, 600, 900}
+
+// TranslateCheck translates a provider agnostic check into an AppInsights config, along with the
+// fields of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.AppInsightsConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.AppInsightsConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			if !slices.Contains(appInsightsFrequencies, check.Interval) {
+				unsupported = append(unsupported, "interval: must be one of 300, 600, 900 seconds")
+				continue
+			}
+			providerConfig.Frequency = check.Interval
+		case "expectedStatusCodes":
+			if len(check.ExpectedStatusCodes) > 1 {
+				unsupported = append(unsupported, "expectedStatusCodes: only one status code is supported")
+				continue
+			}
+			providerConfig.StatusCode = check.ExpectedStatusCodes[0]
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
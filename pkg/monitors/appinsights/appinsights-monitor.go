@@ -100,7 +100,7 @@ func (monitor *AppinsightsMonitorService) Equal(oldMonitor models.Monitor, newMo
 }
 
 // Setup method will initialize a appinsights's go client
-func (aiService *AppinsightsMonitorService) Setup(provider config.Provider) {
+func (aiService *AppinsightsMonitorService) Setup(provider config.Provider) error {
 
 	log.Info("AppInsights Monitor's Setup has been called. Initializing AppInsights Client..")
 
@@ -119,15 +119,13 @@ func (aiService *AppinsightsMonitorService) Setup(provider config.Provider) {
 		if sid := os.Getenv("AZURE_SUBSCRIPTION_ID"); sid != "" {
 			aiService.subscriptionID = sid
 		} else {
-			log.Error(nil, "Azure SubscriptionId is required")
-			os.Exit(1)
+			return fmt.Errorf("azure SubscriptionId is required")
 		}
 	}
 
 	creds, err := azidentity.NewDefaultAzureCredential(nil)
 	if err != nil {
-		log.Error(err, "Error initializing AppInsights Client")
-		os.Exit(1)
+		return fmt.Errorf("error initializing AppInsights Client: %w", err)
 	}
 
 	clientOptions := &arm.ClientOptions{
@@ -141,8 +139,7 @@ func (aiService *AppinsightsMonitorService) Setup(provider config.Provider) {
 	aiService.insightsClient, err = armapplicationinsights.NewWebTestsClient(aiService.subscriptionID, creds, clientOptions)
 
 	if err != nil {
-		log.Error(err, "Error initializing AppInsights Client")
-		os.Exit(1)
+		return fmt.Errorf("error initializing AppInsights Client: %w", err)
 	}
 
 	log.Info("AppInsights Insights Client has been initialized")
@@ -151,13 +148,13 @@ func (aiService *AppinsightsMonitorService) Setup(provider config.Provider) {
 	if aiService.isAlertEnabled() {
 		aiService.alertrulesClient, err = armmonitor.NewAlertRulesClient(aiService.subscriptionID, creds, clientOptions)
 		if err != nil {
-			log.Error(err, "Error initializing AppInsights Alertrules Client")
-			os.Exit(1)
+			return fmt.Errorf("error initializing AppInsights Alertrules Client: %w", err)
 		}
 		log.Info("AppInsights Alertrules Client has been initialized")
 	}
 
 	log.Info("AppInsights Monitor has been initialized")
+	return nil
 }
 
 // GetAll function will return all monitors (appinsights webtest) object in an array
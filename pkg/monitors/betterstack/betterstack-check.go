@@ -0,0 +1,80 @@
+package betterstack
+
+import (
+	"slices"
+	"strings"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// checkFrequencies are the intervals in seconds between two checks accepted by Better Stack
+var checkFrequencies = []int{30, 45, 60, 120, 180, 300, 600, 900, 1800}
+
+// regions are the regions Better Stack checks from
+var regions = []string{"us", "eu", "as", "au"}
+
+// TranslateCheck translates a provider agnostic check into a Better Stack config, along with the fields
+// of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.BetterStackConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.BetterStackConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			if !slices.Contains(checkFrequencies, check.Interval) {
+				unsupported = append(unsupported, "interval: must be one of 30, 45, 60, 120, 180, 300, 600, 900, 1800 seconds")
+				continue
+			}
+			providerConfig.CheckFrequency = check.Interval
+		case "timeout":
+			providerConfig.RequestTimeout = check.Timeout
+		case "method":
+			method := strings.ToLower(check.Method)
+			if !slices.Contains([]string{"get", "head", "post", "put", "patch"}, method) {
+				unsupported = append(unsupported, "method: must be one of GET, HEAD, POST, PUT, PATCH")
+				continue
+			}
+			providerConfig.HTTPMethod = method
+		case "headers":
+			providerConfig.RequestHeaders = check.Headers
+		case "body":
+			providerConfig.RequestBody = check.Body
+		case "expectedStatusCodes":
+			providerConfig.ExpectedStatusCodes = check.ExpectedStatusCodes
+		case "bodyAssertions":
+			if len(check.BodyAssertions) > 1 {
+				unsupported = append(unsupported, "bodyAssertions: only one assertion is supported")
+				continue
+			}
+			assertion := check.BodyAssertions[0]
+			providerConfig.MonitorType = monitorTypeKeyword
+			if assertion.Type == endpointmonitorv1alpha1.BodyAssertionNotContains {
+				providerConfig.MonitorType = "keyword_absence"
+			}
+			providerConfig.RequiredKeyword = assertion.Value
+		case "followRedirects":
+			providerConfig.FollowRedirects = check.FollowRedirects
+		case "verifyTLS":
+			providerConfig.VerifySSL = check.VerifyTLS
+		case "locations":
+			var invalid bool
+			for _, location := range check.Locations {
+				invalid = invalid || !slices.Contains(regions, location)
+			}
+			if invalid {
+				unsupported = append(unsupported, "locations: must be us, eu, as or au")
+				continue
+			}
+			providerConfig.Regions = check.Locations
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
@@ -0,0 +1,164 @@
+package betterstack
+
+import (
+	"sort"
+	"strconv"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+// BetterStackMonitorToBaseMonitorMapper maps a Better Stack monitor to a Monitor, the URL of tcp and ping
+// monitors gets back the scheme of their check type
+func BetterStackMonitorToBaseMonitorMapper(monitor BetterStackMonitor) *models.Monitor {
+	attributes := monitor.Attributes
+	var m models.Monitor
+	m.Name = attributes.PronounceableName
+	m.ID = monitor.ID
+	m.URL = attributes.URL
+	switch attributes.MonitorType {
+	case monitorTypeTCP:
+		port, _ := strconv.Atoi(attributes.Port)
+		m.URL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeTCP, attributes.URL, port)
+	case monitorTypePing:
+		m.URL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeICMP, attributes.URL, 0)
+	}
+
+	providerConfig := &endpointmonitorv1alpha1.BetterStackConfig{
+		MonitorType:         attributes.MonitorType,
+		CheckFrequency:      attributes.CheckFrequency,
+		Regions:             attributes.Regions,
+		HTTPMethod:          attributes.HTTPMethod,
+		RequestBody:         attributes.RequestBody,
+		RequestTimeout:      attributes.RequestTimeout,
+		RequiredKeyword:     attributes.RequiredKeyword,
+		ExpectedStatusCodes: attributes.ExpectedStatusCodes,
+		FollowRedirects:     attributes.FollowRedirects,
+		VerifySSL:           attributes.VerifySSL,
+		PolicyID:            attributes.PolicyID,
+		ConfirmationPeriod:  attributes.ConfirmationPeriod,
+		Email:               attributes.Email,
+		SMS:                 attributes.SMS,
+		Call:                attributes.Call,
+		Push:                attributes.Push,
+		Paused:              attributes.Paused,
+	}
+	if len(attributes.RequestHeaders) > 0 {
+		providerConfig.RequestHeaders = map[string]string{}
+		for _, header := range attributes.RequestHeaders {
+			providerConfig.RequestHeaders[header.Name] = header.Value
+		}
+	}
+	m.Config = providerConfig
+	return &m
+}
+
+// BetterStackHeartbeatToBaseMonitorMapper maps a Better Stack heartbeat to a Monitor, the URL of the
+// monitor is the ping URL of the heartbeat
+func BetterStackHeartbeatToBaseMonitorMapper(heartbeat BetterStackHeartbeat) *models.Monitor {
+	attributes := heartbeat.Attributes
+	var m models.Monitor
+	m.Name = attributes.Name
+	m.ID = heartbeat.ID
+	m.URL = attributes.URL
+	m.Heartbeat = &models.Heartbeat{Period: attributes.Period, Grace: attributes.Grace}
+	m.Config = &endpointmonitorv1alpha1.BetterStackConfig{
+		PolicyID: attributes.PolicyID,
+		Email:    attributes.Email,
+		SMS:      attributes.SMS,
+		Call:     attributes.Call,
+		Push:     attributes.Push,
+		Paused:   attributes.Paused,
+	}
+	return &m
+}
+
+// BaseMonitorToBetterStackMonitorAttributesMapper maps a Monitor to the body of the create and update
+// monitor APIs. TCP checks become tcp monitors of the host and port, ICMP checks ping monitors of the host.
+func BaseMonitorToBetterStackMonitorAttributesMapper(m models.Monitor) BetterStackMonitorAttributes {
+	providerConfig := betterStackConfig(m)
+	attributes := BetterStackMonitorAttributes{
+		URL:                 m.URL,
+		PronounceableName:   m.Name,
+		MonitorType:         providerConfig.MonitorType,
+		CheckFrequency:      providerConfig.CheckFrequency,
+		Regions:             providerConfig.Regions,
+		HTTPMethod:          providerConfig.HTTPMethod,
+		RequestBody:         providerConfig.RequestBody,
+		RequestTimeout:      providerConfig.RequestTimeout,
+		RequiredKeyword:     providerConfig.RequiredKeyword,
+		ExpectedStatusCodes: providerConfig.ExpectedStatusCodes,
+		FollowRedirects:     providerConfig.FollowRedirects,
+		VerifySSL:           providerConfig.VerifySSL,
+		PolicyID:            providerConfig.PolicyID,
+		ConfirmationPeriod:  providerConfig.ConfirmationPeriod,
+		Email:               providerConfig.Email,
+		SMS:                 providerConfig.SMS,
+		Call:                providerConfig.Call,
+		Push:                providerConfig.Push,
+		Paused:              providerConfig.Paused,
+	}
+
+	switch m.CheckType() {
+	case endpointmonitorv1alpha1.CheckTypeTCP:
+		host, port := m.Target()
+		attributes.URL = host
+		attributes.Port = strconv.Itoa(port)
+		attributes.MonitorType = monitorTypeTCP
+	case endpointmonitorv1alpha1.CheckTypeICMP:
+		host, _ := m.Target()
+		attributes.URL = host
+		attributes.MonitorType = monitorTypePing
+	default:
+		if len(attributes.MonitorType) > 0 {
+			break
+		}
+		switch {
+		case len(attributes.RequiredKeyword) > 0:
+			attributes.MonitorType = monitorTypeKeyword
+		case len(attributes.ExpectedStatusCodes) > 0:
+			attributes.MonitorType = monitorTypeExpectedStatusCode
+		default:
+			attributes.MonitorType = monitorTypeStatus
+		}
+	}
+
+	names := make([]string, 0, len(providerConfig.RequestHeaders))
+	for name := range providerConfig.RequestHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attributes.RequestHeaders = append(attributes.RequestHeaders, BetterStackRequestHeader{Name: name, Value: providerConfig.RequestHeaders[name]})
+	}
+	return attributes
+}
+
+// BaseMonitorToBetterStackHeartbeatAttributesMapper maps a heartbeat Monitor to the body of the create and
+// update heartbeat APIs. The heartbeat expects a ping every period of the job.
+func BaseMonitorToBetterStackHeartbeatAttributesMapper(m models.Monitor) BetterStackHeartbeatAttributes {
+	providerConfig := betterStackConfig(m)
+	attributes := BetterStackHeartbeatAttributes{
+		Name:     m.Name,
+		Period:   minHeartbeatPeriod,
+		PolicyID: providerConfig.PolicyID,
+		Email:    providerConfig.Email,
+		SMS:      providerConfig.SMS,
+		Call:     providerConfig.Call,
+		Push:     providerConfig.Push,
+		Paused:   providerConfig.Paused,
+	}
+	if m.Heartbeat != nil {
+		attributes.Period = max(m.Heartbeat.Period, minHeartbeatPeriod)
+		attributes.Grace = m.Heartbeat.Grace
+	}
+	return attributes
+}
+
+// betterStackConfig returns the config of the monitor, an empty one when it has none
+func betterStackConfig(m models.Monitor) *endpointmonitorv1alpha1.BetterStackConfig {
+	if providerConfig, ok := m.Config.(*endpointmonitorv1alpha1.BetterStackConfig); ok && providerConfig != nil {
+		return providerConfig
+	}
+	return &endpointmonitorv1alpha1.BetterStackConfig{}
+}
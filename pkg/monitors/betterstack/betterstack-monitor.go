@@ -0,0 +1,313 @@
+// Package betterstack adds Better Stack Uptime monitoring support in IngressMonitorController
+package betterstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+var log = logf.Log.WithName("betterstack-monitor")
+
+const (
+	// DefaultAPIURL is the URL of the Better Stack Uptime API
+	DefaultAPIURL = "https://uptime.betterstack.com"
+	// TimeoutDefaultValue is the timeout in seconds of a request to the API
+	TimeoutDefaultValue = 30
+
+	// minHeartbeatPeriod is the shortest period in seconds of a heartbeat accepted by Better Stack
+	minHeartbeatPeriod = 30
+	// pageSize is the number of monitors and heartbeats fetched per page, the largest accepted by the API
+	pageSize = 250
+
+	// heartbeatPingPath is the path of the ping URLs of heartbeats
+	heartbeatPingPath = "/api/v1/heartbeat/"
+)
+
+// Types of the monitors created for the check types
+const (
+	monitorTypeStatus             = "status"
+	monitorTypeExpectedStatusCode = "expected_status_code"
+	monitorTypeKeyword            = "keyword"
+	monitorTypePing               = "ping"
+	monitorTypeTCP                = "tcp"
+)
+
+// BetterStackMonitorService manages the monitors and heartbeats of a Better Stack team, heartbeats are
+// the monitors of heartbeat checks
+type BetterStackMonitorService struct {
+	apiKey string
+	url    string
+	client *http.Client
+}
+
+// Setup function is used to initialise the Better Stack service
+func (service *BetterStackMonitorService) Setup(p config.Provider) error {
+	service.apiKey = p.ApiKey
+	service.url = strings.TrimSuffix(p.ApiURL, "/")
+	if len(service.url) == 0 {
+		service.url = DefaultAPIURL
+	}
+	service.client = &http.Client{Timeout: TimeoutDefaultValue * time.Second}
+
+	if len(service.apiKey) == 0 {
+		return fmt.Errorf("apiKey is required for provider %s", p.Name)
+	}
+	return nil
+}
+
+// CheckConnection verifies the API token by listing a monitor
+func (service *BetterStackMonitorService) CheckConnection() error {
+	_, err := service.doRequest(http.MethodGet, "/api/v2/monitors?per_page=1", nil)
+	return err
+}
+
+// SupportsCheckType returns whether Better Stack can run the check type, tcp, ping and heartbeat monitors
+// are supported besides HTTP
+func (service *BetterStackMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType == endpointmonitorv1alpha1.CheckTypeTCP || checkType == endpointmonitorv1alpha1.CheckTypeICMP ||
+		checkType == endpointmonitorv1alpha1.CheckTypeHeartbeat
+}
+
+// GetAll fetches all monitors and heartbeats of the team
+func (service *BetterStackMonitorService) GetAll() ([]models.Monitor, error) {
+	var monitors []models.Monitor
+	next := fmt.Sprintf("/api/v2/monitors?per_page=%d", pageSize)
+	for len(next) > 0 {
+		body, err := service.doRequest(http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var list BetterStackMonitorList
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal betterstack monitors response: %w", err)
+		}
+		for _, monitor := range list.Data {
+			monitors = append(monitors, *BetterStackMonitorToBaseMonitorMapper(monitor))
+		}
+		next = list.Pagination.Next
+	}
+
+	next = fmt.Sprintf("/api/v2/heartbeats?per_page=%d", pageSize)
+	for len(next) > 0 {
+		body, err := service.doRequest(http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var list BetterStackHeartbeatList
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal betterstack heartbeats response: %w", err)
+		}
+		for _, heartbeat := range list.Data {
+			monitors = append(monitors, *BetterStackHeartbeatToBaseMonitorMapper(heartbeat))
+		}
+		next = list.Pagination.Next
+	}
+	return monitors, nil
+}
+
+// GetByName fetches the monitor or heartbeat with the name, nil is returned when there is none
+func (service *BetterStackMonitorService) GetByName(name string) (*models.Monitor, error) {
+	monitors, err := service.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, monitor := range monitors {
+		if monitor.Name == name {
+			return &monitor, nil
+		}
+	}
+	return nil, nil
+}
+
+// Add creates a monitor, or a heartbeat for heartbeat checks
+func (service *BetterStackMonitorService) Add(m models.Monitor) {
+	var err error
+	if isHeartbeat(m) {
+		_, err = service.doRequest(http.MethodPost, "/api/v2/heartbeats", BaseMonitorToBetterStackHeartbeatAttributesMapper(m))
+	} else {
+		_, err = service.doRequest(http.MethodPost, "/api/v2/monitors", BaseMonitorToBetterStackMonitorAttributesMapper(m))
+	}
+	if err != nil {
+		log.Error(err, "Monitor couldn't be added: "+m.Name)
+		return
+	}
+	log.Info("Monitor Added: " + m.Name)
+}
+
+// Update updates an existing monitor or heartbeat. The request headers of monitors are replaced, the
+// existing ones are removed by their ID.
+func (service *BetterStackMonitorService) Update(m models.Monitor) {
+	var err error
+	if isHeartbeat(m) {
+		_, err = service.doRequest(http.MethodPatch, "/api/v2/heartbeats/"+m.ID, BaseMonitorToBetterStackHeartbeatAttributesMapper(m))
+	} else {
+		attributes := BaseMonitorToBetterStackMonitorAttributesMapper(m)
+		var existing *BetterStackMonitor
+		if existing, err = service.getMonitor(m.ID); err == nil {
+			for _, header := range existing.Attributes.RequestHeaders {
+				attributes.RequestHeaders = append(attributes.RequestHeaders, BetterStackRequestHeader{ID: header.ID, Destroy: true})
+			}
+			_, err = service.doRequest(http.MethodPatch, "/api/v2/monitors/"+m.ID, attributes)
+		}
+	}
+	if err != nil {
+		log.Error(err, "Monitor couldn't be updated: "+m.Name)
+		return
+	}
+	log.Info("Monitor Updated: " + m.Name)
+}
+
+// Remove deletes an existing monitor or heartbeat
+func (service *BetterStackMonitorService) Remove(m models.Monitor) {
+	if _, err := service.doRequest(http.MethodDelete, service.resourcePath(m), nil); err != nil {
+		log.Error(err, "Monitor couldn't be removed: "+m.Name)
+		return
+	}
+	log.Info("Monitor Removed: " + m.Name)
+}
+
+// Pause pauses the monitor or heartbeat without changing its config
+func (service *BetterStackMonitorService) Pause(m models.Monitor) error {
+	if _, err := service.doRequest(http.MethodPatch, service.resourcePath(m), map[string]bool{"paused": true}); err != nil {
+		return err
+	}
+	log.Info("Monitor Paused: " + m.Name)
+	return nil
+}
+
+// Equal compares the attributes of the monitors sent to the API. The optional attributes the new monitor
+// leaves unset keep the value chosen by Better Stack, and the order of the regions is ignored.
+func (service *BetterStackMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
+	if isHeartbeat(oldMonitor) != isHeartbeat(newMonitor) {
+		return false
+	}
+	if isHeartbeat(newMonitor) {
+		oldHeartbeat := BaseMonitorToBetterStackHeartbeatAttributesMapper(oldMonitor)
+		newHeartbeat := BaseMonitorToBetterStackHeartbeatAttributesMapper(newMonitor)
+		oldHeartbeat.PolicyID = keepUnset(oldHeartbeat.PolicyID, newHeartbeat.PolicyID)
+		oldHeartbeat.Email = keepUnset(oldHeartbeat.Email, newHeartbeat.Email)
+		oldHeartbeat.SMS = keepUnset(oldHeartbeat.SMS, newHeartbeat.SMS)
+		oldHeartbeat.Call = keepUnset(oldHeartbeat.Call, newHeartbeat.Call)
+		oldHeartbeat.Push = keepUnset(oldHeartbeat.Push, newHeartbeat.Push)
+		return reflect.DeepEqual(oldHeartbeat, newHeartbeat)
+	}
+
+	oldAttributes := BaseMonitorToBetterStackMonitorAttributesMapper(oldMonitor)
+	newAttributes := BaseMonitorToBetterStackMonitorAttributesMapper(newMonitor)
+	oldAttributes.CheckFrequency = keepUnset(oldAttributes.CheckFrequency, newAttributes.CheckFrequency)
+	oldAttributes.HTTPMethod = keepUnset(oldAttributes.HTTPMethod, newAttributes.HTTPMethod)
+	oldAttributes.RequestTimeout = keepUnset(oldAttributes.RequestTimeout, newAttributes.RequestTimeout)
+	oldAttributes.FollowRedirects = keepUnset(oldAttributes.FollowRedirects, newAttributes.FollowRedirects)
+	oldAttributes.VerifySSL = keepUnset(oldAttributes.VerifySSL, newAttributes.VerifySSL)
+	oldAttributes.PolicyID = keepUnset(oldAttributes.PolicyID, newAttributes.PolicyID)
+	oldAttributes.ConfirmationPeriod = keepUnset(oldAttributes.ConfirmationPeriod, newAttributes.ConfirmationPeriod)
+	oldAttributes.Email = keepUnset(oldAttributes.Email, newAttributes.Email)
+	oldAttributes.SMS = keepUnset(oldAttributes.SMS, newAttributes.SMS)
+	oldAttributes.Call = keepUnset(oldAttributes.Call, newAttributes.Call)
+	oldAttributes.Push = keepUnset(oldAttributes.Push, newAttributes.Push)
+	if len(newAttributes.Regions) == 0 {
+		oldAttributes.Regions = nil
+	}
+	if len(newAttributes.ExpectedStatusCodes) == 0 {
+		oldAttributes.ExpectedStatusCodes = nil
+	}
+	oldAttributes.Regions = sorted(oldAttributes.Regions)
+	newAttributes.Regions = sorted(newAttributes.Regions)
+	return reflect.DeepEqual(oldAttributes, newAttributes)
+}
+
+// keepUnset returns the old value of an attribute, or the zero value when the new monitor leaves it unset
+func keepUnset[T comparable](oldValue T, newValue T) T {
+	var zero T
+	if newValue == zero {
+		return zero
+	}
+	return oldValue
+}
+
+func sorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return values
+}
+
+// isHeartbeat returns whether the monitor is a heartbeat, monitors read back from Better Stack are
+// recognized by their ping URL
+func isHeartbeat(m models.Monitor) bool {
+	return m.Heartbeat != nil || strings.Contains(m.URL, heartbeatPingPath)
+}
+
+// resourcePath returns the path of the monitor or heartbeat in the API
+func (service *BetterStackMonitorService) resourcePath(m models.Monitor) string {
+	if isHeartbeat(m) {
+		return "/api/v2/heartbeats/" + m.ID
+	}
+	return "/api/v2/monitors/" + m.ID
+}
+
+func (service *BetterStackMonitorService) getMonitor(id string) (*BetterStackMonitor, error) {
+	body, err := service.doRequest(http.MethodGet, "/api/v2/monitors/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response BetterStackMonitorResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal betterstack monitor response: %w", err)
+	}
+	return &response.Data, nil
+}
+
+// doRequest sends a request to the API and returns the body of a successful response. The path may
+// be the absolute URL of the next page of a list.
+func (service *BetterStackMonitorService) doRequest(method string, path string, payload interface{}) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(raw)
+	}
+	requestURL := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		requestURL = service.url + path
+	}
+	req, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+service.apiKey)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := service.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("betterstack %s %s returned status %d: %s", method, path, resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
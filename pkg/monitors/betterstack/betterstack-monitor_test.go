@@ -0,0 +1,343 @@
+package betterstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+func init() {
+	// To allow normal logging to be printed if tests fails
+	// Dev mode is an extra feature to make output more readable
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+}
+
+const (
+	testAPIKey = "team-token"
+	// testPageSize is the page size of the fake, smaller than the page size requested to test the pagination
+	testPageSize = 2
+)
+
+// fakeBetterStack is an in-memory Better Stack team, which fills in the defaults of the API
+type fakeBetterStack struct {
+	mu         sync.Mutex
+	url        string
+	nextID     int
+	monitors   map[string]BetterStackMonitorAttributes
+	heartbeats map[string]BetterStackHeartbeatAttributes
+}
+
+func (f *fakeBetterStack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+testAPIKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	resource, id, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v2/"), "/")
+	body, _ := io.ReadAll(r.Body)
+	switch resource {
+	case "monitors":
+		f.serveMonitors(w, r, id, body)
+	case "heartbeats":
+		f.serveHeartbeats(w, r, id, body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeBetterStack) serveMonitors(w http.ResponseWriter, r *http.Request, id string, body []byte) {
+	switch r.Method {
+	case http.MethodGet:
+		if id == "" {
+			var list BetterStackMonitorList
+			for _, id := range f.page(r, sortedKeys(f.monitors), &list.Pagination, "monitors") {
+				list.Data = append(list.Data, BetterStackMonitor{ID: id, Type: "monitor", Attributes: f.monitors[id]})
+			}
+			_ = json.NewEncoder(w).Encode(list)
+			return
+		}
+		attributes, ok := f.monitors[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(BetterStackMonitorResponse{Data: BetterStackMonitor{ID: id, Type: "monitor", Attributes: attributes}})
+	case http.MethodPost:
+		var attributes BetterStackMonitorAttributes
+		_ = json.Unmarshal(body, &attributes)
+		f.nextID++
+		id = strconv.Itoa(f.nextID)
+		if attributes.CheckFrequency == 0 {
+			attributes.CheckFrequency = 180
+		}
+		if len(attributes.Regions) == 0 {
+			attributes.Regions = []string{"us", "eu", "as", "au"}
+		}
+		if len(attributes.HTTPMethod) == 0 {
+			attributes.HTTPMethod = "get"
+		}
+		if attributes.RequestTimeout == 0 {
+			attributes.RequestTimeout = 30
+		}
+		enabled := true
+		attributes.VerifySSL, attributes.FollowRedirects, attributes.Email = &enabled, &enabled, &enabled
+		attributes.RequestHeaders = f.applyHeaders(nil, attributes.RequestHeaders)
+		f.monitors[id] = attributes
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(BetterStackMonitorResponse{Data: BetterStackMonitor{ID: id, Type: "monitor", Attributes: attributes}})
+	case http.MethodPatch:
+		attributes, ok := f.monitors[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		headers := attributes.RequestHeaders
+		attributes.RequestHeaders = nil
+		_ = json.Unmarshal(body, &attributes)
+		attributes.RequestHeaders = f.applyHeaders(headers, attributes.RequestHeaders)
+		f.monitors[id] = attributes
+		_ = json.NewEncoder(w).Encode(BetterStackMonitorResponse{Data: BetterStackMonitor{ID: id, Type: "monitor", Attributes: attributes}})
+	case http.MethodDelete:
+		if _, ok := f.monitors[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.monitors, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeBetterStack) serveHeartbeats(w http.ResponseWriter, r *http.Request, id string, body []byte) {
+	switch r.Method {
+	case http.MethodGet:
+		var list BetterStackHeartbeatList
+		for _, id := range f.page(r, sortedKeys(f.heartbeats), &list.Pagination, "heartbeats") {
+			list.Data = append(list.Data, BetterStackHeartbeat{ID: id, Type: "heartbeat", Attributes: f.heartbeats[id]})
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case http.MethodPost:
+		var attributes BetterStackHeartbeatAttributes
+		_ = json.Unmarshal(body, &attributes)
+		f.nextID++
+		id = strconv.Itoa(f.nextID)
+		attributes.URL = f.url + heartbeatPingPath + "token-" + id
+		f.heartbeats[id] = attributes
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(BetterStackHeartbeatResponse{Data: BetterStackHeartbeat{ID: id, Type: "heartbeat", Attributes: attributes}})
+	case http.MethodPatch:
+		attributes, ok := f.heartbeats[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		pingURL := attributes.URL
+		_ = json.Unmarshal(body, &attributes)
+		attributes.URL = pingURL
+		f.heartbeats[id] = attributes
+		_ = json.NewEncoder(w).Encode(BetterStackHeartbeatResponse{Data: BetterStackHeartbeat{ID: id, Type: "heartbeat", Attributes: attributes}})
+	case http.MethodDelete:
+		if _, ok := f.heartbeats[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.heartbeats, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// applyHeaders applies the request headers of a request to the existing ones like the API, headers with
+// an ID are updated or removed and the others added
+func (f *fakeBetterStack) applyHeaders(existing []BetterStackRequestHeader, changes []BetterStackRequestHeader) []BetterStackRequestHeader {
+	headers := []BetterStackRequestHeader{}
+	destroyed := map[string]bool{}
+	for _, change := range changes {
+		if change.Destroy {
+			destroyed[change.ID] = true
+		}
+	}
+	for _, header := range existing {
+		if !destroyed[header.ID] {
+			headers = append(headers, header)
+		}
+	}
+	for _, change := range changes {
+		if len(change.ID) == 0 {
+			f.nextID++
+			change.ID = "header-" + strconv.Itoa(f.nextID)
+			headers = append(headers, change)
+		}
+	}
+	return headers
+}
+
+// page returns the IDs of the page requested, and links to the next one
+func (f *fakeBetterStack) page(r *http.Request, ids []string, pagination *BetterStackPagination, resource string) []string {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	page = max(page, 1)
+	start := min((page-1)*testPageSize, len(ids))
+	end := min(start+testPageSize, len(ids))
+	if end < len(ids) {
+		pagination.Next = fmt.Sprintf("%s/api/v2/%s?page=%d", f.url, resource, page+1)
+	}
+	return ids[start:end]
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newTestService(t *testing.T) (*BetterStackMonitorService, *fakeBetterStack) {
+	fake := &fakeBetterStack{monitors: map[string]BetterStackMonitorAttributes{}, heartbeats: map[string]BetterStackHeartbeatAttributes{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fake.url = server.URL
+
+	service := &BetterStackMonitorService{}
+	assert.NilError(t, service.Setup(config.Provider{Name: "BetterStack", ApiKey: testAPIKey, ApiURL: server.URL}))
+	return service, fake
+}
+
+func TestBetterStackMonitorLifecycle(t *testing.T) {
+	service, fake := newTestService(t)
+	assert.NilError(t, service.CheckConnection())
+
+	spec := &endpointmonitorv1alpha1.BetterStackConfig{
+		CheckFrequency:  60,
+		Regions:         []string{"eu", "us"},
+		RequestHeaders:  map[string]string{"Authorization": "Bearer token"},
+		RequiredKeyword: "ok",
+		PolicyID:        "42",
+	}
+	service.Add(models.Monitor{Name: "checkout-shop", URL: "https://checkout.example.com/healthz", Config: spec})
+
+	monitor, err := service.GetByName("checkout-shop")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor != nil)
+	assert.Equal(t, monitor.URL, "https://checkout.example.com/healthz")
+	providerConfig := monitor.Config.(*endpointmonitorv1alpha1.BetterStackConfig)
+	assert.Equal(t, providerConfig.MonitorType, monitorTypeKeyword)
+	assert.Equal(t, providerConfig.PolicyID, "42")
+	assert.DeepEqual(t, providerConfig.RequestHeaders, map[string]string{"Authorization": "Bearer token"})
+
+	// The defaults filled in by Better Stack and the order of the regions aren't changes
+	unchanged := models.Monitor{Name: "checkout-shop", URL: "https://checkout.example.com/healthz", Config: &endpointmonitorv1alpha1.BetterStackConfig{
+		CheckFrequency:  60,
+		Regions:         []string{"us", "eu"},
+		RequestHeaders:  map[string]string{"Authorization": "Bearer token"},
+		RequiredKeyword: "ok",
+		PolicyID:        "42",
+	}}
+	assert.Assert(t, service.Equal(*monitor, unchanged))
+
+	updated := unchanged
+	updated.ID = monitor.ID
+	updated.Config = &endpointmonitorv1alpha1.BetterStackConfig{CheckFrequency: 300, RequestHeaders: map[string]string{"X-Probe": "imc"}}
+	assert.Assert(t, !service.Equal(*monitor, updated))
+	service.Update(updated)
+	attributes := fake.monitors[monitor.ID]
+	assert.Equal(t, attributes.CheckFrequency, 300)
+	assert.Equal(t, attributes.MonitorType, monitorTypeStatus)
+	assert.Equal(t, len(attributes.RequestHeaders), 1)
+	assert.Equal(t, attributes.RequestHeaders[0].Name, "X-Probe")
+
+	assert.NilError(t, service.Pause(updated))
+	assert.Assert(t, fake.monitors[monitor.ID].Paused)
+
+	service.Remove(updated)
+	monitor, err = service.GetByName("checkout-shop")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor == nil)
+}
+
+func TestBetterStackTCPAndPingMonitors(t *testing.T) {
+	service, fake := newTestService(t)
+	service.Add(models.Monitor{Name: "postgres", URL: "tcp://db.example.com:5432"})
+	service.Add(models.Monitor{Name: "gateway", URL: "icmp://gateway.example.com"})
+	service.Add(models.Monitor{Name: "api", URL: "https://api.example.com"})
+
+	// The three monitors are listed over two pages
+	monitors, err := service.GetAll()
+	assert.NilError(t, err)
+	assert.Equal(t, len(monitors), 3)
+
+	postgres, err := service.GetByName("postgres")
+	assert.NilError(t, err)
+	assert.Equal(t, postgres.URL, "tcp://db.example.com:5432")
+	assert.Equal(t, fake.monitors[postgres.ID].MonitorType, monitorTypeTCP)
+	assert.Equal(t, fake.monitors[postgres.ID].Port, "5432")
+	assert.Assert(t, service.Equal(*postgres, models.Monitor{Name: "postgres", URL: "tcp://db.example.com:5432"}))
+
+	gateway, err := service.GetByName("gateway")
+	assert.NilError(t, err)
+	assert.Equal(t, gateway.URL, "icmp://gateway.example.com")
+	assert.Equal(t, fake.monitors[gateway.ID].MonitorType, monitorTypePing)
+}
+
+func TestBetterStackHeartbeats(t *testing.T) {
+	service, fake := newTestService(t)
+	heartbeat := &models.Heartbeat{Schedule: "0 2 * * *", Period: 86400, Grace: 300}
+	service.Add(models.Monitor{Name: "nightly-backup", Heartbeat: heartbeat, Config: &endpointmonitorv1alpha1.BetterStackConfig{PolicyID: "7"}})
+
+	monitor, err := service.GetByName("nightly-backup")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor != nil)
+	assert.Assert(t, strings.HasPrefix(monitor.URL, fake.url+heartbeatPingPath), monitor.URL)
+	assert.Equal(t, monitor.Heartbeat.Period, 86400)
+	assert.Equal(t, monitor.Heartbeat.Grace, 300)
+	assert.Assert(t, service.Equal(*monitor, models.Monitor{Name: "nightly-backup", Heartbeat: heartbeat, Config: &endpointmonitorv1alpha1.BetterStackConfig{PolicyID: "7"}}))
+
+	updated := models.Monitor{Name: "nightly-backup", ID: monitor.ID, Heartbeat: &models.Heartbeat{Period: 3600, Grace: 600}}
+	assert.Assert(t, !service.Equal(*monitor, updated))
+	service.Update(updated)
+	assert.Equal(t, fake.heartbeats[monitor.ID].Period, 3600)
+
+	// Monitors read back are heartbeats by their ping URL
+	paused := models.Monitor{Name: monitor.Name, ID: monitor.ID, URL: monitor.URL}
+	assert.NilError(t, service.Pause(paused))
+	assert.Assert(t, fake.heartbeats[monitor.ID].Paused)
+	service.Remove(paused)
+	assert.Equal(t, len(fake.heartbeats), 0)
+}
+
+func TestSetupRequiresAPIKey(t *testing.T) {
+	err := (&BetterStackMonitorService{}).Setup(config.Provider{Name: "BetterStack"})
+	assert.ErrorContains(t, err, "apiKey is required")
+}
+
+func TestTranslateCheck(t *testing.T) {
+	providerConfig, unsupported := TranslateCheck(&endpointmonitorv1alpha1.Check{
+		Interval:       60,
+		Method:         "POST",
+		BodyAssertions: []endpointmonitorv1alpha1.BodyAssertion{{Type: endpointmonitorv1alpha1.BodyAssertionNotContains, Value: "error"}},
+		Locations:      []string{"eu", "mars"},
+	})
+	assert.DeepEqual(t, providerConfig, &endpointmonitorv1alpha1.BetterStackConfig{
+		CheckFrequency:  60,
+		HTTPMethod:      "post",
+		MonitorType:     "keyword_absence",
+		RequiredKeyword: "error",
+	})
+	assert.DeepEqual(t, unsupported, []string{"locations: must be us, eu, as or au"})
+}
@@ -0,0 +1,92 @@
+package betterstack
+
+// BetterStackMonitor is a monitor of the Better Stack Uptime API v2
+type BetterStackMonitor struct {
+	ID         string                       `json:"id"`
+	Type       string                       `json:"type"`
+	Attributes BetterStackMonitorAttributes `json:"attributes"`
+}
+
+// BetterStackMonitorAttributes are the attributes of a monitor, they are also the body of the create
+// and update monitor APIs
+type BetterStackMonitorAttributes struct {
+	URL                 string                     `json:"url"`
+	PronounceableName   string                     `json:"pronounceable_name"`
+	MonitorType         string                     `json:"monitor_type"`
+	Port                string                     `json:"port,omitempty"`
+	CheckFrequency      int                        `json:"check_frequency,omitempty"`
+	Regions             []string                   `json:"regions,omitempty"`
+	RequestHeaders      []BetterStackRequestHeader `json:"request_headers,omitempty"`
+	HTTPMethod          string                     `json:"http_method,omitempty"`
+	RequestBody         string                     `json:"request_body,omitempty"`
+	RequestTimeout      int                        `json:"request_timeout,omitempty"`
+	RequiredKeyword     string                     `json:"required_keyword,omitempty"`
+	ExpectedStatusCodes []int                      `json:"expected_status_codes,omitempty"`
+	FollowRedirects     *bool                      `json:"follow_redirects,omitempty"`
+	VerifySSL           *bool                      `json:"verify_ssl,omitempty"`
+	PolicyID            string                     `json:"policy_id,omitempty"`
+	ConfirmationPeriod  int                        `json:"confirmation_period,omitempty"`
+	Email               *bool                      `json:"email,omitempty"`
+	SMS                 *bool                      `json:"sms,omitempty"`
+	Call                *bool                      `json:"call,omitempty"`
+	Push                *bool                      `json:"push,omitempty"`
+	Paused              bool                       `json:"paused"`
+}
+
+// BetterStackRequestHeader is a request header of a monitor. Headers are updated by ID, and removed by
+// sending their ID with _destroy.
+type BetterStackRequestHeader struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Destroy bool   `json:"_destroy,omitempty"`
+}
+
+// BetterStackHeartbeat is a heartbeat of the Better Stack Uptime API v2
+type BetterStackHeartbeat struct {
+	ID         string                         `json:"id"`
+	Type       string                         `json:"type"`
+	Attributes BetterStackHeartbeatAttributes `json:"attributes"`
+}
+
+// BetterStackHeartbeatAttributes are the attributes of a heartbeat, they are also the body of the create
+// and update heartbeat APIs where the ping URL is ignored
+type BetterStackHeartbeatAttributes struct {
+	URL      string `json:"url,omitempty"`
+	Name     string `json:"name"`
+	Period   int    `json:"period"`
+	Grace    int    `json:"grace"`
+	PolicyID string `json:"policy_id,omitempty"`
+	Email    *bool  `json:"email,omitempty"`
+	SMS      *bool  `json:"sms,omitempty"`
+	Call     *bool  `json:"call,omitempty"`
+	Push     *bool  `json:"push,omitempty"`
+	Paused   bool   `json:"paused"`
+}
+
+// BetterStackPagination links to the pages of a list response, next is empty on the last page
+type BetterStackPagination struct {
+	Next string `json:"next"`
+}
+
+// BetterStackMonitorList is a page of the list monitors API
+type BetterStackMonitorList struct {
+	Data       []BetterStackMonitor  `json:"data"`
+	Pagination BetterStackPagination `json:"pagination"`
+}
+
+// BetterStackHeartbeatList is a page of the list heartbeats API
+type BetterStackHeartbeatList struct {
+	Data       []BetterStackHeartbeat `json:"data"`
+	Pagination BetterStackPagination  `json:"pagination"`
+}
+
+// BetterStackMonitorResponse is the response of the get, create and update monitor APIs
+type BetterStackMonitorResponse struct {
+	Data BetterStackMonitor `json:"data"`
+}
+
+// BetterStackHeartbeatResponse is the response of the get, create and update heartbeat APIs
+type BetterStackHeartbeatResponse struct {
+	Data BetterStackHeartbeat `json:"data"`
+}
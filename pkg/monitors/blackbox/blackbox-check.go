@@ -0,0 +1,30 @@
+package blackbox
+
+import (
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// TranslateCheck translates a provider agnostic check into a Blackbox config, along with the fields
+// of the check it can't express. The request and assertions of a probe are set by the module of the
+// blackbox_exporter.
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.BlackboxConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.BlackboxConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			providerConfig.Interval = check.Interval
+		case "timeout":
+			providerConfig.ScrapeTimeout = check.Timeout
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
@@ -0,0 +1,150 @@
+package blackbox
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+// ProbeGVK is the prometheus-operator Probe, which is managed as unstructured so that prometheus-operator
+// doesn't have to be installed with the other providers
+var ProbeGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "Probe"}
+
+const (
+	// MonitorNameAnnotation holds the name of the monitor of a Probe, its name is restricted to a DNS label
+	MonitorNameAnnotation = "endpointmonitor.stakater.com/monitor-name"
+	// MonitorURLAnnotation holds the URL of the monitor of a Probe, its target drops the scheme of TCP checks
+	MonitorURLAnnotation = "endpointmonitor.stakater.com/monitor-url"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "ingressmonitorcontroller"
+
+	// maxProbeNameLength keeps the names of the Probes valid label values
+	maxProbeNameLength = 63
+)
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ProbeName returns the name of the Probe of a monitor. Monitor names that aren't valid object names are
+// sanitized and suffixed with their hash, so that they don't collide.
+func ProbeName(monitorName string) string {
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(monitorName), "-"), "-")
+	if name == monitorName && len(name) <= maxProbeNameLength {
+		return name
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(monitorName))
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+	if len(name) > maxProbeNameLength-len(suffix) {
+		name = strings.TrimRight(name[:maxProbeNameLength-len(suffix)], "-")
+	}
+	return strings.TrimPrefix(name+suffix, "-")
+}
+
+// probeSpec returns the spec of the Probe of the monitor
+func (service *BlackboxMonitorService) probeSpec(m models.Monitor) map[string]interface{} {
+	providerConfig := configOf(m)
+	staticConfig := map[string]interface{}{
+		"static": []interface{}{target(m)},
+	}
+	if len(providerConfig.Labels) > 0 {
+		staticConfig["labels"] = stringMap(providerConfig.Labels)
+	}
+	spec := map[string]interface{}{
+		"jobName": m.Name,
+		"module":  service.module(m),
+		"prober":  copyMap(service.prober),
+		"targets": map[string]interface{}{"staticConfig": staticConfig},
+	}
+	if providerConfig.Interval > 0 {
+		spec["interval"] = duration(providerConfig.Interval)
+	}
+	if providerConfig.ScrapeTimeout > 0 {
+		spec["scrapeTimeout"] = duration(providerConfig.ScrapeTimeout)
+	}
+	return spec
+}
+
+// ProbeToBaseMonitorMapper maps a Probe to a monitor, nil is returned for Probes that don't hold a monitor
+func ProbeToBaseMonitorMapper(probe *unstructured.Unstructured) *models.Monitor {
+	annotations := probe.GetAnnotations()
+	name, ok := annotations[MonitorNameAnnotation]
+	if !ok {
+		return nil
+	}
+	providerConfig := &endpointmonitorv1alpha1.BlackboxConfig{}
+	providerConfig.Module, _, _ = unstructured.NestedString(probe.Object, "spec", "module")
+	if interval, found, _ := unstructured.NestedString(probe.Object, "spec", "interval"); found {
+		providerConfig.Interval = seconds(interval)
+	}
+	if scrapeTimeout, found, _ := unstructured.NestedString(probe.Object, "spec", "scrapeTimeout"); found {
+		providerConfig.ScrapeTimeout = seconds(scrapeTimeout)
+	}
+	if labels, found, _ := unstructured.NestedStringMap(probe.Object, "spec", "targets", "staticConfig", "labels"); found && len(labels) > 0 {
+		providerConfig.Labels = labels
+	}
+	monitor := models.NewMonitor(name, probe.GetName(), annotations[MonitorURLAnnotation], providerConfig)
+	return &monitor
+}
+
+// target returns the target of the blackbox_exporter, the URL of HTTP checks and the host and port of the others
+func target(m models.Monitor) string {
+	switch m.CheckType() {
+	case endpointmonitorv1alpha1.CheckTypeTCP, endpointmonitorv1alpha1.CheckTypeGRPC:
+		host, port := m.Target()
+		return net.JoinHostPort(host, strconv.Itoa(port))
+	case endpointmonitorv1alpha1.CheckTypeICMP:
+		host, _ := m.Target()
+		return host
+	}
+	return m.URL
+}
+
+// configOf returns the config of the monitor, an empty one when it has none
+func configOf(m models.Monitor) *endpointmonitorv1alpha1.BlackboxConfig {
+	if providerConfig, ok := m.Config.(*endpointmonitorv1alpha1.BlackboxConfig); ok && providerConfig != nil {
+		return providerConfig
+	}
+	return &endpointmonitorv1alpha1.BlackboxConfig{}
+}
+
+// duration formats seconds as a Prometheus duration
+func duration(seconds int) string {
+	return model.Duration(time.Duration(seconds) * time.Second).String()
+}
+
+// seconds parses a Prometheus duration into seconds, 0 when it is invalid
+func seconds(value string) int {
+	parsed, err := model.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return int(time.Duration(parsed).Seconds())
+}
+
+func stringMap(values map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		result[key] = value
+	}
+	return result
+}
+
+func copyMap(values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		result[key] = value
+	}
+	return result
+}
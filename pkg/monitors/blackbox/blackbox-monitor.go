@@ -0,0 +1,235 @@
+// Package blackbox adds Prometheus blackbox_exporter monitoring support in IngressMonitorController, the
+// monitors are prometheus-operator Probes scraping the blackbox_exporter
+package blackbox
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/kube"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+var log = logf.Log.WithName("blackbox-monitor")
+
+const (
+	// DefaultModule is the module of HTTP checks when the provider sets none
+	DefaultModule = "http_2xx"
+	// DefaultProberPath is the path of the probe endpoint of the blackbox_exporter
+	DefaultProberPath = "/probe"
+)
+
+// defaultModules holds the module of the check types other than HTTP, which the blackbox_exporter example
+// config names after their prober
+var defaultModules = map[endpointmonitorv1alpha1.CheckType]string{
+	endpointmonitorv1alpha1.CheckTypeTCP:  "tcp_connect",
+	endpointmonitorv1alpha1.CheckTypeICMP: "icmp",
+	endpointmonitorv1alpha1.CheckTypeGRPC: "grpc",
+}
+
+// BlackboxMonitorService manages the Probes of the monitors in the namespace of the provider, Prometheus
+// scrapes them through the blackbox_exporter
+type BlackboxMonitorService struct {
+	client     client.Client
+	namespace  string
+	httpModule string
+	labels     map[string]string
+	prober     map[string]interface{}
+}
+
+// Setup function is used to initialise the Blackbox service
+func (service *BlackboxMonitorService) Setup(p config.Provider) error {
+	blackboxConfig := p.BlackboxConfig
+	if len(blackboxConfig.ProberURL) == 0 {
+		return fmt.Errorf("blackboxConfig.proberURL is required for provider %s", p.Name)
+	}
+	proberURL, err := url.Parse(blackboxConfig.ProberURL)
+	if err != nil || len(proberURL.Host) == 0 {
+		return fmt.Errorf("invalid blackboxConfig.proberURL %q of provider %s", blackboxConfig.ProberURL, p.Name)
+	}
+	service.prober = map[string]interface{}{
+		"url":    proberURL.Host,
+		"scheme": proberURL.Scheme,
+		"path":   DefaultProberPath,
+	}
+	if len(strings.Trim(proberURL.Path, "/")) > 0 {
+		service.prober["path"] = proberURL.Path
+	}
+
+	service.httpModule = blackboxConfig.Module
+	if len(service.httpModule) == 0 {
+		service.httpModule = DefaultModule
+	}
+	service.labels = blackboxConfig.Labels
+	service.namespace = blackboxConfig.Namespace
+	if len(service.namespace) == 0 {
+		service.namespace = kube.GetCurrentKubernetesNamespace()
+	}
+	if len(service.namespace) == 0 {
+		return fmt.Errorf("blackboxConfig.namespace is required for provider %s outside the cluster", p.Name)
+	}
+
+	if service.client == nil {
+		restConfig, err := ctrlconfig.GetConfig()
+		if err != nil {
+			return err
+		}
+		if service.client, err = client.New(restConfig, client.Options{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckConnection verifies that Probes can be listed, which fails when prometheus-operator isn't installed
+func (service *BlackboxMonitorService) CheckConnection() error {
+	probes := &unstructured.UnstructuredList{}
+	probes.SetGroupVersionKind(ProbeGVK.GroupVersion().WithKind(ProbeGVK.Kind + "List"))
+	return service.client.List(context.TODO(), probes, client.InNamespace(service.namespace), client.Limit(1))
+}
+
+// SupportsCheckType returns whether the blackbox_exporter can run the check type, TCP, ICMP and GRPC checks are
+// supported besides HTTP
+func (service *BlackboxMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	_, ok := defaultModules[checkType]
+	return ok
+}
+
+// GetAll fetches the monitors of the Probes managed by the controller
+func (service *BlackboxMonitorService) GetAll() ([]models.Monitor, error) {
+	probes := &unstructured.UnstructuredList{}
+	probes.SetGroupVersionKind(ProbeGVK.GroupVersion().WithKind(ProbeGVK.Kind + "List"))
+	if err := service.client.List(context.TODO(), probes, client.InNamespace(service.namespace), client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return nil, err
+	}
+	monitors := []models.Monitor{}
+	for index := range probes.Items {
+		if monitor := ProbeToBaseMonitorMapper(&probes.Items[index]); monitor != nil {
+			monitors = append(monitors, *monitor)
+		}
+	}
+	return monitors, nil
+}
+
+// GetByName fetches the monitor with the name, nil is returned when there is none
+func (service *BlackboxMonitorService) GetByName(name string) (*models.Monitor, error) {
+	monitors, err := service.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, monitor := range monitors {
+		if monitor.Name == name {
+			return &monitor, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetByID fetches the monitor of the Probe with the name
+func (service *BlackboxMonitorService) GetByID(id string) (*models.Monitor, error) {
+	probe, err := service.getProbe(id)
+	if err != nil {
+		return nil, err
+	}
+	return ProbeToBaseMonitorMapper(probe), nil
+}
+
+// Add creates the Probe of the monitor
+func (service *BlackboxMonitorService) Add(m models.Monitor) {
+	probe := &unstructured.Unstructured{}
+	probe.SetGroupVersionKind(ProbeGVK)
+	probe.SetName(ProbeName(m.Name))
+	probe.SetNamespace(service.namespace)
+	service.setProbe(probe, m)
+	if err := service.client.Create(context.TODO(), probe); err != nil {
+		log.Error(err, "Monitor couldn't be added: "+m.Name)
+		return
+	}
+	log.Info("Monitor Added: " + m.Name)
+}
+
+// Update updates the Probe of the monitor
+func (service *BlackboxMonitorService) Update(m models.Monitor) {
+	probe, err := service.getProbe(m.ID)
+	if err == nil {
+		service.setProbe(probe, m)
+		err = service.client.Update(context.TODO(), probe)
+	}
+	if err != nil {
+		log.Error(err, "Monitor couldn't be updated: "+m.Name)
+		return
+	}
+	log.Info("Monitor Updated: " + m.Name)
+}
+
+// Remove deletes the Probe of the monitor
+func (service *BlackboxMonitorService) Remove(m models.Monitor) {
+	probe := &unstructured.Unstructured{}
+	probe.SetGroupVersionKind(ProbeGVK)
+	probe.SetName(m.ID)
+	probe.SetNamespace(service.namespace)
+	if err := service.client.Delete(context.TODO(), probe); err != nil {
+		log.Error(err, "Monitor couldn't be removed: "+m.Name)
+		return
+	}
+	log.Info("Monitor Removed: " + m.Name)
+}
+
+// Equal compares the specs of the Probes of the monitors
+func (service *BlackboxMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
+	return oldMonitor.URL == newMonitor.URL && reflect.DeepEqual(service.probeSpec(oldMonitor), service.probeSpec(newMonitor))
+}
+
+func (service *BlackboxMonitorService) getProbe(name string) (*unstructured.Unstructured, error) {
+	probe := &unstructured.Unstructured{}
+	probe.SetGroupVersionKind(ProbeGVK)
+	if err := service.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: service.namespace}, probe); err != nil {
+		return nil, err
+	}
+	return probe, nil
+}
+
+// setProbe sets the labels, annotations and spec of the Probe of the monitor
+func (service *BlackboxMonitorService) setProbe(probe *unstructured.Unstructured, m models.Monitor) {
+	labels := probe.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range service.labels {
+		labels[key] = value
+	}
+	labels[managedByLabel] = managedByValue
+	probe.SetLabels(labels)
+
+	annotations := probe.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[MonitorNameAnnotation] = m.Name
+	annotations[MonitorURLAnnotation] = m.URL
+	probe.SetAnnotations(annotations)
+
+	probe.Object["spec"] = service.probeSpec(m)
+}
+
+// module returns the module probing the endpoint of the monitor
+func (service *BlackboxMonitorService) module(m models.Monitor) string {
+	if module := configOf(m).Module; len(module) > 0 {
+		return module
+	}
+	if module, ok := defaultModules[m.CheckType()]; ok {
+		return module
+	}
+	return service.httpModule
+}
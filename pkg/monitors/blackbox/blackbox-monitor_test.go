@@ -0,0 +1,110 @@
+package blackbox
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+func newTestService(t *testing.T) *BlackboxMonitorService {
+	service := &BlackboxMonitorService{client: fake.NewClientBuilder().Build()}
+	assert.NilError(t, service.Setup(config.Provider{
+		Name: "Blackbox",
+		BlackboxConfig: config.Blackbox{
+			ProberURL: "http://blackbox-exporter.monitoring.svc:9115",
+			Namespace: "monitoring",
+			Labels:    map[string]string{"release": "prometheus"},
+		},
+	}))
+	return service
+}
+
+func TestBlackboxMonitorLifecycle(t *testing.T) {
+	service := newTestService(t)
+	service.Add(models.Monitor{
+		Name:   "shop-checkout",
+		URL:    "https://checkout.example.com",
+		Config: &endpointmonitorv1alpha1.BlackboxConfig{Interval: 30, Labels: map[string]string{"team": "shop"}},
+	})
+
+	monitor, err := service.GetByName("shop-checkout")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor != nil)
+	assert.Equal(t, monitor.ID, "shop-checkout")
+	assert.Equal(t, monitor.URL, "https://checkout.example.com")
+	assert.DeepEqual(t, monitor.Config, &endpointmonitorv1alpha1.BlackboxConfig{Module: DefaultModule, Interval: 30, Labels: map[string]string{"team": "shop"}})
+
+	probe, err := service.getProbe(monitor.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, probe.GetLabels()["release"], "prometheus")
+	prober, _, _ := unstructured.NestedStringMap(probe.Object, "spec", "prober")
+	assert.DeepEqual(t, prober, map[string]string{"url": "blackbox-exporter.monitoring.svc:9115", "scheme": "http", "path": DefaultProberPath})
+	jobName, _, _ := unstructured.NestedString(probe.Object, "spec", "jobName")
+	assert.Equal(t, jobName, "shop-checkout")
+	targets, _, _ := unstructured.NestedStringSlice(probe.Object, "spec", "targets", "staticConfig", "static")
+	assert.DeepEqual(t, targets, []string{"https://checkout.example.com"})
+
+	// The default module isn't a change
+	unchanged := models.Monitor{Name: "shop-checkout", URL: "https://checkout.example.com", Config: &endpointmonitorv1alpha1.BlackboxConfig{Interval: 30, Labels: map[string]string{"team": "shop"}}}
+	assert.Assert(t, service.Equal(*monitor, unchanged))
+
+	updated := *monitor
+	updated.Config = &endpointmonitorv1alpha1.BlackboxConfig{Module: "http_post_2xx", Interval: 60}
+	assert.Assert(t, !service.Equal(*monitor, updated))
+	service.Update(updated)
+	monitor, err = service.GetByID("shop-checkout")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, monitor.Config, &endpointmonitorv1alpha1.BlackboxConfig{Module: "http_post_2xx", Interval: 60})
+
+	service.Remove(*monitor)
+	monitors, err := service.GetAll()
+	assert.NilError(t, err)
+	assert.Equal(t, len(monitors), 0)
+}
+
+func TestBlackboxTargets(t *testing.T) {
+	service := newTestService(t)
+	tests := []struct {
+		url    string
+		target string
+		module string
+	}{
+		{"https://checkout.example.com/healthz", "https://checkout.example.com/healthz", DefaultModule},
+		{"tcp://db.example.com:5432", "db.example.com:5432", "tcp_connect"},
+		{"icmp://gateway.example.com", "gateway.example.com", "icmp"},
+		{"grpc://api.example.com:443", "api.example.com:443", "grpc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			monitor := models.Monitor{Name: "target", URL: tt.url}
+			assert.Equal(t, target(monitor), tt.target)
+			assert.Equal(t, service.module(monitor), tt.module)
+		})
+	}
+}
+
+func TestProbeName(t *testing.T) {
+	assert.Equal(t, ProbeName("shop-checkout"), "shop-checkout")
+	assert.Assert(t, ProbeName("Shop Checkout") != ProbeName("shop-checkout"))
+	assert.Assert(t, len(ProbeName("Shop Checkout")) > len("shop-checkout"))
+	long := ProbeName("a-very-long-monitor-name-that-does-not-fit-into-the-name-of-a-probe-object")
+	assert.Assert(t, len(long) <= maxProbeNameLength)
+}
+
+func TestSetupRequiresProberURL(t *testing.T) {
+	service := &BlackboxMonitorService{client: fake.NewClientBuilder().Build()}
+	err := service.Setup(config.Provider{Name: "Blackbox", BlackboxConfig: config.Blackbox{Namespace: "monitoring"}})
+	assert.ErrorContains(t, err, "proberURL is required")
+}
+
+func TestTranslateCheck(t *testing.T) {
+	providerConfig, unsupported := TranslateCheck(&endpointmonitorv1alpha1.Check{Interval: 30, Timeout: 5, Method: "POST"})
+	assert.DeepEqual(t, providerConfig, &endpointmonitorv1alpha1.BlackboxConfig{Interval: 30, ScrapeTimeout: 5})
+	assert.DeepEqual(t, unsupported, []string{"method"})
+}
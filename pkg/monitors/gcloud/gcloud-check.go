@@ -0,0 +1,11 @@
+package gcloud
+
+import (
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// TranslateCheck translates a provider agnostic check into a Google Cloud config. The uptime checks
+// are created with the defaults of Google Cloud, so none of the fields of the check can be expressed.
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.GCloudConfig, []string) {
+	return nil, check.SetFields()
+}
@@ -33,17 +33,22 @@ func (monitor *MonitorService) Equal(oldMonitor models.Monitor, newMonitor model
 	return false
 }
 
-func (service *MonitorService) Setup(provider config.Provider) {
+// SupportsCheckType returns whether Cloud Monitoring can run the check type, only HTTP and TCP
+// uptime checks exist
+func (service *MonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType == endpointmonitorv1alpha1.CheckTypeHTTP || checkType == endpointmonitorv1alpha1.CheckTypeTCP
+}
+
+func (service *MonitorService) Setup(provider config.Provider) error {
 	service.ctx = context.Background()
+	service.projectID = provider.GcloudConfig.ProjectID
 
 	client, err := monitoring.NewUptimeCheckClient(service.ctx, option.WithCredentialsJSON([]byte(provider.ApiKey)))
-
 	if err != nil {
-		log.Info("Error Seting Up Monitor Service: " + err.Error())
-	} else {
-		service.client = client
+		return fmt.Errorf("error setting up uptime check client: %w", err)
 	}
-	service.projectID = provider.GcloudConfig.ProjectID
+	service.client = client
+	return nil
 }
 
 func (service *MonitorService) GetByName(name string) (monitor *models.Monitor, err error) {
@@ -68,6 +73,18 @@ func (service *MonitorService) GetByName(name string) (monitor *models.Monitor,
 	return nil, nil
 }
 
+// CheckConnection verifies the credentials by listing a single uptime check
+func (service *MonitorService) CheckConnection() error {
+	uptimeCheckConfigsIterator := service.client.ListUptimeCheckConfigs(service.ctx, &monitoringpb.ListUptimeCheckConfigsRequest{
+		Parent:   "projects/" + service.projectID,
+		PageSize: 1,
+	})
+	if _, err := uptimeCheckConfigsIterator.Next(); err != nil && !errors.Is(err, iterator.Done) {
+		return fmt.Errorf("error received while listing checks: %w", err)
+	}
+	return nil
+}
+
 func (service *MonitorService) GetAll() ([]models.Monitor, error) {
 	uptimeCheckConfigsIterator := service.client.ListUptimeCheckConfigs(service.ctx, &monitoringpb.ListUptimeCheckConfigsRequest{
 		Parent: "projects/" + service.projectID,
@@ -121,26 +138,35 @@ func (service *MonitorService) Add(monitor models.Monitor) {
 		projectID = providerConfig.ProjectId
 	}
 
-	_, err = service.client.CreateUptimeCheckConfig(service.ctx, &monitoringpb.CreateUptimeCheckConfigRequest{
-		Parent: "projects/" + projectID,
-		UptimeCheckConfig: &monitoringpb.UptimeCheckConfig{
-			DisplayName: monitor.Name,
-			Resource: &monitoringpb.UptimeCheckConfig_MonitoredResource{
-				MonitoredResource: &monitoredres.MonitoredResource{
-					Type: "uptime_url",
-					Labels: map[string]string{
-						"host": url.Hostname(),
-					},
+	uptimeCheckConfig := &monitoringpb.UptimeCheckConfig{
+		DisplayName: monitor.Name,
+		Resource: &monitoringpb.UptimeCheckConfig_MonitoredResource{
+			MonitoredResource: &monitoredres.MonitoredResource{
+				Type: "uptime_url",
+				Labels: map[string]string{
+					"host": url.Hostname(),
 				},
 			},
-			CheckRequestType: &monitoringpb.UptimeCheckConfig_HttpCheck_{
-				HttpCheck: &monitoringpb.UptimeCheckConfig_HttpCheck{
-					Path:   url.Path,
-					Port:   int32(port),
-					UseSsl: url.Scheme == "https",
-				},
+		},
+		CheckRequestType: &monitoringpb.UptimeCheckConfig_HttpCheck_{
+			HttpCheck: &monitoringpb.UptimeCheckConfig_HttpCheck{
+				Path:   url.Path,
+				Port:   int32(port),
+				UseSsl: url.Scheme == "https",
 			},
 		},
+	}
+	if monitor.CheckType() == endpointmonitorv1alpha1.CheckTypeTCP {
+		uptimeCheckConfig.CheckRequestType = &monitoringpb.UptimeCheckConfig_TcpCheck_{
+			TcpCheck: &monitoringpb.UptimeCheckConfig_TcpCheck{
+				Port: int32(port),
+			},
+		}
+	}
+
+	_, err = service.client.CreateUptimeCheckConfig(service.ctx, &monitoringpb.CreateUptimeCheckConfigRequest{
+		Parent:            "projects/" + projectID,
+		UptimeCheckConfig: uptimeCheckConfig,
 	})
 	if err != nil {
 		log.Info("Error Adding Monitor: " + err.Error())
@@ -187,8 +213,12 @@ func (service *MonitorService) Update(monitor models.Monitor) {
 	}
 
 	uptimeCheckConfig.DisplayName = monitor.Name
-	uptimeCheckConfig.GetHttpCheck().Port = int32(port)
-	uptimeCheckConfig.GetHttpCheck().Path = url.Path
+	if tcpCheck := uptimeCheckConfig.GetTcpCheck(); tcpCheck != nil {
+		tcpCheck.Port = int32(port)
+	} else {
+		uptimeCheckConfig.GetHttpCheck().Port = int32(port)
+		uptimeCheckConfig.GetHttpCheck().Path = url.Path
+	}
 
 	uptimeCheckConfig, err = service.client.UpdateUptimeCheckConfig(service.ctx, &monitoringpb.UpdateUptimeCheckConfigRequest{
 		UptimeCheckConfig: uptimeCheckConfig,
@@ -218,6 +248,14 @@ func transformToMonitor(uptimeCheckConfig *monitoringpb.UptimeCheckConfig) (moni
 	port := uptimeCheckConfig.GetHttpCheck().Port
 	host := uptimeCheckConfig.GetMonitoredResource().Labels["host"]
 
+	if tcpCheck := uptimeCheckConfig.GetTcpCheck(); tcpCheck != nil {
+		return models.Monitor{
+			URL:  models.TargetURL(endpointmonitorv1alpha1.CheckTypeTCP, host, int(tcpCheck.Port)),
+			Name: uptimeCheckConfig.DisplayName,
+			ID:   uptimeCheckConfig.Name,
+		}
+	}
+
 	var scheme string
 	if isSsl {
 		scheme = "https"
@@ -0,0 +1,29 @@
+package grafana
+
+import (
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// TranslateCheck translates a provider agnostic check into a Grafana config, along with the fields
+// of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.GrafanaConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.GrafanaConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			providerConfig.Frequency = int64(check.Interval) * 1000
+		case "locations":
+			providerConfig.Probes = check.Locations
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
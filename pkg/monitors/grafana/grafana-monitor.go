@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/synthetic-monitoring-agent/pkg/pb/synthetic_monitoring"
 	smapi "github.com/grafana/synthetic-monitoring-api-go-client"
@@ -20,6 +21,8 @@ var log = logf.Log.WithName("grafana-monitor")
 const (
 	// Default value for monitor configuration
 	FrequencyDefaultValue = 10000
+	// Resolver queried by DNS checks
+	defaultDNSServer = "dns.google"
 )
 
 type GrafanaMonitorService struct {
@@ -44,7 +47,15 @@ func getID(monitor models.Monitor) (int64, error) {
 	return checkId, nil
 }
 
-func (service *GrafanaMonitorService) Setup(provider config.Provider) {
+// CheckConnection verifies the API key by fetching the tenant
+func (service *GrafanaMonitorService) CheckConnection() error {
+	if _, err := service.smClient.GetTenant(service.ctx); err != nil {
+		return fmt.Errorf("failed to get Synthetic Monitoring tenant: %w", err)
+	}
+	return nil
+}
+
+func (service *GrafanaMonitorService) Setup(provider config.Provider) error {
 	service.ctx = context.Background()
 	service.apiKey = provider.ApiKey
 	service.client = http.Client{}
@@ -52,8 +63,7 @@ func (service *GrafanaMonitorService) Setup(provider config.Provider) {
 	client := smapi.NewClient(service.baseURL, service.apiKey, http.DefaultClient)
 	tenant, err := client.GetTenant(service.ctx)
 	if err != nil {
-		log.Error(err, "Failed to initialize Synthetic Monitoring client")
-		return
+		return fmt.Errorf("failed to initialize Synthetic Monitoring client: %w", err)
 	}
 	service.smClient = client
 	service.tenant = tenant
@@ -63,6 +73,7 @@ func (service *GrafanaMonitorService) Setup(provider config.Provider) {
 	} else {
 		service.frequency = FrequencyDefaultValue
 	}
+	return nil
 }
 
 func (service *GrafanaMonitorService) CreateSyntheticCheck(monitor models.Monitor, tenantID int64) (*synthetic_monitoring.Check, error) {
@@ -108,26 +119,95 @@ func (service *GrafanaMonitorService) CreateSyntheticCheck(monitor models.Monito
 	if err != nil {
 		return nil, fmt.Errorf("Error converting ID %v %v", monitor.ID, err)
 	}
+	target, settings := checkTargetAndSettings(monitor)
 	// Creating a new Check object
 	return &synthetic_monitoring.Check{
-		Id:        checkId,
-		Target:    monitor.URL,
-		Job:       monitor.Name,
-		Frequency: frequency,
-		TenantId:  tenantID,
-		Timeout:   2000,
-		Enabled:   true,
-		Probes:    probeIDs,
-		Settings: synthetic_monitoring.CheckSettings{
-			Http: &synthetic_monitoring.HttpSettings{
-				IpVersion: synthetic_monitoring.IpVersion_V4,
-			},
-		},
+		Id:               checkId,
+		Target:           target,
+		Job:              monitor.Name,
+		Frequency:        frequency,
+		TenantId:         tenantID,
+		Timeout:          2000,
+		Enabled:          true,
+		Probes:           probeIDs,
+		Settings:         settings,
 		BasicMetricsOnly: true,
 		AlertSensitivity: alertSensitivity,
 	}, nil
 }
 
+// checkTargetAndSettings returns the target and settings of the check for the check type of the monitor,
+// TCP and gRPC checks target host:port while ping and DNS checks target the host
+func checkTargetAndSettings(monitor models.Monitor) (string, synthetic_monitoring.CheckSettings) {
+	_, target, _ := strings.Cut(monitor.URL, "://")
+	switch monitor.CheckType() {
+	case endpointmonitorv1alpha1.CheckTypeTCP:
+		return target, synthetic_monitoring.CheckSettings{
+			Tcp: &synthetic_monitoring.TcpSettings{IpVersion: synthetic_monitoring.IpVersion_V4},
+		}
+	case endpointmonitorv1alpha1.CheckTypeICMP:
+		return target, synthetic_monitoring.CheckSettings{
+			Ping: &synthetic_monitoring.PingSettings{IpVersion: synthetic_monitoring.IpVersion_V4, PacketCount: 1},
+		}
+	case endpointmonitorv1alpha1.CheckTypeGRPC:
+		return target, synthetic_monitoring.CheckSettings{Grpc: grpcSettings(monitor)}
+	case endpointmonitorv1alpha1.CheckTypeDNS:
+		return target, synthetic_monitoring.CheckSettings{
+			Dns: &synthetic_monitoring.DnsSettings{
+				IpVersion:   synthetic_monitoring.IpVersion_V4,
+				Server:      defaultDNSServer,
+				Port:        53,
+				RecordType:  synthetic_monitoring.DnsRecordType_A,
+				Protocol:    synthetic_monitoring.DnsProtocol_UDP,
+				ValidRCodes: []string{"NOERROR"},
+			},
+		}
+	}
+	return monitor.URL, synthetic_monitoring.CheckSettings{
+		Http: &synthetic_monitoring.HttpSettings{
+			IpVersion: synthetic_monitoring.IpVersion_V4,
+		},
+	}
+}
+
+// grpcSettings translates the gRPC options of the monitor, metadata can't be sent by Grafana checks
+func grpcSettings(monitor models.Monitor) *synthetic_monitoring.GrpcSettings {
+	_, port := monitor.Target()
+	settings := &synthetic_monitoring.GrpcSettings{
+		IpVersion: synthetic_monitoring.IpVersion_V4,
+		Tls:       monitor.GRPC.UsesTLS(port),
+	}
+	if monitor.GRPC != nil {
+		settings.Service = monitor.GRPC.Service
+		if monitor.GRPC.InsecureSkipVerify {
+			settings.TlsConfig = &synthetic_monitoring.TLSConfig{InsecureSkipVerify: true}
+		}
+	}
+	return settings
+}
+
+// checkURL returns the monitor URL of a check, the target of TCP, ping, DNS and gRPC checks is prefixed
+// with the scheme of their check type
+func checkURL(check synthetic_monitoring.Check) string {
+	switch {
+	case check.Settings.Tcp != nil:
+		return "tcp://" + check.Target
+	case check.Settings.Ping != nil:
+		return "icmp://" + check.Target
+	case check.Settings.Dns != nil:
+		return "dns://" + check.Target
+	case check.Settings.Grpc != nil:
+		return "grpc://" + check.Target
+	}
+	return check.Target
+}
+
+// SupportsCheckType returns whether Grafana can run the check type, all check types are supported except
+// heartbeats which Synthetic Monitoring can't receive
+func (service *GrafanaMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType != endpointmonitorv1alpha1.CheckTypeHeartbeat
+}
+
 // Add adds a new monitor to Grafana Synthetic Monitoring service
 func (service *GrafanaMonitorService) Add(monitor models.Monitor) {
 	var tenantID int64
@@ -193,9 +273,18 @@ func (service *GrafanaMonitorService) GetAll() ([]models.Monitor, error) {
 				}
 			}
 		}
+		var grpcCheck *endpointmonitorv1alpha1.GRPCCheck
+		if grpc := check.Settings.Grpc; grpc != nil {
+			tls := grpc.Tls
+			grpcCheck = &endpointmonitorv1alpha1.GRPCCheck{Service: grpc.Service, TLS: &tls}
+			if grpc.TlsConfig != nil {
+				grpcCheck.InsecureSkipVerify = grpc.TlsConfig.InsecureSkipVerify
+			}
+		}
 		monitors = append(monitors, models.Monitor{
 			Name: check.Job,
-			URL:  check.Target,
+			URL:  checkURL(check),
+			GRPC: grpcCheck,
 			ID:   fmt.Sprintf("%v", check.Id),
 			Config: &endpointmonitorv1alpha1.GrafanaConfig{
 				TenantId:         check.TenantId,
@@ -237,5 +326,8 @@ func (service *GrafanaMonitorService) Remove(monitor models.Monitor) {
 }
 
 func (service *GrafanaMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
+	if oldMonitor.CheckType() == endpointmonitorv1alpha1.CheckTypeGRPC && !reflect.DeepEqual(grpcSettings(oldMonitor), grpcSettings(newMonitor)) {
+		return false
+	}
 	return oldMonitor.Name == newMonitor.Name && oldMonitor.URL == newMonitor.URL && oldMonitor.ID == newMonitor.ID && reflect.DeepEqual(oldMonitor.Config, newMonitor.Config)
 }
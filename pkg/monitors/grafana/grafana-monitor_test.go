@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/grafana/synthetic-monitoring-agent/pkg/pb/synthetic_monitoring"
+
 	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
 	"github.com/stakater/IngressMonitorController/v2/pkg/config"
 	"github.com/stakater/IngressMonitorController/v2/pkg/models"
@@ -194,3 +196,40 @@ func TestEqualModules(t *testing.T) {
 		t.Error("Monitor should not be the same", m1, m4)
 	}
 }
+
+func TestCheckTargetAndSettingsForCheckTypes(t *testing.T) {
+	target, settings := checkTargetAndSettings(models.Monitor{URL: "tcp://db.example.com:5432"})
+	if target != "db.example.com:5432" || settings.Tcp == nil {
+		t.Error("TCP monitor should target host:port with tcp settings", target, settings)
+	}
+	check := synthetic_monitoring.Check{Target: target, Settings: settings}
+	if checkURL(check) != "tcp://db.example.com:5432" {
+		t.Error("TCP check should map back to its monitor url", checkURL(check))
+	}
+
+	target, settings = checkTargetAndSettings(models.Monitor{URL: "icmp://10.0.0.1"})
+	if target != "10.0.0.1" || settings.Ping == nil {
+		t.Error("ICMP monitor should target the host with ping settings", target, settings)
+	}
+
+	target, settings = checkTargetAndSettings(models.Monitor{URL: "dns://example.com"})
+	if target != "example.com" || settings.Dns == nil || settings.Dns.Server != defaultDNSServer {
+		t.Error("DNS monitor should resolve the host with dns settings", target, settings)
+	}
+
+	grpcMonitor := models.Monitor{URL: "grpc://api.example.com:443", GRPC: &endpointmonitorv1alpha1.GRPCCheck{Service: "checkout.v1.Checkout"}}
+	target, settings = checkTargetAndSettings(grpcMonitor)
+	if target != "api.example.com:443" || settings.Grpc == nil || settings.Grpc.Service != "checkout.v1.Checkout" || !settings.Grpc.Tls {
+		t.Error("gRPC monitor should target host:port with TLS on port 443", target, settings)
+	}
+	plaintext := false
+	recorded := models.Monitor{URL: "grpc://api.example.com:443", GRPC: &endpointmonitorv1alpha1.GRPCCheck{Service: "checkout.v1.Checkout", TLS: &plaintext}}
+	if (&GrafanaMonitorService{}).Equal(recorded, grpcMonitor) {
+		t.Error("gRPC monitors with different TLS settings should not be the same")
+	}
+
+	target, settings = checkTargetAndSettings(models.Monitor{URL: "https://example.com/health"})
+	if target != "https://example.com/health" || settings.Http == nil {
+		t.Error("HTTP monitor should target the url with http settings", target, settings)
+	}
+}
@@ -0,0 +1,68 @@
+package healthchecks
+
+import (
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+// HealthchecksCheckToBaseMonitorMapper maps a Healthchecks.io check to a Monitor, the URL of the
+// monitor is the ping URL of the check
+func HealthchecksCheckToBaseMonitorMapper(check HealthchecksCheck) *models.Monitor {
+	var m models.Monitor
+	m.Name = check.Name
+	m.ID = check.UUID
+	m.URL = check.PingURL
+	m.Heartbeat = &models.Heartbeat{
+		Schedule: check.Schedule,
+		TimeZone: check.Tz,
+		Period:   check.Timeout,
+		Grace:    check.Grace,
+	}
+	m.Config = &endpointmonitorv1alpha1.HealthchecksConfig{
+		Channels:    check.Channels,
+		Tags:        check.Tags,
+		Description: check.Desc,
+		Grace:       check.Grace,
+	}
+	return &m
+}
+
+// HealthchecksChecksToBaseMonitorsMapper maps Healthchecks.io checks to Monitors
+func HealthchecksChecksToBaseMonitorsMapper(checks []HealthchecksCheck) []models.Monitor {
+	var monitors []models.Monitor
+	for _, check := range checks {
+		monitors = append(monitors, *HealthchecksCheckToBaseMonitorMapper(check))
+	}
+	return monitors
+}
+
+// BaseMonitorToHealthchecksCheckRequestMapper maps a Monitor to the body of the create and update
+// check APIs. Checks follow the cron schedule of the job, or expect a ping every period without one.
+func BaseMonitorToHealthchecksCheckRequestMapper(m models.Monitor) HealthchecksCheckRequest {
+	request := HealthchecksCheckRequest{Name: m.Name}
+
+	providerConfig, _ := m.Config.(*endpointmonitorv1alpha1.HealthchecksConfig)
+	if providerConfig != nil {
+		request.Tags = providerConfig.Tags
+		request.Desc = providerConfig.Description
+		request.Channels = providerConfig.Channels
+		request.Grace = providerConfig.Grace
+	}
+
+	if m.Heartbeat != nil {
+		if len(m.Heartbeat.Schedule) > 0 {
+			request.Schedule = m.Heartbeat.Schedule
+			request.Tz = m.Heartbeat.TimeZone
+			if len(request.Tz) == 0 {
+				request.Tz = "UTC"
+			}
+		} else {
+			request.Timeout = max(m.Heartbeat.Period, minGrace)
+		}
+		if request.Grace == 0 {
+			request.Grace = m.Heartbeat.Grace
+		}
+	}
+	request.Grace = max(request.Grace, minGrace)
+	return request
+}
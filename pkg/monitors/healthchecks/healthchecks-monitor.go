@@ -0,0 +1,203 @@
+// Package healthchecks adds Healthchecks.io heartbeat monitoring support in IngressMonitorController
+package healthchecks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+var log = logf.Log.WithName("healthchecks-monitor")
+
+const (
+	// DefaultAPIURL is the URL of the hosted Healthchecks.io, self-hosted instances set apiURL
+	DefaultAPIURL = "https://healthchecks.io"
+	// TimeoutDefaultValue is the timeout in seconds of a request to the API
+	TimeoutDefaultValue = 30
+
+	// minGrace is the shortest grace period and timeout in seconds accepted by Healthchecks.io
+	minGrace = 60
+)
+
+// HealthchecksMonitorService manages the checks of a Healthchecks.io project, which are pinged by jobs
+// instead of checking a URL
+type HealthchecksMonitorService struct {
+	apiKey string
+	url    string
+	client *http.Client
+}
+
+// Setup function is used to initialise the Healthchecks.io service
+func (service *HealthchecksMonitorService) Setup(p config.Provider) error {
+	service.apiKey = p.ApiKey
+	service.url = strings.TrimSuffix(p.ApiURL, "/")
+	if len(service.url) == 0 {
+		service.url = DefaultAPIURL
+	}
+	service.client = &http.Client{Timeout: TimeoutDefaultValue * time.Second}
+
+	if len(service.apiKey) == 0 {
+		return fmt.Errorf("apiKey is required for provider %s", p.Name)
+	}
+	return nil
+}
+
+// CheckConnection verifies the API key by listing the checks
+func (service *HealthchecksMonitorService) CheckConnection() error {
+	_, err := service.GetAll()
+	return err
+}
+
+// SupportsCheckType returns whether Healthchecks.io can run the check type, only heartbeats are supported
+func (service *HealthchecksMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType == endpointmonitorv1alpha1.CheckTypeHeartbeat
+}
+
+// MonitorLinks links to the details page of the check
+func (service *HealthchecksMonitorService) MonitorLinks(m models.Monitor) (models.MonitorLinks, error) {
+	return models.MonitorLinks{DashboardURL: service.url + "/checks/" + m.ID + "/details/"}, nil
+}
+
+// GetAll fetches all checks of the project
+func (service *HealthchecksMonitorService) GetAll() ([]models.Monitor, error) {
+	body, err := service.doRequest(http.MethodGet, "/api/v3/checks/", nil)
+	if err != nil {
+		return nil, err
+	}
+	var checks HealthchecksCheckList
+	if err := json.Unmarshal(body, &checks); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal healthchecks list response: %w", err)
+	}
+	return HealthchecksChecksToBaseMonitorsMapper(checks.Checks), nil
+}
+
+// GetByName function will Get a monitor by it's name
+func (service *HealthchecksMonitorService) GetByName(name string) (*models.Monitor, error) {
+	monitors, err := service.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, monitor := range monitors {
+		if monitor.Name == name {
+			return &monitor, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetByID fetches a single check by its UUID
+func (service *HealthchecksMonitorService) GetByID(id string) (*models.Monitor, error) {
+	body, err := service.doRequest(http.MethodGet, "/api/v3/checks/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	var check HealthchecksCheck
+	if err := json.Unmarshal(body, &check); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal healthchecks check response: %w", err)
+	}
+	return HealthchecksCheckToBaseMonitorMapper(check), nil
+}
+
+// Add will create a new check
+func (service *HealthchecksMonitorService) Add(m models.Monitor) {
+	if _, err := service.doRequest(http.MethodPost, "/api/v3/checks/", BaseMonitorToHealthchecksCheckRequestMapper(m)); err != nil {
+		log.Error(err, "Monitor couldn't be added: "+m.Name)
+		return
+	}
+	log.Info("Monitor Added: " + m.Name)
+}
+
+// Update will update an existing check
+func (service *HealthchecksMonitorService) Update(m models.Monitor) {
+	if _, err := service.doRequest(http.MethodPost, "/api/v3/checks/"+m.ID, BaseMonitorToHealthchecksCheckRequestMapper(m)); err != nil {
+		log.Error(err, "Monitor couldn't be updated: "+m.Name)
+		return
+	}
+	log.Info("Monitor Updated: " + m.Name)
+}
+
+// Remove will delete an existing check
+func (service *HealthchecksMonitorService) Remove(m models.Monitor) {
+	if _, err := service.doRequest(http.MethodDelete, "/api/v3/checks/"+m.ID, nil); err != nil {
+		log.Error(err, "Monitor couldn't be removed: "+m.Name)
+		return
+	}
+	log.Info("Monitor Removed: " + m.Name)
+}
+
+// Pause pauses the check until it receives the next ping
+func (service *HealthchecksMonitorService) Pause(m models.Monitor) error {
+	if _, err := service.doRequest(http.MethodPost, "/api/v3/checks/"+m.ID+"/pause", nil); err != nil {
+		return err
+	}
+	log.Info("Monitor Paused: " + m.Name)
+	return nil
+}
+
+// Equal compares the fields of the checks sent to the API. The channels are only compared when they are
+// set to a list of IDs, since the API returns the IDs of the channels selected by "*".
+func (service *HealthchecksMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
+	oldCheck := BaseMonitorToHealthchecksCheckRequestMapper(oldMonitor)
+	newCheck := BaseMonitorToHealthchecksCheckRequestMapper(newMonitor)
+	if len(newCheck.Channels) == 0 || newCheck.Channels == "*" {
+		oldCheck.Channels = newCheck.Channels
+	} else {
+		oldCheck.Channels = sortedList(oldCheck.Channels)
+		newCheck.Channels = sortedList(newCheck.Channels)
+	}
+	return oldCheck == newCheck
+}
+
+func sortedList(list string) string {
+	values := strings.Split(list, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	slices.Sort(values)
+	return strings.Join(values, ",")
+}
+
+// doRequest sends a request to the API and returns the body of a successful response
+func (service *HealthchecksMonitorService) doRequest(method string, path string, payload interface{}) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, service.url+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Api-Key", service.apiKey)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := service.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("healthchecks %s %s returned status %d: %s", method, path, resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
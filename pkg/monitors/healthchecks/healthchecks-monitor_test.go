@@ -0,0 +1,190 @@
+package healthchecks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+func init() {
+	// To allow normal logging to be printed if tests fails
+	// Dev mode is an extra feature to make output more readable
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+}
+
+const testAPIKey = "project-key"
+
+// fakeHealthchecks is an in-memory Healthchecks.io project
+type fakeHealthchecks struct {
+	mu     sync.Mutex
+	checks map[string]HealthchecksCheck
+	paused []string
+}
+
+func (f *fakeHealthchecks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("X-Api-Key") != testAPIKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3/checks/")
+	uuid, action, _ := strings.Cut(path, "/")
+	body, _ := io.ReadAll(r.Body)
+	switch {
+	case r.Method == http.MethodGet && uuid == "":
+		list := HealthchecksCheckList{Checks: []HealthchecksCheck{}}
+		for _, check := range f.checks {
+			list.Checks = append(list.Checks, check)
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodGet:
+		check, ok := f.checks[uuid]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(check)
+	case r.Method == http.MethodPost && action == "pause":
+		f.paused = append(f.paused, uuid)
+	case r.Method == http.MethodPost:
+		var request HealthchecksCheckRequest
+		_ = json.Unmarshal(body, &request)
+		if uuid == "" {
+			uuid = "uuid-" + request.Name
+			w.WriteHeader(http.StatusCreated)
+		} else if _, ok := f.checks[uuid]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.checks[uuid] = HealthchecksCheck{
+			UUID: uuid, Name: request.Name, Tags: request.Tags, Desc: request.Desc, Grace: request.Grace,
+			Timeout: request.Timeout, Schedule: request.Schedule, Tz: request.Tz, Channels: request.Channels,
+			PingURL: "https://hc-ping.com/" + uuid,
+		}
+		_ = json.NewEncoder(w).Encode(f.checks[uuid])
+	case r.Method == http.MethodDelete:
+		delete(f.checks, uuid)
+	}
+}
+
+func setupTestService(t *testing.T) (*HealthchecksMonitorService, *fakeHealthchecks) {
+	fake := &fakeHealthchecks{checks: map[string]HealthchecksCheck{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	service := &HealthchecksMonitorService{}
+	assert.NilError(t, service.Setup(config.Provider{Name: "Healthchecks", ApiKey: testAPIKey, ApiURL: server.URL + "/"}))
+	return service, fake
+}
+
+func TestSetupRequiresAPIKey(t *testing.T) {
+	service := &HealthchecksMonitorService{}
+	if err := service.Setup(config.Provider{Name: "Healthchecks"}); err == nil {
+		t.Error("Expected an error without an apiKey")
+	}
+	if service.url != DefaultAPIURL {
+		t.Errorf("Expected the hosted API, got %s", service.url)
+	}
+}
+
+func TestAddUpdateRemoveHeartbeatCheck(t *testing.T) {
+	service, fake := setupTestService(t)
+	assert.NilError(t, service.CheckConnection())
+
+	m := models.Monitor{
+		Name:      "nightly-backup",
+		Heartbeat: &models.Heartbeat{Schedule: "0 2 * * *", TimeZone: "Europe/Berlin", Period: 86400, Grace: 300},
+		Config:    &endpointmonitorv1alpha1.HealthchecksConfig{Tags: "backup prod", Channels: "*"},
+	}
+	service.Add(m)
+
+	created, err := service.GetByName("nightly-backup")
+	assert.NilError(t, err)
+	assert.Assert(t, created != nil)
+	assert.Equal(t, created.URL, "https://hc-ping.com/uuid-nightly-backup")
+	assert.Equal(t, created.Heartbeat.Schedule, "0 2 * * *")
+	assert.Equal(t, created.Heartbeat.TimeZone, "Europe/Berlin")
+	assert.Equal(t, created.Heartbeat.Grace, 300)
+	assert.Assert(t, service.Equal(*created, m))
+
+	// The schedule of the CronJob changed
+	m.ID = created.ID
+	m.Heartbeat.Schedule = "0 3 * * *"
+	assert.Assert(t, !service.Equal(*created, m))
+	service.Update(m)
+	updated, err := service.GetByID(created.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, updated.Heartbeat.Schedule, "0 3 * * *")
+
+	assert.NilError(t, service.Pause(*updated))
+	assert.DeepEqual(t, fake.paused, []string{created.ID})
+
+	service.Remove(*updated)
+	removed, err := service.GetByName("nightly-backup")
+	assert.NilError(t, err)
+	assert.Assert(t, removed == nil)
+}
+
+func TestBaseMonitorToHealthchecksCheckRequestMapper(t *testing.T) {
+	// Without a schedule the check expects a ping every period
+	request := BaseMonitorToHealthchecksCheckRequestMapper(models.Monitor{
+		Name:      "sync",
+		Heartbeat: &models.Heartbeat{Period: 30, Grace: 10},
+	})
+	if request.Schedule != "" || request.Timeout != minGrace || request.Grace != minGrace {
+		t.Errorf("Expected a simple check with minimum timeout and grace, got %+v", request)
+	}
+
+	request = BaseMonitorToHealthchecksCheckRequestMapper(models.Monitor{
+		Name:      "report",
+		Heartbeat: &models.Heartbeat{Schedule: "*/15 * * * *", Period: 900, Grace: 300},
+		Config:    &endpointmonitorv1alpha1.HealthchecksConfig{Grace: 600},
+	})
+	if request.Schedule != "*/15 * * * *" || request.Tz != "UTC" || request.Timeout != 0 || request.Grace != 600 {
+		t.Errorf("Expected a cron check in UTC with the configured grace, got %+v", request)
+	}
+}
+
+func TestEqualComparesChannelIDsOnlyWhenListed(t *testing.T) {
+	service := &HealthchecksMonitorService{}
+	old := models.Monitor{
+		Name:      "sync",
+		Heartbeat: &models.Heartbeat{Period: 300},
+		Config:    &endpointmonitorv1alpha1.HealthchecksConfig{Channels: "b, a"},
+	}
+	m := old
+	m.Config = &endpointmonitorv1alpha1.HealthchecksConfig{Channels: "*"}
+	if !service.Equal(old, m) {
+		t.Error("Expected all channels to match the channel IDs returned by the API")
+	}
+	m.Config = &endpointmonitorv1alpha1.HealthchecksConfig{Channels: "a,b"}
+	if !service.Equal(old, m) {
+		t.Error("Expected the channel order to be ignored")
+	}
+	m.Config = &endpointmonitorv1alpha1.HealthchecksConfig{Channels: "a"}
+	if service.Equal(old, m) {
+		t.Error("Expected a removed channel to need an update")
+	}
+}
+
+func TestSupportsOnlyHeartbeats(t *testing.T) {
+	service := &HealthchecksMonitorService{}
+	if service.SupportsCheckType(endpointmonitorv1alpha1.CheckTypeHTTP) || !service.SupportsCheckType(endpointmonitorv1alpha1.CheckTypeHeartbeat) {
+		t.Error("Expected Healthchecks.io to only support heartbeats")
+	}
+}
@@ -0,0 +1,33 @@
+package healthchecks
+
+// HealthchecksCheck is a check of the Healthchecks.io Management API v3
+type HealthchecksCheck struct {
+	UUID     string `json:"uuid"`
+	Name     string `json:"name"`
+	Tags     string `json:"tags"`
+	Desc     string `json:"desc"`
+	Grace    int    `json:"grace"`
+	Timeout  int    `json:"timeout,omitempty"`
+	Schedule string `json:"schedule,omitempty"`
+	Tz       string `json:"tz,omitempty"`
+	Channels string `json:"channels,omitempty"`
+	Status   string `json:"status,omitempty"`
+	PingURL  string `json:"ping_url,omitempty"`
+}
+
+// HealthchecksCheckList is the response of the list checks API
+type HealthchecksCheckList struct {
+	Checks []HealthchecksCheck `json:"checks"`
+}
+
+// HealthchecksCheckRequest is the body of the create and update check APIs
+type HealthchecksCheckRequest struct {
+	Name     string `json:"name"`
+	Tags     string `json:"tags"`
+	Desc     string `json:"desc"`
+	Grace    int    `json:"grace"`
+	Timeout  int    `json:"timeout,omitempty"`
+	Schedule string `json:"schedule,omitempty"`
+	Tz       string `json:"tz,omitempty"`
+	Channels string `json:"channels,omitempty"`
+}
@@ -0,0 +1,47 @@
+package local
+
+import (
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// TranslateCheck translates a provider agnostic check into a Local config, along with the fields
+// of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.LocalConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.LocalConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			if check.Interval < minInterval {
+				unsupported = append(unsupported, "interval: must be at least 5 seconds")
+				continue
+			}
+			providerConfig.Interval = check.Interval
+		case "timeout":
+			providerConfig.Timeout = check.Timeout
+		case "method":
+			providerConfig.Method = check.Method
+		case "headers":
+			providerConfig.Headers = check.Headers
+		case "body":
+			providerConfig.Body = check.Body
+		case "expectedStatusCodes":
+			providerConfig.ExpectedStatusCodes = check.ExpectedStatusCodes
+		case "bodyAssertions":
+			providerConfig.BodyAssertions = check.BodyAssertions
+		case "followRedirects":
+			providerConfig.FollowRedirects = check.FollowRedirects
+		case "verifyTLS":
+			providerConfig.VerifyTLS = check.VerifyTLS
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
@@ -0,0 +1,61 @@
+package local
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+var (
+	probeSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingressmonitorcontroller_probe_success",
+		Help: "Whether the last probe of a Local monitor succeeded",
+	}, []string{"monitor", "target"})
+
+	probeDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingressmonitorcontroller_probe_duration_seconds",
+		Help: "Duration of the last probe of a Local monitor",
+	}, []string{"monitor", "target"})
+
+	probeStatusCode = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingressmonitorcontroller_probe_http_status_code",
+		Help: "HTTP status code of the last probe of a Local monitor, 0 when no response was received",
+	}, []string{"monitor", "target"})
+
+	probeCertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingressmonitorcontroller_probe_ssl_earliest_cert_expiry",
+		Help: "Expiry in unixtime of the certificate of a Local monitor that expires first",
+	}, []string{"monitor", "target"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(probeSuccess, probeDuration, probeStatusCode, probeCertificateExpiry)
+}
+
+// observeProbe records the result of a probe of the monitor
+func observeProbe(m models.Monitor, result models.ProbeResult) {
+	labels := prometheus.Labels{"monitor": m.Name, "target": m.URL}
+	success := 0.0
+	if result.Success {
+		success = 1
+	}
+	probeSuccess.With(labels).Set(success)
+	probeDuration.With(labels).Set(result.Duration.Seconds())
+	if m.CheckType() == endpointmonitorv1alpha1.CheckTypeHTTP {
+		probeStatusCode.With(labels).Set(float64(result.StatusCode))
+	}
+	if !result.CertificateNotAfter.IsZero() {
+		probeCertificateExpiry.With(labels).Set(float64(result.CertificateNotAfter.Unix()))
+	}
+}
+
+// forgetProbes deletes the series of the monitor, once it is removed or probes another target
+func forgetProbes(m models.Monitor) {
+	labels := prometheus.Labels{"monitor": m.Name, "target": m.URL}
+	probeSuccess.Delete(labels)
+	probeDuration.Delete(labels)
+	probeStatusCode.Delete(labels)
+	probeCertificateExpiry.Delete(labels)
+}
@@ -0,0 +1,247 @@
+// Package local adds monitoring from inside the cluster in IngressMonitorController, the controller
+// probes the endpoints itself and exposes the results as Prometheus metrics
+package local
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+var log = logf.Log.WithName("local-monitor")
+
+const (
+	// DefaultInterval is the time in seconds between two probes when the config sets no interval
+	DefaultInterval = 60
+	// DefaultTimeout is the time in seconds a probe waits for the endpoint when the config sets no timeout
+	DefaultTimeout = 10
+
+	// minInterval is the shortest time in seconds between two probes
+	minInterval = 5
+
+	// defaultAccount keys the prober of the provider when it sets no id
+	defaultAccount = "Local"
+)
+
+var (
+	// probers holds the prober of each account, they outlive the services so that reloading the config
+	// doesn't leave the probes of the previous service running
+	probers      = map[string]*prober{}
+	probersMutex sync.Mutex
+)
+
+// LocalMonitorService runs the monitors in the controller, each one probes its endpoint at its interval
+// until it is removed
+type LocalMonitorService struct {
+	prober *prober
+}
+
+// prober holds the monitors of an account by ID
+type prober struct {
+	mutex    sync.Mutex
+	monitors map[string]*localMonitor
+}
+
+type localMonitor struct {
+	monitor models.Monitor
+	ctx     context.Context
+	cancel  context.CancelFunc
+	result  *models.ProbeResult
+}
+
+// Setup function is used to initialise the Local service, which picks up the monitors of the account
+// left by the previous service
+func (service *LocalMonitorService) Setup(p config.Provider) error {
+	account := p.ID
+	if len(account) == 0 {
+		account = defaultAccount
+	}
+	probersMutex.Lock()
+	defer probersMutex.Unlock()
+	if probers[account] == nil {
+		probers[account] = &prober{monitors: map[string]*localMonitor{}}
+	}
+	service.prober = probers[account]
+	return nil
+}
+
+// SupportsCheckType returns whether the controller can run the check type, HTTP and TCP are supported
+func (service *LocalMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType == endpointmonitorv1alpha1.CheckTypeHTTP || checkType == endpointmonitorv1alpha1.CheckTypeTCP
+}
+
+// GetAll returns the monitors run by the controller
+func (service *LocalMonitorService) GetAll() ([]models.Monitor, error) {
+	service.prober.mutex.Lock()
+	defer service.prober.mutex.Unlock()
+	monitors := []models.Monitor{}
+	for _, local := range service.prober.monitors {
+		monitors = append(monitors, local.monitor)
+	}
+	sort.Slice(monitors, func(i, j int) bool {
+		return monitors[i].Name < monitors[j].Name
+	})
+	return monitors, nil
+}
+
+// GetByName returns the monitor with the name, nil when there is none
+func (service *LocalMonitorService) GetByName(name string) (*models.Monitor, error) {
+	service.prober.mutex.Lock()
+	defer service.prober.mutex.Unlock()
+	if local := service.prober.byName(name); local != nil {
+		monitor := local.monitor
+		return &monitor, nil
+	}
+	return nil, nil
+}
+
+// Add starts probing the endpoint of the monitor
+func (service *LocalMonitorService) Add(m models.Monitor) {
+	service.prober.mutex.Lock()
+	defer service.prober.mutex.Unlock()
+	if service.prober.byName(m.Name) != nil {
+		log.Info("Monitor already exists: " + m.Name)
+		return
+	}
+	m.ID = uuid.NewString()
+	m.Config = configOf(m).DeepCopy()
+	service.prober.start(&localMonitor{monitor: m})
+	log.Info("Monitor Added: " + m.Name)
+}
+
+// Update restarts the probes of the monitor with its new config, the last result is kept while the
+// monitor probes the same endpoint
+func (service *LocalMonitorService) Update(m models.Monitor) {
+	service.prober.mutex.Lock()
+	defer service.prober.mutex.Unlock()
+	local := service.prober.monitors[m.ID]
+	if local == nil {
+		log.Info("Monitor couldn't be updated, it doesn't exist: " + m.Name)
+		return
+	}
+	local.cancel()
+	if local.monitor.Name != m.Name || local.monitor.URL != m.URL {
+		forgetProbes(local.monitor)
+		local.result = nil
+	}
+	m.Config = configOf(m).DeepCopy()
+	local.monitor = m
+	service.prober.start(local)
+	log.Info("Monitor Updated: " + m.Name)
+}
+
+// Remove stops probing the endpoint of the monitor
+func (service *LocalMonitorService) Remove(m models.Monitor) {
+	service.prober.mutex.Lock()
+	defer service.prober.mutex.Unlock()
+	local := service.prober.monitors[m.ID]
+	if local == nil {
+		log.Info("Monitor couldn't be removed, it doesn't exist: " + m.Name)
+		return
+	}
+	local.cancel()
+	forgetProbes(local.monitor)
+	delete(service.prober.monitors, m.ID)
+	log.Info("Monitor Removed: " + m.Name)
+}
+
+// Equal compares the endpoints and configs of the monitors
+func (service *LocalMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
+	return oldMonitor.URL == newMonitor.URL && reflect.DeepEqual(configOf(oldMonitor), configOf(newMonitor))
+}
+
+// ProbeResult returns the result of the last probe of the monitor, nil until it is probed
+func (service *LocalMonitorService) ProbeResult(name string) *models.ProbeResult {
+	service.prober.mutex.Lock()
+	defer service.prober.mutex.Unlock()
+	local := service.prober.byName(name)
+	if local == nil || local.result == nil {
+		return nil
+	}
+	result := *local.result
+	return &result
+}
+
+// byName returns the monitor with the name, the mutex of the prober must be held
+func (p *prober) byName(name string) *localMonitor {
+	for _, local := range p.monitors {
+		if local.monitor.Name == name {
+			return local
+		}
+	}
+	return nil
+}
+
+// start runs the probes of the monitor until it is cancelled, the mutex of the prober must be held
+func (p *prober) start(local *localMonitor) {
+	local.ctx, local.cancel = context.WithCancel(context.Background())
+	p.monitors[local.monitor.ID] = local
+	go p.run(local.ctx, local.monitor)
+}
+
+func (p *prober) run(ctx context.Context, m models.Monitor) {
+	providerConfig := configOf(m)
+	ticker := time.NewTicker(intervalOf(providerConfig))
+	defer ticker.Stop()
+	for {
+		result := probe(ctx, m, providerConfig)
+		if ctx.Err() != nil {
+			return
+		}
+		p.record(ctx, m, result)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record keeps the result of a probe and exports it as metrics, unless the probes were restarted
+// while it ran
+func (p *prober) record(ctx context.Context, m models.Monitor, result models.ProbeResult) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	local := p.monitors[m.ID]
+	if local == nil || local.ctx != ctx {
+		return
+	}
+	result.Interval = intervalOf(configOf(m))
+	result.LastTransitionTime = result.Time
+	if local.result != nil && local.result.Success == result.Success {
+		result.LastTransitionTime = local.result.LastTransitionTime
+	}
+	local.result = &result
+	observeProbe(m, result)
+}
+
+// configOf returns the config of the monitor, an empty one when it has none
+func configOf(m models.Monitor) *endpointmonitorv1alpha1.LocalConfig {
+	if providerConfig, ok := m.Config.(*endpointmonitorv1alpha1.LocalConfig); ok && providerConfig != nil {
+		return providerConfig
+	}
+	return &endpointmonitorv1alpha1.LocalConfig{}
+}
+
+func intervalOf(providerConfig *endpointmonitorv1alpha1.LocalConfig) time.Duration {
+	if providerConfig.Interval < minInterval {
+		return DefaultInterval * time.Second
+	}
+	return time.Duration(providerConfig.Interval) * time.Second
+}
+
+func timeoutOf(providerConfig *endpointmonitorv1alpha1.LocalConfig) time.Duration {
+	if providerConfig.Timeout <= 0 {
+		return DefaultTimeout * time.Second
+	}
+	return time.Duration(providerConfig.Timeout) * time.Second
+}
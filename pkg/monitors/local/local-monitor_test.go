@@ -0,0 +1,159 @@
+package local
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+func TestProbeHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/", http.StatusFound)
+		case "/maintenance":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	followRedirects := false
+	headers := map[string]string{"Authorization": "Bearer token"}
+	tests := []struct {
+		name           string
+		path           string
+		providerConfig endpointmonitorv1alpha1.LocalConfig
+		success        bool
+		statusCode     int
+		message        string
+	}{
+		{"up", "/", endpointmonitorv1alpha1.LocalConfig{Headers: headers}, true, http.StatusOK, ""},
+		{"unauthorized", "/", endpointmonitorv1alpha1.LocalConfig{}, false, http.StatusUnauthorized, "status 401"},
+		{"down", "/maintenance", endpointmonitorv1alpha1.LocalConfig{}, false, http.StatusServiceUnavailable, "status 503"},
+		{"expected status code", "/maintenance", endpointmonitorv1alpha1.LocalConfig{ExpectedStatusCodes: []int{503}}, true, http.StatusServiceUnavailable, ""},
+		{"redirect not followed", "/redirect", endpointmonitorv1alpha1.LocalConfig{FollowRedirects: &followRedirects, ExpectedStatusCodes: []int{200}}, false, http.StatusFound, "status 302"},
+		{"body assertion", "/", endpointmonitorv1alpha1.LocalConfig{
+			Headers:        headers,
+			BodyAssertions: []endpointmonitorv1alpha1.BodyAssertion{{Type: endpointmonitorv1alpha1.BodyAssertionContains, Value: `"status":"ok"`}, {Type: endpointmonitorv1alpha1.BodyAssertionNotContains, Value: "ok"}},
+		}, false, http.StatusOK, `the body contains "ok"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := probe(context.Background(), models.Monitor{URL: server.URL + tt.path}, &tt.providerConfig)
+			assert.Equal(t, result.Success, tt.success)
+			assert.Equal(t, result.StatusCode, tt.statusCode)
+			assert.Equal(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestProbeHTTPSReportsCertificateExpiry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	result := probe(context.Background(), models.Monitor{URL: server.URL}, &endpointmonitorv1alpha1.LocalConfig{})
+	assert.Assert(t, !result.Success)
+	assert.Assert(t, strings.Contains(result.Message, "certificate"), result.Message)
+
+	verifyTLS := false
+	result = probe(context.Background(), models.Monitor{URL: server.URL}, &endpointmonitorv1alpha1.LocalConfig{VerifyTLS: &verifyTLS})
+	assert.Assert(t, result.Success)
+	assert.Assert(t, result.CertificateNotAfter.Equal(server.Certificate().NotAfter))
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	address := listener.Addr().String()
+
+	result := probe(context.Background(), models.Monitor{URL: "tcp://" + address}, &endpointmonitorv1alpha1.LocalConfig{})
+	assert.Assert(t, result.Success, result.Message)
+
+	listener.Close()
+	result = probe(context.Background(), models.Monitor{URL: "tcp://" + address}, &endpointmonitorv1alpha1.LocalConfig{})
+	assert.Assert(t, !result.Success)
+}
+
+func TestLocalMonitorLifecycle(t *testing.T) {
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	service := &LocalMonitorService{}
+	assert.NilError(t, service.Setup(config.Provider{Name: "Local", ID: "lifecycle"}))
+	service.Add(models.Monitor{Name: "checkout", URL: server.URL, Config: &endpointmonitorv1alpha1.LocalConfig{Interval: 5}})
+
+	monitor, err := service.GetByName("checkout")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor != nil && len(monitor.ID) > 0)
+	result := waitForProbe(t, service, "checkout", func(result *models.ProbeResult) bool { return result != nil })
+	assert.Assert(t, result.Success)
+	assert.Equal(t, result.Interval, 5*time.Second)
+
+	// A new service of the account, as set up on reload, keeps the monitors running
+	reloaded := &LocalMonitorService{}
+	assert.NilError(t, reloaded.Setup(config.Provider{Name: "Local", ID: "lifecycle"}))
+	all, err := reloaded.GetAll()
+	assert.NilError(t, err)
+	assert.Equal(t, len(all), 1)
+
+	down.Store(true)
+	updated := *monitor
+	updated.Config = &endpointmonitorv1alpha1.LocalConfig{Interval: 10}
+	assert.Assert(t, !reloaded.Equal(*monitor, updated))
+	reloaded.Update(updated)
+	result = waitForProbe(t, reloaded, "checkout", func(result *models.ProbeResult) bool { return !result.Success })
+	assert.Equal(t, result.Message, "status 502")
+	assert.Equal(t, result.Interval, 10*time.Second)
+
+	reloaded.Remove(updated)
+	monitor, err = reloaded.GetByName("checkout")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor == nil)
+	assert.Assert(t, reloaded.ProbeResult("checkout") == nil)
+}
+
+func waitForProbe(t *testing.T, service *LocalMonitorService, name string, done func(*models.ProbeResult) bool) *models.ProbeResult {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		result := service.ProbeResult(name)
+		if result != nil && done(result) {
+			return result
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("monitor %s wasn't probed", name)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTranslateCheck(t *testing.T) {
+	providerConfig, unsupported := TranslateCheck(&endpointmonitorv1alpha1.Check{
+		Interval:  30,
+		Method:    "HEAD",
+		Locations: []string{"eu"},
+	})
+	assert.DeepEqual(t, providerConfig, &endpointmonitorv1alpha1.LocalConfig{Interval: 30, Method: "HEAD"})
+	assert.DeepEqual(t, unsupported, []string{"locations"})
+}
@@ -0,0 +1,146 @@
+package local
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+// maxBodySize bounds the part of the response body read to run the body assertions
+const maxBodySize = 1 << 20
+
+// probe checks the endpoint of the monitor once, HTTP endpoints through a request and TCP endpoints
+// through a connection
+func probe(ctx context.Context, m models.Monitor, providerConfig *endpointmonitorv1alpha1.LocalConfig) models.ProbeResult {
+	ctx, cancel := context.WithTimeout(ctx, timeoutOf(providerConfig))
+	defer cancel()
+
+	start := time.Now()
+	var result models.ProbeResult
+	if m.CheckType() == endpointmonitorv1alpha1.CheckTypeTCP {
+		result = probeTCP(ctx, m)
+	} else {
+		result = probeHTTP(ctx, m.URL, providerConfig)
+	}
+	result.Time = start
+	result.Duration = time.Since(start)
+	return result
+}
+
+// probeHTTP sends the request of the config to the url and asserts on its response
+func probeHTTP(ctx context.Context, url string, providerConfig *endpointmonitorv1alpha1.LocalConfig) models.ProbeResult {
+	method := http.MethodGet
+	if len(providerConfig.Method) > 0 {
+		method = providerConfig.Method
+	}
+	var body io.Reader
+	if len(providerConfig.Body) > 0 {
+		body = strings.NewReader(providerConfig.Body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return models.ProbeResult{Message: err.Error()}
+	}
+	for name, value := range providerConfig.Headers {
+		request.Header.Set(name, value)
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: providerConfig.VerifyTLS != nil && !*providerConfig.VerifyTLS}, // #nosec G402 -- opted out through verifyTLS
+		},
+	}
+	if providerConfig.FollowRedirects != nil && !*providerConfig.FollowRedirects {
+		httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return models.ProbeResult{Message: err.Error()}
+	}
+	defer response.Body.Close()
+
+	result := models.ProbeResult{StatusCode: response.StatusCode}
+	if response.TLS != nil {
+		for _, certificate := range response.TLS.PeerCertificates {
+			if result.CertificateNotAfter.IsZero() || certificate.NotAfter.Before(result.CertificateNotAfter) {
+				result.CertificateNotAfter = certificate.NotAfter
+			}
+		}
+	}
+	if !expectedStatusCode(response.StatusCode, providerConfig.ExpectedStatusCodes) {
+		result.Message = "status " + strconv.Itoa(response.StatusCode)
+		return result
+	}
+	if len(providerConfig.BodyAssertions) > 0 {
+		responseBody, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+		if err != nil {
+			result.Message = "failed to read the body: " + err.Error()
+			return result
+		}
+		if message := assertBody(string(responseBody), providerConfig.BodyAssertions); len(message) > 0 {
+			result.Message = message
+			return result
+		}
+	}
+	result.Success = true
+	return result
+}
+
+// probeTCP opens a connection to the host and port of the monitor
+func probeTCP(ctx context.Context, m models.Monitor) models.ProbeResult {
+	host, port := m.Target()
+	if port == 0 {
+		return models.ProbeResult{Message: "the url sets no port"}
+	}
+	connection, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return models.ProbeResult{Message: err.Error()}
+	}
+	connection.Close()
+	return models.ProbeResult{Success: true}
+}
+
+// expectedStatusCode returns whether the status code is one of the expected ones, 2xx and 3xx when
+// none are set
+func expectedStatusCode(statusCode int, expected []int) bool {
+	if len(expected) == 0 {
+		return statusCode >= http.StatusOK && statusCode < http.StatusBadRequest
+	}
+	for _, code := range expected {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// assertBody returns why the body fails the first failing assertion, empty when they all pass
+func assertBody(body string, assertions []endpointmonitorv1alpha1.BodyAssertion) string {
+	for _, assertion := range assertions {
+		contains := strings.Contains(body, assertion.Value)
+		switch assertion.Type {
+		case endpointmonitorv1alpha1.BodyAssertionContains:
+			if !contains {
+				return fmt.Sprintf("the body doesn't contain %q", assertion.Value)
+			}
+		case endpointmonitorv1alpha1.BodyAssertionNotContains:
+			if contains {
+				return fmt.Sprintf("the body contains %q", assertion.Value)
+			}
+		}
+	}
+	return ""
+}
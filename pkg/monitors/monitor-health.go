@@ -0,0 +1,64 @@
+package monitors
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// HealthChecker periodically checks the connectivity of the providers in the controller config
+type HealthChecker struct {
+	MonitorServices []*MonitorServiceProxy
+	Interval        time.Duration
+	// OnCheck is called after every round of checks
+	OnCheck func(ctx context.Context)
+}
+
+// NewHealthChecker returns a HealthChecker for the monitor services, interval is in seconds
+func NewHealthChecker(monitorServices []*MonitorServiceProxy, interval int, onCheck func(ctx context.Context)) *HealthChecker {
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+	return &HealthChecker{
+		MonitorServices: monitorServices,
+		Interval:        time.Duration(interval) * time.Second,
+		OnCheck:         onCheck,
+	}
+}
+
+const defaultHealthCheckInterval = 60
+
+// Start checks the providers until ctx is done, it implements manager.Runnable
+func (c *HealthChecker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false since the readiness of every replica depends on the checks
+func (c *HealthChecker) NeedLeaderElection() bool {
+	return false
+}
+
+// Check checks the connectivity of all providers once
+func (c *HealthChecker) Check(ctx context.Context) {
+	for _, monitorService := range c.MonitorServices {
+		_ = monitorService.CheckConnection()
+	}
+	if c.OnCheck != nil {
+		c.OnCheck(ctx)
+	}
+}
+
+// ReadyzCheck reports whether the provider is set up and reachable, it implements healthz.Checker
+func (mp *MonitorServiceProxy) ReadyzCheck(_ *http.Request) error {
+	return mp.Ready()
+}
@@ -0,0 +1,54 @@
+package monitors
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	providerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingressmonitorcontroller_provider_requests_total",
+		Help: "Number of calls to uptime providers by provider type, account and operation",
+	}, []string{"provider", "account", "operation"})
+
+	providerRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingressmonitorcontroller_provider_request_errors_total",
+		Help: "Number of failed calls to uptime providers by provider type, account and operation",
+	}, []string{"provider", "account", "operation"})
+
+	providerRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ingressmonitorcontroller_provider_request_duration_seconds",
+		Help:    "Duration of calls to uptime providers by provider type, account and operation",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider", "account", "operation"})
+
+	providerUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingressmonitorcontroller_provider_up",
+		Help: "Whether the last connectivity check of an uptime provider account succeeded",
+	}, []string{"provider", "account"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(providerRequests, providerRequestErrors, providerRequestDuration, providerUp)
+}
+
+// observe records a call to the provider of the proxy, err is nil for operations that don't report errors
+func (mp *MonitorServiceProxy) observe(operation string, start time.Time, err error) {
+	labels := prometheus.Labels{"provider": mp.monitorType, "account": mp.GetID(), "operation": operation}
+	providerRequests.With(labels).Inc()
+	providerRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+	if err != nil {
+		providerRequestErrors.With(labels).Inc()
+	}
+}
+
+// observeConnection records the result of a connectivity check of the provider of the proxy
+func (mp *MonitorServiceProxy) observeConnection(err error) {
+	up := 1.0
+	if err != nil {
+		up = 0
+	}
+	providerUp.With(prometheus.Labels{"provider": mp.monitorType, "account": mp.GetID()}).Set(up)
+}
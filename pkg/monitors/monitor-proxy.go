@@ -1,18 +1,29 @@
 package monitors
 
 import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
 	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
 	"github.com/stakater/IngressMonitorController/v2/pkg/config"
 	"github.com/stakater/IngressMonitorController/v2/pkg/models"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/appinsights"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/betterstack"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/blackbox"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/gcloud"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/grafana"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/healthchecks"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/local"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/pingdom"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/pingdomtransaction"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/plugin"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/statuscake"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/updown"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/uptime"
 	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/uptimerobot"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/webhook"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -28,42 +39,158 @@ const (
 	TypeAppInsights        = "AppInsights"
 	TypeGCloud             = "gcloud"
 	TypeGrafana            = "Grafana"
+	TypePlugin             = "Plugin"
+	TypeWebhook            = "Webhook"
+	TypeHealthchecks       = "Healthchecks"
+	TypeLocal              = "Local"
+	TypeBlackbox           = "Blackbox"
+	TypeBetterStack        = "BetterStack"
 )
 
+// maxNameLengths holds the longest monitor name accepted by each provider, providers
+// without a known limit are left out
+var maxNameLengths = map[string]int{
+	TypeUptimeRobot:        250,
+	TypePingdom:            256,
+	TypePingdomTransaction: 256,
+	TypeStatusCake:         255,
+	TypeUptime:             255,
+	TypeUpdown:             255,
+	// Leaves room for the "-alert" suffix of the alert rule
+	TypeAppInsights:  254,
+	TypeGCloud:       100,
+	TypeGrafana:      128,
+	TypeHealthchecks: 100,
+}
+
+// renameUnsupported holds the providers that identify monitors by their name, so a
+// monitor can't be renamed in place
+var renameUnsupported = map[string]bool{
+	TypeAppInsights: true,
+}
+
+// httpUnsupported holds the providers that only run the check types they support, instead of the
+// HTTP checks every other provider runs
+var httpUnsupported = map[string]bool{
+	TypeHealthchecks: true,
+}
+
+// ErrPauseNotSupported is returned by Pause for providers that cannot pause monitors
+var ErrPauseNotSupported = errors.New("pausing monitors is not supported")
+
+// ErrCertificateMonitorsNotSupported is returned for providers without dedicated certificate monitors
+var ErrCertificateMonitorsNotSupported = errors.New("certificate monitors are not supported")
+
 type MonitorServiceProxy struct {
 	monitorType string
 	monitor     MonitorService
+
+	// id and isDefault identify the account when several providers of the same type are configured
+	id        string
+	isDefault bool
+
+	// provider is the configuration the monitor service was last set up with, setupErr the error
+	// it failed with. Setup is retried with an exponential backoff until it succeeds.
+	provider      config.Provider
+	setupErr      error
+	setupFailures int
+	lastSetup     time.Time
+
+	// connectionErr is the error of the last connectivity check, see CheckConnection
+	connectionErr error
+	lastCheck     time.Time
+
+	// mu guards monitor and the setup state, which are replaced when the provider is reloaded
+	mu sync.RWMutex
 }
 
+const (
+	// setupRetryInitialInterval is the delay before the setup of a failing provider is retried, it doubles
+	// with every failure up to setupRetryMaxInterval
+	setupRetryInitialInterval = 5 * time.Second
+	setupRetryMaxInterval     = 5 * time.Minute
+)
+
 func (mp *MonitorServiceProxy) GetType() string {
 	return mp.monitorType
 }
 
-func (mp *MonitorServiceProxy) OfType(mType string) MonitorServiceProxy {
+// GetID returns the ID of the provider account, the provider type is used for accounts without an ID
+func (mp *MonitorServiceProxy) GetID() string {
+	if len(mp.id) == 0 {
+		return mp.monitorType
+	}
+	return mp.id
+}
+
+// IsDefault returns whether the account is used for EndpointMonitors that don't select one
+func (mp *MonitorServiceProxy) IsDefault() bool {
+	return mp.isDefault
+}
+
+// GetMaxNameLength returns the maximum length of a monitor name for the provider, 0 means no limit
+func (mp *MonitorServiceProxy) GetMaxNameLength() int {
+	return maxNameLengths[mp.monitorType]
+}
+
+// SupportsRename returns whether a monitor can be renamed in place through Update
+func (mp *MonitorServiceProxy) SupportsRename() bool {
+	return !renameUnsupported[mp.monitorType]
+}
+
+// SupportsCheckType returns whether the provider can run the check type, every provider except the
+// heartbeat only ones runs HTTP checks
+func (mp *MonitorServiceProxy) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	if len(checkType) == 0 || checkType == endpointmonitorv1alpha1.CheckTypeHTTP {
+		return !httpUnsupported[mp.monitorType]
+	}
+	if supporter, ok := mp.service().(CheckTypeSupporter); ok {
+		return supporter.SupportsCheckType(checkType)
+	}
+	return false
+}
+
+func (mp *MonitorServiceProxy) OfType(mType string) *MonitorServiceProxy {
 	mp.monitorType = mType
+	mp.monitor, mp.setupErr = newMonitorService(mType)
+	return mp
+}
+
+func newMonitorService(mType string) (MonitorService, error) {
 	switch mType {
 	case TypeUptimeRobot:
-		mp.monitor = &uptimerobot.UpTimeMonitorService{}
+		return &uptimerobot.UpTimeMonitorService{}, nil
 	case TypePingdom:
-		mp.monitor = &pingdom.PingdomMonitorService{}
+		return &pingdom.PingdomMonitorService{}, nil
 	case TypePingdomTransaction:
-		mp.monitor = &pingdomtransaction.PingdomTransactionMonitorService{}
+		return &pingdomtransaction.PingdomTransactionMonitorService{}, nil
 	case TypeStatusCake:
-		mp.monitor = &statuscake.StatusCakeMonitorService{}
+		return &statuscake.StatusCakeMonitorService{}, nil
 	case TypeUptime:
-		mp.monitor = &uptime.UpTimeMonitorService{}
+		return &uptime.UpTimeMonitorService{}, nil
 	case TypeUpdown:
-		mp.monitor = &updown.UpdownMonitorService{}
+		return &updown.UpdownMonitorService{}, nil
 	case TypeAppInsights:
-		mp.monitor = &appinsights.AppinsightsMonitorService{}
+		return &appinsights.AppinsightsMonitorService{}, nil
 	case TypeGCloud:
-		mp.monitor = &gcloud.MonitorService{}
+		return &gcloud.MonitorService{}, nil
 	case TypeGrafana:
-		mp.monitor = &grafana.GrafanaMonitorService{}
+		return &grafana.GrafanaMonitorService{}, nil
+	case TypePlugin:
+		return &plugin.PluginMonitorService{}, nil
+	case TypeWebhook:
+		return &webhook.WebhookMonitorService{}, nil
+	case TypeHealthchecks:
+		return &healthchecks.HealthchecksMonitorService{}, nil
+	case TypeLocal:
+		return &local.LocalMonitorService{}, nil
+	case TypeBlackbox:
+		return &blackbox.BlackboxMonitorService{}, nil
+	case TypeBetterStack:
+		return &betterstack.BetterStackMonitorService{}, nil
 	default:
-		panic("No such provider found: " + mType)
+		return nil, fmt.Errorf("no such provider found: %s", mType)
 	}
-	return *mp
 }
 
 func (mp *MonitorServiceProxy) ExtractConfig(spec endpointmonitorv1alpha1.EndpointMonitorSpec) interface{} {
@@ -87,36 +214,380 @@ func (mp *MonitorServiceProxy) ExtractConfig(spec endpointmonitorv1alpha1.Endpoi
 		config = spec.GCloudConfig
 	case TypeGrafana:
 		config = spec.GrafanaConfig
+	case TypePlugin:
+		config = spec.PluginConfig
+	case TypeWebhook:
+		config = spec.WebhookConfig
+	case TypeHealthchecks:
+		config = spec.HealthchecksConfig
+	case TypeLocal:
+		config = spec.LocalConfig
+	case TypeBlackbox:
+		config = spec.BlackboxConfig
+	case TypeBetterStack:
+		config = spec.BetterStackConfig
 	default:
 		return config
 	}
 	return config
 }
 
-func (mp *MonitorServiceProxy) Setup(p config.Provider) {
-	mp.monitor.Setup(p)
+// TranslateCheck translates a provider agnostic check into the config of the provider. The config is
+// returned as defaults for the provider config of the spec, along with the fields of the check the
+// provider can't express.
+func (mp *MonitorServiceProxy) TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.MonitorProviderDefaults, []string) {
+	defaults := &endpointmonitorv1alpha1.MonitorProviderDefaults{}
+	var unsupported []string
+	switch mp.monitorType {
+	case TypeUptimeRobot:
+		defaults.UptimeRobotConfig, unsupported = uptimerobot.TranslateCheck(check)
+	case TypePingdom:
+		defaults.PingdomConfig, unsupported = pingdom.TranslateCheck(check)
+	case TypePingdomTransaction:
+		defaults.PingdomTransactionConfig, unsupported = pingdomtransaction.TranslateCheck(check)
+	case TypeStatusCake:
+		defaults.StatusCakeConfig, unsupported = statuscake.TranslateCheck(check)
+	case TypeUptime:
+		defaults.UptimeConfig, unsupported = uptime.TranslateCheck(check)
+	case TypeUpdown:
+		defaults.UpdownConfig, unsupported = updown.TranslateCheck(check)
+	case TypeAppInsights:
+		defaults.AppInsightsConfig, unsupported = appinsights.TranslateCheck(check)
+	case TypeGCloud:
+		defaults.GCloudConfig, unsupported = gcloud.TranslateCheck(check)
+	case TypeGrafana:
+		defaults.GrafanaConfig, unsupported = grafana.TranslateCheck(check)
+	case TypePlugin:
+		defaults.PluginConfig, unsupported = plugin.TranslateCheck(check)
+	case TypeWebhook:
+		defaults.WebhookConfig, unsupported = webhook.TranslateCheck(check)
+	case TypeLocal:
+		defaults.LocalConfig, unsupported = local.TranslateCheck(check)
+	case TypeBlackbox:
+		defaults.BlackboxConfig, unsupported = blackbox.TranslateCheck(check)
+	case TypeBetterStack:
+		defaults.BetterStackConfig, unsupported = betterstack.TranslateCheck(check)
+	default:
+		unsupported = check.SetFields()
+	}
+	return defaults, unsupported
+}
+
+// TranslateCertificateAlert translates the alerting on the expiry of a certificate into the config of
+// providers that alert through the certificate settings of their monitors, nil is returned for the others
+func (mp *MonitorServiceProxy) TranslateCertificateAlert(alertDaysBefore int) *endpointmonitorv1alpha1.MonitorProviderDefaults {
+	switch mp.monitorType {
+	case TypePingdom:
+		return &endpointmonitorv1alpha1.MonitorProviderDefaults{PingdomConfig: pingdom.TranslateCertificateAlert(alertDaysBefore)}
+	default:
+		return nil
+	}
+}
+
+// SupportsCertificateMonitors returns whether the provider alerts on the expiry of certificates through
+// dedicated certificate monitors
+func (mp *MonitorServiceProxy) SupportsCertificateMonitors() bool {
+	_, ok := mp.service().(CertificateMonitorer)
+	return ok
+}
+
+// Setup sets up the monitor service for the provider account. A provider that fails to set up doesn't
+// affect the others, it reports the error through Healthy and is set up again by RetrySetup.
+func (mp *MonitorServiceProxy) Setup(p config.Provider) error {
+	mp.id = p.ID
+	return mp.Reload(p)
+}
+
+// Reload sets up a new monitor service with the given provider configuration and swaps it in
+// once ready, so that calls in progress keep using the previous one
+func (mp *MonitorServiceProxy) Reload(p config.Provider) error {
+	monitor, err := newMonitorService(mp.monitorType)
+	if err == nil {
+		err = p.CredentialsError()
+	}
+	if err == nil {
+		err = monitor.Setup(p)
+	}
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	mp.provider = p
+	mp.lastSetup = time.Now()
+	if err != nil {
+		mp.setupErr = fmt.Errorf("provider %s is not ready: %w", mp.GetID(), err)
+		mp.setupFailures++
+		log.Error(err, "Failed to set up provider "+mp.GetID(), "retryIn", mp.setupRetryDelay())
+		return mp.setupErr
+	}
+	mp.monitor = monitor
+	mp.setupErr = nil
+	mp.setupFailures = 0
+	return nil
+}
+
+// Healthy returns the error the provider failed to set up with, or nil once it is ready
+func (mp *MonitorServiceProxy) Healthy() error {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return mp.setupErr
 }
 
-func (mp *MonitorServiceProxy) GetAll() ([]models.Monitor, error) {
-	return mp.monitor.GetAll()
+// CheckConnection makes a cheap authenticated call to the provider, providers without one only
+// report whether they are set up. The result is kept for Ready.
+func (mp *MonitorServiceProxy) CheckConnection() (err error) {
+	defer func() {
+		mp.mu.Lock()
+		previous := mp.connectionErr
+		mp.connectionErr = err
+		mp.lastCheck = time.Now()
+		mp.mu.Unlock()
+		mp.observeConnection(err)
+
+		if err != nil && previous == nil {
+			log.Error(err, "Connectivity check of provider "+mp.GetID()+" failed")
+		} else if err == nil && previous != nil {
+			log.Info("Provider " + mp.GetID() + " is reachable again")
+		}
+	}()
+
+	if err := mp.Healthy(); err != nil {
+		return err
+	}
+	checker, ok := mp.service().(ConnectionChecker)
+	if !ok {
+		return nil
+	}
+
+	defer func(start time.Time) { mp.observe("check_connection", start, err) }(time.Now())
+	if err := checker.CheckConnection(); err != nil {
+		return fmt.Errorf("provider %s is not reachable: %w", mp.GetID(), err)
+	}
+	return nil
+}
+
+// Ready returns the error the provider failed to set up with, or else the error of its last
+// connectivity check
+func (mp *MonitorServiceProxy) Ready() error {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	if mp.setupErr != nil {
+		return mp.setupErr
+	}
+	return mp.connectionErr
+}
+
+// LastCheck returns when the connectivity of the provider was last checked
+func (mp *MonitorServiceProxy) LastCheck() time.Time {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return mp.lastCheck
+}
+
+// RetrySetup sets up a failing provider again once its backoff has passed, and returns the
+// error it still fails with
+func (mp *MonitorServiceProxy) RetrySetup() error {
+	mp.mu.RLock()
+	err, due := mp.setupErr, time.Since(mp.lastSetup) >= mp.setupRetryDelay()
+	provider := mp.provider
+	mp.mu.RUnlock()
+
+	if err == nil || !due {
+		return err
+	}
+	log.Info("Retrying setup of provider " + mp.GetID())
+	return mp.Reload(provider)
 }
 
-func (mp *MonitorServiceProxy) GetByName(name string) (*models.Monitor, error) {
-	return mp.monitor.GetByName(name)
+// setupRetryDelay returns the backoff after the failures so far, mu must be held
+func (mp *MonitorServiceProxy) setupRetryDelay() time.Duration {
+	return retryDelay(mp.setupFailures)
+}
+
+// retryDelay returns the backoff of a setup after its failures so far
+func retryDelay(failures int) time.Duration {
+	delay := setupRetryInitialInterval
+	for i := 1; i < failures && delay < setupRetryMaxInterval; i++ {
+		delay *= 2
+	}
+	if delay > setupRetryMaxInterval {
+		delay = setupRetryMaxInterval
+	}
+	return delay
+}
+
+func (mp *MonitorServiceProxy) service() MonitorService {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return mp.monitor
+}
+
+func (mp *MonitorServiceProxy) GetAll() (monitors []models.Monitor, err error) {
+	defer func(start time.Time) { mp.observe("get_all", start, err) }(time.Now())
+	if err := mp.Healthy(); err != nil {
+		return nil, err
+	}
+	return mp.service().GetAll()
+}
+
+func (mp *MonitorServiceProxy) GetByName(name string) (monitor *models.Monitor, err error) {
+	defer func(start time.Time) { mp.observe("get_by_name", start, err) }(time.Now())
+	if err := mp.Healthy(); err != nil {
+		return nil, err
+	}
+	return mp.service().GetByName(name)
+}
+
+// GetByID returns the monitor with the given ID, or nil if it doesn't exist
+func (mp *MonitorServiceProxy) GetByID(id string) (monitor *models.Monitor, err error) {
+	defer func(start time.Time) { mp.observe("get_by_id", start, err) }(time.Now())
+	if err := mp.Healthy(); err != nil {
+		return nil, err
+	}
+	if getter, ok := mp.service().(MonitorByIDGetter); ok {
+		return getter.GetByID(id)
+	}
+
+	monitors, err := mp.service().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, monitor := range monitors {
+		if monitor.ID == id {
+			return &monitor, nil
+		}
+	}
+	return nil, nil
 }
 
 func (mp *MonitorServiceProxy) Add(m models.Monitor) {
-	mp.monitor.Add(m)
+	if err := mp.Healthy(); err != nil {
+		log.Error(err, "Skipping add of monitor "+m.Name)
+		return
+	}
+	defer mp.observe("add", time.Now(), nil)
+	mp.service().Add(m)
 }
 
+// Equal reports monitors as equal while the provider is not set up, as they can't be updated anyway
 func (mp *MonitorServiceProxy) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
-	return mp.monitor.Equal(oldMonitor, newMonitor)
+	if err := mp.Healthy(); err != nil {
+		return true
+	}
+	return mp.service().Equal(oldMonitor, newMonitor)
 }
 
 func (mp *MonitorServiceProxy) Update(m models.Monitor) {
-	mp.monitor.Update(m)
+	if err := mp.Healthy(); err != nil {
+		log.Error(err, "Skipping update of monitor "+m.Name)
+		return
+	}
+	defer mp.observe("update", time.Now(), nil)
+	mp.service().Update(m)
+}
+
+// Pause disables the checks of a monitor at the provider while keeping the monitor. It returns
+// ErrPauseNotSupported for providers that cannot pause monitors.
+func (mp *MonitorServiceProxy) Pause(m models.Monitor) (err error) {
+	if err := mp.Healthy(); err != nil {
+		return err
+	}
+	if pauser, ok := mp.service().(MonitorPauser); ok {
+		defer func(start time.Time) { mp.observe("pause", start, err) }(time.Now())
+		return pauser.Pause(m)
+	}
+
+	// The remaining providers pause monitors through their config
+	switch providerConfig := m.Config.(type) {
+	case *endpointmonitorv1alpha1.PingdomConfig:
+		paused := endpointmonitorv1alpha1.PingdomConfig{}
+		if providerConfig != nil {
+			paused = *providerConfig
+		}
+		paused.Paused = true
+		m.Config = &paused
+	case *endpointmonitorv1alpha1.PingdomTransactionConfig:
+		paused := endpointmonitorv1alpha1.PingdomTransactionConfig{}
+		if providerConfig != nil {
+			paused = *providerConfig
+		}
+		paused.Paused = true
+		m.Config = &paused
+	case *endpointmonitorv1alpha1.StatusCakeConfig:
+		paused := endpointmonitorv1alpha1.StatusCakeConfig{}
+		if providerConfig != nil {
+			paused = *providerConfig
+		}
+		paused.Paused = true
+		m.Config = &paused
+	default:
+		return fmt.Errorf("%w by provider %s", ErrPauseNotSupported, mp.GetType())
+	}
+	defer mp.observe("pause", time.Now(), nil)
+	mp.service().Update(m)
+	return nil
+}
+
+// EnsureCertificateMonitor creates or updates the dedicated monitor alerting on the expiry of the certificate
+// served at the URL of m and returns its ID. It returns ErrCertificateMonitorsNotSupported for providers
+// without certificate monitors.
+func (mp *MonitorServiceProxy) EnsureCertificateMonitor(m models.Monitor, id string, alertDaysBefore int) (certificateMonitorID string, err error) {
+	if err := mp.Healthy(); err != nil {
+		return "", err
+	}
+	certificateMonitorer, ok := mp.service().(CertificateMonitorer)
+	if !ok {
+		return "", fmt.Errorf("%w by provider %s", ErrCertificateMonitorsNotSupported, mp.GetType())
+	}
+	defer func(start time.Time) { mp.observe("ensure_certificate_monitor", start, err) }(time.Now())
+	return certificateMonitorer.EnsureCertificateMonitor(m, id, alertDaysBefore)
+}
+
+// RemoveCertificateMonitor deletes a dedicated certificate monitor. It returns ErrCertificateMonitorsNotSupported
+// for providers without certificate monitors.
+func (mp *MonitorServiceProxy) RemoveCertificateMonitor(id string) (err error) {
+	if err := mp.Healthy(); err != nil {
+		return err
+	}
+	certificateMonitorer, ok := mp.service().(CertificateMonitorer)
+	if !ok {
+		return fmt.Errorf("%w by provider %s", ErrCertificateMonitorsNotSupported, mp.GetType())
+	}
+	defer func(start time.Time) { mp.observe("remove_certificate_monitor", start, err) }(time.Now())
+	return certificateMonitorer.RemoveCertificateMonitor(id)
 }
 
-func (mp *MonitorServiceProxy) Remove(m models.Monitor) {
-	mp.monitor.Remove(m)
+// MonitorLinks returns the URLs of the dashboard page and public status page of the monitor, they are
+// empty for providers that can't link to their monitors
+func (mp *MonitorServiceProxy) MonitorLinks(m models.Monitor) (links models.MonitorLinks, err error) {
+	if err := mp.Healthy(); err != nil {
+		return links, err
+	}
+	linker, ok := mp.service().(MonitorLinker)
+	if !ok {
+		return links, nil
+	}
+	defer func(start time.Time) { mp.observe("monitor_links", start, err) }(time.Now())
+	return linker.MonitorLinks(m)
+}
+
+// ProbeResult returns the result of the last probe of the monitor by providers probing from inside the
+// cluster, nil for the other providers and until the monitor is probed
+func (mp *MonitorServiceProxy) ProbeResult(name string) *models.ProbeResult {
+	if err := mp.Healthy(); err != nil {
+		return nil
+	}
+	if reporter, ok := mp.service().(ProbeReporter); ok {
+		return reporter.ProbeResult(name)
+	}
+	return nil
+}
+
+// Remove fails while the provider is not set up, so that the deletion of the monitor is retried
+func (mp *MonitorServiceProxy) Remove(m models.Monitor) error {
+	if err := mp.Healthy(); err != nil {
+		return err
+	}
+	defer mp.observe("remove", time.Now(), nil)
+	mp.service().Remove(m)
+	return nil
 }
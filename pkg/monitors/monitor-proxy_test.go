@@ -1,9 +1,14 @@
 package monitors
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/stakater/IngressMonitorController/v2/pkg/util"
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
@@ -24,12 +29,265 @@ func TestMonitorServiceProxyOfTypeWithCorrectType(t *testing.T) {
 }
 
 func TestMonitorServiceProxyOfTypeWithWrongType(t *testing.T) {
-	util.AssertPanic(t, func() {
-		monitorType := "Testing"
-		uptime := (&MonitorServiceProxy{}).OfType(monitorType)
+	monitorType := "Testing"
+	uptime := (&MonitorServiceProxy{}).OfType(monitorType)
+
+	if uptime.monitorType != monitorType {
+		t.Error("Monitor type is not the same")
+	}
+	if uptime.Healthy() == nil {
+		t.Error("Expected a provider of an unknown type not to be ready")
+	}
+	if _, err := uptime.GetAll(); err == nil {
+		t.Error("Expected calls to a provider that is not ready to fail")
+	}
+	if err := uptime.Remove(models.Monitor{Name: "checkout"}); err == nil {
+		t.Error("Expected the removal from a provider that is not ready to fail")
+	}
+	if !uptime.Equal(models.Monitor{Name: "checkout"}, models.Monitor{Name: "checkout", URL: "https://checkout.example.com"}) {
+		t.Error("Expected monitors of a provider that is not ready not to be updated")
+	}
+}
 
-		if uptime.monitorType != monitorType {
-			t.Error("Monitor type is not the same")
+func TestMonitorServiceProxySetupFailure(t *testing.T) {
+	proxy := (&MonitorServiceProxy{}).OfType(TypeWebhook)
+	if err := proxy.Setup(config.Provider{Name: TypeWebhook, ID: "hooks"}); err == nil {
+		t.Fatal("Expected the setup of a webhook provider without url to fail")
+	}
+	if err := proxy.Healthy(); err == nil || !strings.Contains(err.Error(), "provider hooks is not ready") {
+		t.Errorf("Expected the provider not to be ready, got %v", err)
+	}
+
+	// The retry waits for the backoff to pass
+	lastSetup := proxy.lastSetup
+	if err := proxy.RetrySetup(); err == nil || proxy.lastSetup != lastSetup {
+		t.Error("Expected the setup not to be retried before the backoff passed")
+	}
+
+	proxy.provider.WebhookConfig.URL = "http://localhost/monitors"
+	proxy.lastSetup = time.Now().Add(-setupRetryInitialInterval)
+	if err := proxy.RetrySetup(); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if err := proxy.Healthy(); err != nil {
+		t.Errorf("Expected the provider to be ready, got %v", err)
+	}
+}
+
+func TestMonitorServiceProxySetupRetryDelay(t *testing.T) {
+	proxy := &MonitorServiceProxy{}
+	expected := map[int]time.Duration{
+		1:  5 * time.Second,
+		2:  10 * time.Second,
+		4:  40 * time.Second,
+		20: 5 * time.Minute,
+	}
+	for failures, delay := range expected {
+		proxy.setupFailures = failures
+		if got := proxy.setupRetryDelay(); got != delay {
+			t.Errorf("Expected a delay of %v after %d failures, got %v", delay, failures, got)
 		}
-	})
+	}
+}
+
+func TestConfigRetrier(t *testing.T) {
+	loadErr := errors.New("config secret not found")
+	loaded := false
+	retrier := NewConfigRetrier(func() error { return loadErr }, loadErr, func() { loaded = true })
+	if err := retrier.ReadyzCheck(nil); err != loadErr {
+		t.Errorf("Expected the config not to be ready, got %v", err)
+	}
+
+	// The retry waits for the backoff to pass
+	lastLoad := retrier.lastLoad
+	if retrier.retry(); retrier.lastLoad != lastLoad {
+		t.Error("Expected the config not to be loaded before the backoff passed")
+	}
+	retrier.lastLoad = time.Now().Add(-setupRetryInitialInterval)
+	if retrier.retry(); retrier.failures != 2 || loaded {
+		t.Errorf("Expected the config to fail to load again, got %d failures", retrier.failures)
+	}
+
+	loadErr = nil
+	retrier.lastLoad = time.Now().Add(-2 * setupRetryInitialInterval)
+	retrier.retry()
+	if err := retrier.ReadyzCheck(nil); err != nil || !loaded {
+		t.Errorf("Expected the config to be loaded and the providers to be set up, got %v", err)
+	}
+}
+
+// fakeMonitorService keeps monitors in memory and only implements the required interface
+type fakeMonitorService struct {
+	monitors []models.Monitor
+	updated  []models.Monitor
+}
+
+func (f *fakeMonitorService) GetAll() ([]models.Monitor, error) { return f.monitors, nil }
+func (f *fakeMonitorService) Add(m models.Monitor)              {}
+func (f *fakeMonitorService) Update(m models.Monitor)           { f.updated = append(f.updated, m) }
+func (f *fakeMonitorService) GetByName(name string) (*models.Monitor, error) {
+	return nil, nil
+}
+func (f *fakeMonitorService) Remove(m models.Monitor)       {}
+func (f *fakeMonitorService) Setup(p config.Provider) error { return nil }
+func (f *fakeMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
+	return false
+}
+
+func TestMonitorServiceProxyGetByIDFallsBackToGetAll(t *testing.T) {
+	proxy := &MonitorServiceProxy{monitor: &fakeMonitorService{monitors: []models.Monitor{
+		{ID: "1", Name: "frontend-shop"},
+		{ID: "2", Name: "backend-shop"},
+	}}}
+
+	monitor, err := proxy.GetByID("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor == nil || monitor.Name != "backend-shop" {
+		t.Errorf("Expected monitor backend-shop, got %v", monitor)
+	}
+
+	monitor, err = proxy.GetByID("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor != nil {
+		t.Errorf("Expected no monitor, got %v", monitor)
+	}
+}
+
+// fakeConnectionChecker fails its connectivity check with err
+type fakeConnectionChecker struct {
+	fakeMonitorService
+	err error
+}
+
+func (f *fakeConnectionChecker) CheckConnection() error { return f.err }
+
+func TestMonitorServiceProxyCheckConnection(t *testing.T) {
+	checker := &fakeConnectionChecker{err: errors.New("401 Unauthorized")}
+	proxy := &MonitorServiceProxy{monitorType: TypeUptimeRobot, id: "retail", monitor: checker}
+
+	if err := proxy.CheckConnection(); err == nil || !strings.Contains(err.Error(), "provider retail is not reachable: 401 Unauthorized") {
+		t.Errorf("Expected the connectivity check to fail, got %v", err)
+	}
+	if err := proxy.Ready(); err == nil {
+		t.Error("Expected the provider not to be ready")
+	}
+	if proxy.Healthy() != nil {
+		t.Error("Expected the provider to stay set up")
+	}
+
+	checker.err = nil
+	if err := proxy.CheckConnection(); err != nil {
+		t.Errorf("Expected the connectivity check to succeed, got %v", err)
+	}
+	if err := proxy.Ready(); err != nil {
+		t.Errorf("Expected the provider to be ready, got %v", err)
+	}
+
+	// Providers without a connectivity check only report their setup
+	proxy = &MonitorServiceProxy{monitorType: TypeWebhook, monitor: &fakeMonitorService{}}
+	if err := proxy.CheckConnection(); err != nil {
+		t.Errorf("Expected the connectivity check to succeed, got %v", err)
+	}
+}
+
+// fakeMonitorPauser pauses monitors without updating their config
+type fakeMonitorPauser struct {
+	fakeMonitorService
+	paused []models.Monitor
+}
+
+func (f *fakeMonitorPauser) Pause(m models.Monitor) error {
+	f.paused = append(f.paused, m)
+	return nil
+}
+
+func TestMonitorServiceProxyPause(t *testing.T) {
+	pauser := &fakeMonitorPauser{}
+	proxy := &MonitorServiceProxy{monitorType: TypeUptimeRobot, monitor: pauser}
+	if err := proxy.Pause(models.Monitor{ID: "1", Name: "frontend-shop"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(pauser.paused) != 1 || len(pauser.updated) != 0 {
+		t.Errorf("Expected the monitor to be paused without an update, got %v", pauser.paused)
+	}
+
+	// Providers without Pause are updated with a paused config, leaving the config of the spec untouched
+	service := &fakeMonitorService{}
+	proxy = &MonitorServiceProxy{monitorType: TypeStatusCake, monitor: service}
+	providerConfig := &endpointmonitorv1alpha1.StatusCakeConfig{CheckRate: 300}
+	if err := proxy.Pause(models.Monitor{ID: "2", Name: "backend-shop", Config: providerConfig}); err != nil {
+		t.Fatal(err)
+	}
+	if len(service.updated) != 1 {
+		t.Fatalf("Expected the monitor to be updated, got %v", service.updated)
+	}
+	paused := service.updated[0].Config.(*endpointmonitorv1alpha1.StatusCakeConfig)
+	if !paused.Paused || paused.CheckRate != 300 || providerConfig.Paused {
+		t.Errorf("Expected a paused copy of the config, got %+v", paused)
+	}
+
+	proxy = &MonitorServiceProxy{monitorType: TypeWebhook, monitor: &fakeMonitorService{}}
+	if err := proxy.Pause(models.Monitor{ID: "3", Name: "blog"}); !errors.Is(err, ErrPauseNotSupported) {
+		t.Errorf("Expected pausing to be unsupported, got %v", err)
+	}
+}
+
+func TestMonitorServiceProxySupportsRename(t *testing.T) {
+	if !(&MonitorServiceProxy{monitorType: TypeUptimeRobot}).SupportsRename() {
+		t.Error("UptimeRobot monitors should support renames")
+	}
+	if (&MonitorServiceProxy{monitorType: TypeAppInsights}).SupportsRename() {
+		t.Error("AppInsights monitors are identified by name and can't be renamed")
+	}
+}
+
+func TestValidateProviderAccounts(t *testing.T) {
+	valid := []config.Provider{
+		{Name: TypeUptimeRobot, ID: "retail"},
+		{Name: TypeUptimeRobot, ID: "payments", Default: true},
+		{Name: TypeStatusCake},
+	}
+	if err := validateProviderAccounts(valid); err != nil {
+		t.Errorf("Expected providers to be valid, got %v", err)
+	}
+
+	invalid := map[string][]config.Provider{
+		"missing id":       {{Name: TypeUptimeRobot, ID: "retail"}, {Name: TypeUptimeRobot}},
+		"duplicate id":     {{Name: TypeUptimeRobot, ID: "retail"}, {Name: TypeStatusCake, ID: "retail"}},
+		"multiple default": {{Name: TypeUptimeRobot, ID: "retail", Default: true}, {Name: TypeUptimeRobot, ID: "payments", Default: true}},
+	}
+	for name, providers := range invalid {
+		if err := validateProviderAccounts(providers); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestMarkDefaultAccounts(t *testing.T) {
+	providers := []config.Provider{
+		{Name: TypeUptimeRobot, ID: "retail"},
+		{Name: TypeUptimeRobot, ID: "payments", Default: true},
+		{Name: TypeStatusCake, ID: "retail-statuscake"},
+		{Name: TypeStatusCake, ID: "payments-statuscake"},
+	}
+	monitorServices := []*MonitorServiceProxy{}
+	for _, provider := range providers {
+		monitorServices = append(monitorServices, &MonitorServiceProxy{monitorType: provider.Name, id: provider.ID})
+	}
+
+	markDefaultAccounts(monitorServices, providers)
+
+	expected := []bool{false, true, true, false}
+	for index, monitorService := range monitorServices {
+		if monitorService.IsDefault() != expected[index] {
+			t.Errorf("Expected default of %s to be %v", monitorService.GetID(), expected[index])
+		}
+	}
+	if (&MonitorServiceProxy{monitorType: TypeStatusCake}).GetID() != TypeStatusCake {
+		t.Error("Accounts without an id should be identified by their type")
+	}
 }
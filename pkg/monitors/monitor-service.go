@@ -1,8 +1,10 @@
 package monitors
 
 import (
+	"fmt"
 	"strings"
 
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
 	"github.com/stakater/IngressMonitorController/v2/pkg/config"
 	"github.com/stakater/IngressMonitorController/v2/pkg/models"
 )
@@ -13,30 +15,129 @@ type MonitorService interface {
 	Update(m models.Monitor)
 	GetByName(name string) (*models.Monitor, error)
 	Remove(m models.Monitor)
-	Setup(p config.Provider)
+	Setup(p config.Provider) error
 	Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool
 }
 
+// MonitorByIDGetter is implemented by providers that can fetch a single monitor by its ID,
+// other providers are searched through GetAll
+type MonitorByIDGetter interface {
+	GetByID(id string) (*models.Monitor, error)
+}
+
+// ConnectionChecker is implemented by providers that can verify their credentials with a cheap
+// authenticated call
+type ConnectionChecker interface {
+	CheckConnection() error
+}
+
+// MonitorPauser is implemented by providers that can pause a monitor without changing its config,
+// the config of the other providers is updated instead when it supports pausing
+type MonitorPauser interface {
+	Pause(m models.Monitor) error
+}
+
+// CheckTypeSupporter is implemented by providers that can run checks other than HTTP, the other
+// providers only run HTTP checks
+type CheckTypeSupporter interface {
+	SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool
+}
+
+// CertificateMonitorer is implemented by providers that alert on the expiry of certificates through
+// dedicated monitors, separate from the monitor of the endpoint
+type CertificateMonitorer interface {
+	EnsureCertificateMonitor(m models.Monitor, id string, alertDaysBefore int) (string, error)
+	RemoveCertificateMonitor(id string) error
+}
+
+// MonitorLinker is implemented by providers that can link to the dashboard page or public status page
+// of a monitor
+type MonitorLinker interface {
+	MonitorLinks(m models.Monitor) (models.MonitorLinks, error)
+}
+
+// ProbeReporter is implemented by providers that probe the endpoints from inside the cluster, which
+// report the result of the last probe of a monitor
+type ProbeReporter interface {
+	ProbeResult(name string) *models.ProbeResult
+}
+
+// CreateMonitorService sets up the monitor service of a provider. It is returned even when the
+// setup fails, the error is then reported through Healthy until a retry succeeds.
 func CreateMonitorService(p *config.Provider) *MonitorServiceProxy {
 	monitorService := (&MonitorServiceProxy{}).OfType(p.Name)
-	monitorService.monitorType = p.Name
-	monitorService.Setup(*p)
-	return &monitorService
+	_ = monitorService.Setup(*p)
+	return monitorService
 }
 
-func SetupMonitorServicesForProviders(providers []config.Provider) []*MonitorServiceProxy {
+// SetupMonitorServicesForProviders sets up the monitor services of the providers in the controller config,
+// an error is only returned when the accounts of the providers can't be told apart
+func SetupMonitorServicesForProviders(providers []config.Provider) ([]*MonitorServiceProxy, error) {
 	if len(providers) < 1 {
-		panic("Cannot Instantiate controller with no providers")
+		log.Info("No providers configured, only MonitorProviders and ClusterMonitorProviders can be used")
+	}
+
+	if err := validateProviderAccounts(providers); err != nil {
+		return nil, err
 	}
 
 	monitorServices := []*MonitorServiceProxy{}
 
 	for index := 0; index < len(providers); index++ {
 		monitorServices = append(monitorServices, CreateMonitorService(&providers[index]))
-		log.Info("Configuration added for " + providers[index].Name)
+		log.Info("Configuration added for "+providers[index].Name, "id", providers[index].ID)
 	}
+	markDefaultAccounts(monitorServices, providers)
 
-	return monitorServices
+	return monitorServices, nil
+}
+
+// validateProviderAccounts checks that every account can be told apart when several providers
+// of the same type are configured
+func validateProviderAccounts(providers []config.Provider) error {
+	accounts := map[string]int{}
+	defaults := map[string]int{}
+	ids := map[string]bool{}
+	for _, provider := range providers {
+		accounts[provider.Name]++
+		if provider.Default {
+			defaults[provider.Name]++
+		}
+		if len(provider.ID) > 0 {
+			if ids[provider.ID] {
+				return fmt.Errorf("provider id %s is used more than once", provider.ID)
+			}
+			ids[provider.ID] = true
+		}
+	}
+
+	for _, provider := range providers {
+		if accounts[provider.Name] > 1 && len(provider.ID) == 0 {
+			return fmt.Errorf("providers of type %s must set an id when more than one is configured", provider.Name)
+		}
+		if defaults[provider.Name] > 1 {
+			return fmt.Errorf("only one provider of type %s can be the default", provider.Name)
+		}
+	}
+	return nil
+}
+
+// markDefaultAccounts marks the default account of each provider type, which is the one with
+// default set or otherwise the first one configured
+func markDefaultAccounts(monitorServices []*MonitorServiceProxy, providers []config.Provider) {
+	hasDefault := map[string]bool{}
+	for index, monitorService := range monitorServices {
+		if providers[index].Default {
+			monitorService.isDefault = true
+			hasDefault[monitorService.GetType()] = true
+		}
+	}
+	for _, monitorService := range monitorServices {
+		if !hasDefault[monitorService.GetType()] {
+			monitorService.isDefault = true
+			hasDefault[monitorService.GetType()] = true
+		}
+	}
 }
 
 func SetupMonitorServicesForProvidersTest(providers []config.Provider) []*MonitorServiceProxy {
@@ -48,13 +149,16 @@ func SetupMonitorServicesForProvidersTest(providers []config.Provider) []*Monito
 	log.Info("Setting up monitor services for tests(CRDs) for supported providers: " + strings.Join(allowedProviders[:], ","))
 
 	monitorServices := []*MonitorServiceProxy{}
+	allowed := []config.Provider{}
 
 	for index := 0; index < len(providers); index++ {
 		if contains(allowedProviders, providers[index].Name) {
 			monitorServices = append(monitorServices, CreateMonitorService(&providers[index]))
+			allowed = append(allowed, providers[index])
 			log.Info("Configuration added for " + providers[index].Name)
 		}
 	}
+	markDefaultAccounts(monitorServices, allowed)
 
 	return monitorServices
 }
@@ -0,0 +1,132 @@
+package monitors
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SetupRetrier sets up the providers that failed to set up again, each with its own exponential backoff
+type SetupRetrier struct {
+	MonitorServices []*MonitorServiceProxy
+	Interval        time.Duration
+}
+
+// NewSetupRetrier returns a SetupRetrier for the monitor services
+func NewSetupRetrier(monitorServices []*MonitorServiceProxy) *SetupRetrier {
+	return &SetupRetrier{
+		MonitorServices: monitorServices,
+		Interval:        time.Second,
+	}
+}
+
+// Start retries the setup of failing providers until ctx is done, it implements manager.Runnable
+func (r *SetupRetrier) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, monitorService := range r.MonitorServices {
+				_ = monitorService.RetrySetup()
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false since every replica needs ready providers
+func (r *SetupRetrier) NeedLeaderElection() bool {
+	return false
+}
+
+// ConfigRetrier loads the controller config that failed to load again, with the same backoff as the setup of
+// providers. OnLoaded is called once it loads, to set up the providers of the config.
+type ConfigRetrier struct {
+	Load     func() error
+	OnLoaded func()
+	Interval time.Duration
+
+	mu       sync.RWMutex
+	err      error
+	failures int
+	lastLoad time.Time
+}
+
+// NewConfigRetrier returns a ConfigRetrier for a config that failed to load with err, nothing is retried when err
+// is nil
+func NewConfigRetrier(load func() error, err error, onLoaded func()) *ConfigRetrier {
+	retrier := &ConfigRetrier{
+		Load:     load,
+		OnLoaded: onLoaded,
+		Interval: time.Second,
+		err:      err,
+		lastLoad: time.Now(),
+	}
+	if err != nil {
+		retrier.failures = 1
+	}
+	return retrier
+}
+
+// Start loads the config again until it loads or ctx is done, it implements manager.Runnable
+func (r *ConfigRetrier) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if r.Err() == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.retry()
+		}
+	}
+}
+
+// retry loads the config again once its backoff has passed
+func (r *ConfigRetrier) retry() {
+	r.mu.Lock()
+	if time.Since(r.lastLoad) < retryDelay(r.failures) {
+		r.mu.Unlock()
+		return
+	}
+	log.Info("Retrying to load the controller config")
+	r.err = r.Load()
+	r.lastLoad = time.Now()
+	if r.err != nil {
+		r.failures++
+		log.Error(r.err, "Failed to load the controller config", "retryIn", retryDelay(r.failures))
+		r.mu.Unlock()
+		return
+	}
+	r.mu.Unlock()
+
+	log.Info("Loaded the controller config")
+	if r.OnLoaded != nil {
+		r.OnLoaded()
+	}
+}
+
+// Err returns the error the config failed to load with, nil once it loaded
+func (r *ConfigRetrier) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.err
+}
+
+// ReadyzCheck reports the error the config failed to load with, it implements healthz.Checker
+func (r *ConfigRetrier) ReadyzCheck(_ *http.Request) error {
+	return r.Err()
+}
+
+// NeedLeaderElection returns false since every replica needs the config
+func (r *ConfigRetrier) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,61 @@
+package pingdom
+
+import (
+	"encoding/json"
+	"slices"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// pingdomResolutions are the check intervals in minutes accepted by Pingdom
+var pingdomResolutions = []int{1, 5, 15, 30, 60}
+
+// TranslateCheck translates a provider agnostic check into a Pingdom config, along with the fields
+// of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.PingdomConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.PingdomConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			if check.Interval%60 != 0 || !slices.Contains(pingdomResolutions, check.Interval/60) {
+				unsupported = append(unsupported, "interval: must be one of 60, 300, 900, 1800, 3600 seconds")
+				continue
+			}
+			providerConfig.Resolution = check.Interval / 60
+		case "headers":
+			headers, err := json.Marshal(check.Headers)
+			if err != nil {
+				unsupported = append(unsupported, "headers: "+err.Error())
+				continue
+			}
+			providerConfig.RequestHeaders = string(headers)
+		case "bodyAssertions":
+			if len(check.BodyAssertions) > 1 || check.BodyAssertions[0].Type != endpointmonitorv1alpha1.BodyAssertionContains {
+				unsupported = append(unsupported, "bodyAssertions: only one Contains assertion is supported")
+				continue
+			}
+			providerConfig.ShouldContain = check.BodyAssertions[0].Value
+		case "verifyTLS":
+			providerConfig.VerifyCertificate = *check.VerifyTLS
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
+
+// TranslateCertificateAlert translates the alerting on the expiry of a certificate into a Pingdom config,
+// the check is considered down alertDaysBefore days before the expiry
+func TranslateCertificateAlert(alertDaysBefore int) *endpointmonitorv1alpha1.PingdomConfig {
+	return &endpointmonitorv1alpha1.PingdomConfig{
+		VerifyCertificate: true,
+		SSLDownDaysBefore: alertDaysBefore,
+	}
+}
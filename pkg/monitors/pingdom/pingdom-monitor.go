@@ -35,7 +35,12 @@ func (service *PingdomMonitorService) Equal(oldMonitor models.Monitor, newMonito
 	return false
 }
 
-func (service *PingdomMonitorService) Setup(p config.Provider) {
+// MonitorLinks links to the uptime report of the check
+func (service *PingdomMonitorService) MonitorLinks(m models.Monitor) (models.MonitorLinks, error) {
+	return models.MonitorLinks{DashboardURL: "https://my.pingdom.com/app/reports/uptime#check=" + m.ID}, nil
+}
+
+func (service *PingdomMonitorService) Setup(p config.Provider) error {
 	service.apiToken = p.ApiToken
 	service.url = p.ApiURL
 	service.alertContacts = p.AlertContacts
@@ -48,8 +53,17 @@ func (service *PingdomMonitorService) Setup(p config.Provider) {
 		BaseURL:  service.url,
 	})
 	if err != nil {
-		log.Info("Error setting up Monitor Service", "error", err)
+		return fmt.Errorf("error setting up Pingdom client: %w", err)
 	}
+	return nil
+}
+
+// CheckConnection verifies the API token by listing a single check
+func (service *PingdomMonitorService) CheckConnection() error {
+	if _, err := service.client.Checks.List(map[string]string{"limit": "1"}); err != nil {
+		return fmt.Errorf("failed to list Pingdom checks: %w", err)
+	}
+	return nil
 }
 
 func (service *PingdomMonitorService) GetAll() ([]models.Monitor, error) {
@@ -59,8 +73,15 @@ func (service *PingdomMonitorService) GetAll() ([]models.Monitor, error) {
 		return nil, err
 	}
 	for _, mon := range checks {
+		monitorURL := mon.Hostname
+		switch mon.Type.Name {
+		case "tcp":
+			monitorURL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeTCP, mon.Hostname, 0)
+		case "ping":
+			monitorURL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeICMP, mon.Hostname, 0)
+		}
 		monitors = append(monitors, models.Monitor{
-			URL:  mon.Hostname,
+			URL:  monitorURL,
 			ID:   fmt.Sprintf("%v", mon.ID),
 			Name: mon.Name,
 		})
@@ -82,9 +103,9 @@ func (service *PingdomMonitorService) GetByName(name string) (*models.Monitor, e
 }
 
 func (service *PingdomMonitorService) Add(m models.Monitor) {
-	httpCheck := service.createHttpCheck(m)
+	check := service.createCheck(m)
 
-	_, err := service.client.Checks.Create(&httpCheck)
+	_, err := service.client.Checks.Create(check)
 	if err != nil {
 		log.Info(fmt.Sprintf("Error adding Monitor '%s': %v", m.Name, err.Error()))
 	} else {
@@ -93,10 +114,10 @@ func (service *PingdomMonitorService) Add(m models.Monitor) {
 }
 
 func (service *PingdomMonitorService) Update(m models.Monitor) {
-	httpCheck := service.createHttpCheck(m)
+	check := service.createCheck(m)
 	monitorID, _ := strconv.Atoi(m.ID)
 
-	resp, err := service.client.Checks.Update(monitorID, &httpCheck)
+	resp, err := service.client.Checks.Update(monitorID, check)
 	if err != nil {
 		log.Info(fmt.Sprintf("Error updating Monitor '%s': %v", m.Name, err.Error()))
 	} else {
@@ -115,6 +136,49 @@ func (service *PingdomMonitorService) Remove(m models.Monitor) {
 	}
 }
 
+// SupportsCheckType returns whether Pingdom can run the check type, TCP and ping checks are supported
+func (service *PingdomMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType == endpointmonitorv1alpha1.CheckTypeTCP || checkType == endpointmonitorv1alpha1.CheckTypeICMP
+}
+
+// createCheck builds the check for the check type of the monitor, TCP and ping checks share the
+// alerting settings of the HTTP check
+func (service *PingdomMonitorService) createCheck(monitor models.Monitor) pingdom.Check {
+	httpCheck := service.createHttpCheck(monitor)
+	host, port := monitor.Target()
+
+	switch monitor.CheckType() {
+	case endpointmonitorv1alpha1.CheckTypeTCP:
+		return &pingdom.TCPCheck{
+			Name:                     httpCheck.Name,
+			Hostname:                 host,
+			Port:                     port,
+			Resolution:               httpCheck.Resolution,
+			Paused:                   httpCheck.Paused,
+			SendNotificationWhenDown: httpCheck.SendNotificationWhenDown,
+			NotifyWhenBackup:         httpCheck.NotifyWhenBackup,
+			IntegrationIds:           httpCheck.IntegrationIds,
+			Tags:                     httpCheck.Tags,
+			UserIds:                  httpCheck.UserIds,
+			TeamIds:                  httpCheck.TeamIds,
+		}
+	case endpointmonitorv1alpha1.CheckTypeICMP:
+		return &pingdom.PingCheck{
+			Name:                     httpCheck.Name,
+			Hostname:                 host,
+			Resolution:               httpCheck.Resolution,
+			Paused:                   httpCheck.Paused,
+			SendNotificationWhenDown: httpCheck.SendNotificationWhenDown,
+			NotifyWhenBackup:         httpCheck.NotifyWhenBackup,
+			IntegrationIds:           httpCheck.IntegrationIds,
+			Tags:                     httpCheck.Tags,
+			UserIds:                  httpCheck.UserIds,
+			TeamIds:                  httpCheck.TeamIds,
+		}
+	}
+	return &httpCheck
+}
+
 func (service *PingdomMonitorService) createHttpCheck(monitor models.Monitor) pingdom.HttpCheck {
 	httpCheck := pingdom.HttpCheck{}
 	url, err := url.Parse(monitor.URL)
@@ -248,7 +312,11 @@ func (service *PingdomMonitorService) addConfigToHttpCheck(httpCheck *pingdom.Ht
 		}
 	}
 
-	if providerConfig != nil && len(providerConfig.BasicAuthUser) > 0 {
+	if providerConfig != nil && len(providerConfig.BasicAuthUser) > 0 && len(providerConfig.BasicAuthPassword) > 0 {
+		// Password resolved from basicAuthPasswordFrom by the controller
+		httpCheck.Username = providerConfig.BasicAuthUser
+		httpCheck.Password = providerConfig.BasicAuthPassword
+	} else if providerConfig != nil && len(providerConfig.BasicAuthUser) > 0 {
 		// This should be set to the username to set on the httpCheck
 		// Environment variable should define the password
 		// Mounted via a secret; key is the username, value the password
@@ -279,7 +347,10 @@ func (service *PingdomMonitorService) addConfigToHttpCheck(httpCheck *pingdom.Ht
 		// Enable SSL validation
 		httpCheck.VerifyCertificate = &providerConfig.VerifyCertificate
 		// Add post data if exists
-		if len(providerConfig.PostDataEnvVar) > 0 {
+		if len(providerConfig.PostData) > 0 {
+			// Post data resolved from postDataFrom by the controller
+			httpCheck.PostData = providerConfig.PostData
+		} else if len(providerConfig.PostDataEnvVar) > 0 {
 			postDataValue := os.Getenv(providerConfig.PostDataEnvVar)
 			if postDataValue != "" {
 				httpCheck.PostData = postDataValue
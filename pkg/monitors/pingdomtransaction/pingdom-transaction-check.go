@@ -0,0 +1,46 @@
+package pingdomtransaction
+
+import (
+	"slices"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+var (
+	// transactionIntervals are the check intervals in minutes accepted by Pingdom for transaction checks
+	transactionIntervals = []int{5, 10, 20, 60, 720, 1440}
+	// transactionRegions are the regions transaction checks run from
+	transactionRegions = []string{"us-east", "us-west", "eu", "au"}
+)
+
+// TranslateCheck translates a provider agnostic check into a Pingdom transaction config, along with
+// the fields of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.PingdomTransactionConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.PingdomTransactionConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			if check.Interval%60 != 0 || !slices.Contains(transactionIntervals, check.Interval/60) {
+				unsupported = append(unsupported, "interval: must be one of 300, 600, 1200, 3600, 43200, 86400 seconds")
+				continue
+			}
+			providerConfig.Interval = check.Interval / 60
+		case "locations":
+			if len(check.Locations) > 1 || !slices.Contains(transactionRegions, check.Locations[0]) {
+				unsupported = append(unsupported, "locations: must be a single region of us-east, us-west, eu, au")
+				continue
+			}
+			providerConfig.Region = check.Locations[0]
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
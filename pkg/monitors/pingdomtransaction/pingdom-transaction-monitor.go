@@ -45,7 +45,7 @@ func (service *PingdomTransactionMonitorService) Equal(oldMonitor models.Monitor
 	return false
 }
 
-func (service *PingdomTransactionMonitorService) Setup(p config.Provider) {
+func (service *PingdomTransactionMonitorService) Setup(p config.Provider) error {
 	service.apiToken = p.ApiToken
 	service.url = p.ApiURL
 	service.alertContacts = p.AlertContacts
@@ -61,10 +61,19 @@ func (service *PingdomTransactionMonitorService) Setup(p config.Provider) {
 	service.context = context.Background()
 	kubeClient, err := kube.GetClient()
 	if err != nil {
-		log.Error(err, "Error creating kubernetes client")
+		return fmt.Errorf("error creating kubernetes client: %w", err)
 	}
 	service.kubeClient = kubeClient
 	service.namespace = kube.GetCurrentKubernetesNamespace()
+	return nil
+}
+
+// CheckConnection verifies the API token by listing a single transaction check
+func (service *PingdomTransactionMonitorService) CheckConnection() error {
+	if _, _, err := service.client.TMSChecksAPI.GetAllChecks(service.context).Limit("1").Execute(); err != nil {
+		return fmt.Errorf("failed to list Pingdom transaction checks: %w", err)
+	}
+	return nil
 }
 
 func (service *PingdomTransactionMonitorService) GetAll() ([]models.Monitor, error) {
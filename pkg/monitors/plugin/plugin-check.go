@@ -0,0 +1,19 @@
+package plugin
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// TranslateCheck passes a provider agnostic check to the plugin as the `check` field of the monitor
+// config, leaving its interpretation to the receiver
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*runtime.RawExtension, []string) {
+	raw, err := json.Marshal(map[string]interface{}{"check": check})
+	if err != nil {
+		return nil, []string{"check: " + err.Error()}
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
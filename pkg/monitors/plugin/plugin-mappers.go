@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/plugin/pluginpb"
+)
+
+// PluginMonitorToBaseMonitorMapper function to map a plugin monitor to Monitor
+func PluginMonitorToBaseMonitorMapper(pluginMonitor *pluginpb.Monitor) *models.Monitor {
+	var m models.Monitor
+	m.Name = pluginMonitor.GetName()
+	m.URL = pluginMonitor.GetUrl()
+	m.ID = pluginMonitor.GetId()
+
+	if pluginMonitor.GetConfig() != nil {
+		raw, err := protojson.Marshal(pluginMonitor.GetConfig())
+		if err != nil {
+			log.Error(err, "Unable to marshal plugin config for monitor "+m.Name)
+		} else {
+			m.Config = &runtime.RawExtension{Raw: raw}
+		}
+	}
+	return &m
+}
+
+// PluginMonitorsToBaseMonitorsMapper function to map plugin monitors to Monitors
+func PluginMonitorsToBaseMonitorsMapper(pluginMonitors []*pluginpb.Monitor) []models.Monitor {
+	var monitors []models.Monitor
+	for _, pluginMonitor := range pluginMonitors {
+		monitors = append(monitors, *PluginMonitorToBaseMonitorMapper(pluginMonitor))
+	}
+	return monitors
+}
+
+// BaseMonitorToPluginMonitorMapper function to map Monitor to a plugin monitor.
+// The monitor config is sent as a JSON object whatever its Go type is.
+func BaseMonitorToPluginMonitorMapper(m models.Monitor) (*pluginpb.Monitor, error) {
+	pluginMonitor := &pluginpb.Monitor{
+		Id:   m.ID,
+		Name: m.Name,
+		Url:  m.URL,
+	}
+
+	var raw []byte
+	switch config := m.Config.(type) {
+	case nil:
+		return pluginMonitor, nil
+	case *runtime.RawExtension:
+		if config == nil || len(config.Raw) == 0 {
+			return pluginMonitor, nil
+		}
+		raw = config.Raw
+	default:
+		var err error
+		raw, err = json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		if string(raw) == "null" {
+			return pluginMonitor, nil
+		}
+	}
+
+	pluginConfig := &structpb.Struct{}
+	if err := protojson.Unmarshal(raw, pluginConfig); err != nil {
+		return nil, err
+	}
+	pluginMonitor.Config = pluginConfig
+	return pluginMonitor, nil
+}
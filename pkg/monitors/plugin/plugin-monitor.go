@@ -0,0 +1,190 @@
+// Package plugin adds support for out-of-process monitor providers that implement
+// the MonitorService gRPC API defined in pluginpb/monitor_service.proto
+package plugin
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/plugin/pluginpb"
+)
+
+var log = logf.Log.WithName("plugin-monitor")
+
+const (
+	// Default timeout in seconds for a single call to the plugin
+	TimeoutDefaultValue = 30
+)
+
+// PluginMonitorService forwards every MonitorService call to a plugin over gRPC
+type PluginMonitorService struct {
+	address string
+	timeout time.Duration
+	conn    *grpc.ClientConn
+	client  pluginpb.MonitorServiceClient
+}
+
+// Setup dials the plugin endpoint configured for the provider
+func (service *PluginMonitorService) Setup(p config.Provider) error {
+	service.address = p.PluginConfig.Address
+	service.timeout = TimeoutDefaultValue * time.Second
+	if p.PluginConfig.Timeout > 0 {
+		service.timeout = time.Duration(p.PluginConfig.Timeout) * time.Second
+	}
+
+	if len(service.address) == 0 {
+		return fmt.Errorf("plugin address is required for provider %s", p.Name)
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if p.PluginConfig.TLS {
+		transportCredentials = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+
+	conn, err := grpc.NewClient(service.address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return fmt.Errorf("failed to create plugin client for %s: %w", service.address, err)
+	}
+	service.setupWithConn(conn)
+	return nil
+}
+
+// setupWithConn wires the service to an already established connection
+func (service *PluginMonitorService) setupWithConn(conn *grpc.ClientConn) {
+	if service.timeout == 0 {
+		service.timeout = TimeoutDefaultValue * time.Second
+	}
+	service.conn = conn
+	service.client = pluginpb.NewMonitorServiceClient(conn)
+}
+
+func (service *PluginMonitorService) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), service.timeout)
+}
+
+func (service *PluginMonitorService) GetAll() ([]models.Monitor, error) {
+	if service.client == nil {
+		return nil, fmt.Errorf("plugin %s is not connected", service.address)
+	}
+	ctx, cancel := service.context()
+	defer cancel()
+
+	response, err := service.client.GetAll(ctx, &pluginpb.GetAllRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("plugin GetAll failed: %w", err)
+	}
+	return PluginMonitorsToBaseMonitorsMapper(response.GetMonitors()), nil
+}
+
+func (service *PluginMonitorService) GetByName(name string) (*models.Monitor, error) {
+	if service.client == nil {
+		return nil, fmt.Errorf("plugin %s is not connected", service.address)
+	}
+	ctx, cancel := service.context()
+	defer cancel()
+
+	response, err := service.client.GetByName(ctx, &pluginpb.GetByNameRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("plugin GetByName failed for monitor %s: %w", name, err)
+	}
+	if response.GetMonitor() == nil {
+		return nil, nil
+	}
+	return PluginMonitorToBaseMonitorMapper(response.GetMonitor()), nil
+}
+
+func (service *PluginMonitorService) Add(m models.Monitor) {
+	monitor, err := BaseMonitorToPluginMonitorMapper(m)
+	if err != nil {
+		log.Error(err, "Unable to convert monitor "+m.Name)
+		return
+	}
+	if service.client == nil {
+		log.Error(nil, "Plugin is not connected, monitor couldn't be added: "+m.Name)
+		return
+	}
+	ctx, cancel := service.context()
+	defer cancel()
+
+	if _, err := service.client.Add(ctx, &pluginpb.AddRequest{Monitor: monitor}); err != nil {
+		log.Error(err, "Monitor couldn't be added: "+m.Name)
+		return
+	}
+	log.Info("Monitor Added: " + m.Name)
+}
+
+func (service *PluginMonitorService) Update(m models.Monitor) {
+	monitor, err := BaseMonitorToPluginMonitorMapper(m)
+	if err != nil {
+		log.Error(err, "Unable to convert monitor "+m.Name)
+		return
+	}
+	if service.client == nil {
+		log.Error(nil, "Plugin is not connected, monitor couldn't be updated: "+m.Name)
+		return
+	}
+	ctx, cancel := service.context()
+	defer cancel()
+
+	if _, err := service.client.Update(ctx, &pluginpb.UpdateRequest{Monitor: monitor}); err != nil {
+		log.Error(err, "Monitor couldn't be updated: "+m.Name)
+		return
+	}
+	log.Info("Monitor Updated: " + m.Name)
+}
+
+func (service *PluginMonitorService) Remove(m models.Monitor) {
+	monitor, err := BaseMonitorToPluginMonitorMapper(m)
+	if err != nil {
+		log.Error(err, "Unable to convert monitor "+m.Name)
+		return
+	}
+	if service.client == nil {
+		log.Error(nil, "Plugin is not connected, monitor couldn't be removed: "+m.Name)
+		return
+	}
+	ctx, cancel := service.context()
+	defer cancel()
+
+	if _, err := service.client.Remove(ctx, &pluginpb.RemoveRequest{Monitor: monitor}); err != nil {
+		log.Error(err, "Monitor couldn't be removed: "+m.Name)
+		return
+	}
+	log.Info("Monitor Removed: " + m.Name)
+}
+
+// Equal asks the plugin whether the monitors differ. Any failure is treated as a
+// difference so that the monitor gets updated on the next reconcile.
+func (service *PluginMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
+	oldPluginMonitor, err := BaseMonitorToPluginMonitorMapper(oldMonitor)
+	if err != nil {
+		log.Error(err, "Unable to convert monitor "+oldMonitor.Name)
+		return false
+	}
+	newPluginMonitor, err := BaseMonitorToPluginMonitorMapper(newMonitor)
+	if err != nil {
+		log.Error(err, "Unable to convert monitor "+newMonitor.Name)
+		return false
+	}
+	if service.client == nil {
+		return false
+	}
+	ctx, cancel := service.context()
+	defer cancel()
+
+	response, err := service.client.Equal(ctx, &pluginpb.EqualRequest{OldMonitor: oldPluginMonitor, NewMonitor: newPluginMonitor})
+	if err != nil {
+		log.Error(err, "Plugin Equal failed for monitor "+newMonitor.Name)
+		return false
+	}
+	return response.GetEqual()
+}
@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+	"github.com/stakater/IngressMonitorController/v2/pkg/monitors/plugin/pluginpb"
+)
+
+func init() {
+	// To allow normal logging to be printed if tests fails
+	// Dev mode is an extra feature to make output more readable
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+}
+
+// fakePlugin is an in-memory plugin implementation used to exercise the client
+type fakePlugin struct {
+	pluginpb.UnimplementedMonitorServiceServer
+	mu       sync.Mutex
+	nextID   int
+	monitors map[string]*pluginpb.Monitor
+}
+
+func (p *fakePlugin) GetAll(ctx context.Context, req *pluginpb.GetAllRequest) (*pluginpb.GetAllResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	response := &pluginpb.GetAllResponse{}
+	for _, m := range p.monitors {
+		response.Monitors = append(response.Monitors, m)
+	}
+	return response, nil
+}
+
+func (p *fakePlugin) GetByName(ctx context.Context, req *pluginpb.GetByNameRequest) (*pluginpb.GetByNameResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, m := range p.monitors {
+		if m.GetName() == req.GetName() {
+			return &pluginpb.GetByNameResponse{Monitor: m}, nil
+		}
+	}
+	return &pluginpb.GetByNameResponse{}, nil
+}
+
+func (p *fakePlugin) Add(ctx context.Context, req *pluginpb.AddRequest) (*pluginpb.AddResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nextID++
+	m := req.GetMonitor()
+	m.Id = fmt.Sprintf("%d", p.nextID)
+	p.monitors[m.Id] = m
+	return &pluginpb.AddResponse{}, nil
+}
+
+func (p *fakePlugin) Update(ctx context.Context, req *pluginpb.UpdateRequest) (*pluginpb.UpdateResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.monitors[req.GetMonitor().GetId()] = req.GetMonitor()
+	return &pluginpb.UpdateResponse{}, nil
+}
+
+func (p *fakePlugin) Remove(ctx context.Context, req *pluginpb.RemoveRequest) (*pluginpb.RemoveResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.monitors, req.GetMonitor().GetId())
+	return &pluginpb.RemoveResponse{}, nil
+}
+
+func (p *fakePlugin) Equal(ctx context.Context, req *pluginpb.EqualRequest) (*pluginpb.EqualResponse, error) {
+	oldMonitor, newMonitor := req.GetOldMonitor(), req.GetNewMonitor()
+	equal := oldMonitor.GetUrl() == newMonitor.GetUrl() && proto.Equal(oldMonitor.GetConfig(), newMonitor.GetConfig())
+	return &pluginpb.EqualResponse{Equal: equal}, nil
+}
+
+func setupTestService(t *testing.T) *PluginMonitorService {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pluginpb.RegisterMonitorServiceServer(server, &fakePlugin{monitors: map[string]*pluginpb.Monitor{}})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NilError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	service := &PluginMonitorService{address: "bufnet"}
+	service.setupWithConn(conn)
+	return service
+}
+
+func TestPluginMonitorLifecycle(t *testing.T) {
+	service := setupTestService(t)
+	config := &runtime.RawExtension{Raw: []byte(`{"team":"checkout","interval":60}`)}
+
+	service.Add(models.Monitor{Name: "checkout-api", URL: "https://checkout.example.com", Config: config})
+
+	monitor, err := service.GetByName("checkout-api")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor != nil)
+	assert.Equal(t, monitor.URL, "https://checkout.example.com")
+	assert.Equal(t, monitor.ID, "1")
+
+	// The config must survive the round trip through the plugin unchanged
+	desired := models.Monitor{Name: monitor.Name, ID: monitor.ID, URL: monitor.URL, Config: config}
+	assert.Assert(t, service.Equal(*monitor, desired))
+
+	desired.URL = "https://checkout.example.com/healthz"
+	assert.Assert(t, !service.Equal(*monitor, desired))
+	service.Update(desired)
+
+	monitors, err := service.GetAll()
+	assert.NilError(t, err)
+	assert.Equal(t, len(monitors), 1)
+	assert.Equal(t, monitors[0].URL, "https://checkout.example.com/healthz")
+
+	service.Remove(monitors[0])
+	monitor, err = service.GetByName("checkout-api")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor == nil)
+}
+
+func TestPluginMonitorWithoutConnection(t *testing.T) {
+	service := &PluginMonitorService{}
+
+	_, err := service.GetAll()
+	assert.ErrorContains(t, err, "not connected")
+
+	_, err = service.GetByName("missing")
+	assert.ErrorContains(t, err, "not connected")
+
+	assert.Assert(t, !service.Equal(models.Monitor{}, models.Monitor{}))
+}
+
+func TestBaseMonitorToPluginMonitorMapper(t *testing.T) {
+	var tests = []struct {
+		name       string
+		config     interface{}
+		wantConfig bool
+	}{
+		{name: "nil config", config: nil},
+		{name: "empty raw extension", config: &runtime.RawExtension{}},
+		{name: "raw extension", config: &runtime.RawExtension{Raw: []byte(`{"a":"b"}`)}, wantConfig: true},
+		{name: "typed config", config: &struct {
+			Interval int `json:"interval"`
+		}{Interval: 60}, wantConfig: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pluginMonitor, err := BaseMonitorToPluginMonitorMapper(models.Monitor{Name: "test", Config: tt.config})
+			assert.NilError(t, err)
+			assert.Equal(t, pluginMonitor.GetConfig() != nil, tt.wantConfig)
+		})
+	}
+}
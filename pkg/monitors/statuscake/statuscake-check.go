@@ -0,0 +1,66 @@
+package statuscake
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// statusCakeCheckRates are the check intervals in seconds accepted by StatusCake for uptime tests
+var statusCakeCheckRates = []int{30, 60, 300, 900, 1800, 3600, 86400}
+
+// TranslateCheck translates a provider agnostic check into a StatusCake config, along with the fields
+// of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.StatusCakeConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.StatusCakeConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			if !slices.Contains(statusCakeCheckRates, check.Interval) {
+				unsupported = append(unsupported, "interval: must be one of 30, 60, 300, 900, 1800, 3600, 86400 seconds")
+				continue
+			}
+			providerConfig.CheckRate = check.Interval
+		case "timeout":
+			if check.Timeout < 5 || check.Timeout > 75 {
+				unsupported = append(unsupported, "timeout: must be between 5 and 75 seconds")
+				continue
+			}
+			providerConfig.Timeout = check.Timeout
+		case "method":
+			// StatusCake sends a GET, or a POST when there is a body
+			expected := http.MethodGet
+			if len(check.Body) > 0 {
+				expected = http.MethodPost
+			}
+			if check.Method != expected {
+				unsupported = append(unsupported, "method: only GET, or POST with a body, is supported")
+			}
+			continue
+		case "body":
+			providerConfig.RawPostData = check.Body
+		case "bodyAssertions":
+			if len(check.BodyAssertions) > 1 || check.BodyAssertions[0].Type != endpointmonitorv1alpha1.BodyAssertionContains {
+				unsupported = append(unsupported, "bodyAssertions: only one Contains assertion is supported")
+				continue
+			}
+			providerConfig.FindString = check.BodyAssertions[0].Value
+		case "followRedirects":
+			providerConfig.FollowRedirect = *check.FollowRedirects
+		case "locations":
+			providerConfig.Regions = strings.Join(check.Locations, ",")
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
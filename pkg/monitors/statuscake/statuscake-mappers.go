@@ -13,6 +13,9 @@ func StatusCakeMonitorMonitorToBaseMonitorMapper(statuscakeData StatusCakeMonito
 	var m models.Monitor
 	m.Name = statuscakeData.WebsiteName
 	m.URL = statuscakeData.WebsiteURL
+	if strings.EqualFold(statuscakeData.TestType, "PING") {
+		m.URL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeICMP, statuscakeData.WebsiteURL, 0)
+	}
 	m.ID = statuscakeData.TestID
 
 	var providerConfig endpointmonitorv1alpha1.StatusCakeConfig
@@ -50,6 +53,7 @@ func StatusCakeHeartbeatToBaseMonitorMapper(hb statuscake.HeartbeatTestOverview)
 	m.Name = hb.Name
 	m.URL = hb.WebsiteURL
 	m.ID = hb.ID
+	m.Heartbeat = &models.Heartbeat{Period: int(hb.Period)}
 
 	var providerConfig endpointmonitorv1alpha1.StatusCakeConfig
 	providerConfig.TestType = "Heartbeat"
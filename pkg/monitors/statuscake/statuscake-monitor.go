@@ -2,6 +2,8 @@ package statuscake
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,7 +27,20 @@ import (
 )
 
 var log = logf.Log.WithName("statuscake-monitor")
-var rateLimiter = rate.NewLimiter(5, 1) // Allow 5 requests per second
+
+// Bounds and default of the period of heartbeat tests in seconds
+const (
+	minHeartbeatPeriod     = 30
+	maxHeartbeatPeriod     = 172800
+	defaultHeartbeatPeriod = 300
+)
+
+// secretsTagPrefix starts the tag holding a hash of the sensitive fields of an uptime test, which the
+// StatusCake API doesn't return, so that their rotation is detected
+const secretsTagPrefix = "imc-secrets:"
+
+// requestsPerSecond is the rate at which requests are sent to the StatusCake API by each account
+const requestsPerSecond = 5
 
 // StatusCakeMonitorService is the service structure for StatusCake
 type StatusCakeMonitorService struct {
@@ -34,16 +49,18 @@ type StatusCakeMonitorService struct {
 	username string
 	cgroup   string
 	client   *http.Client
+	// rateLimiter is per service so that every StatusCake account gets its own request budget
+	rateLimiter *rate.Limiter
 }
 
 func (monitor *StatusCakeMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
 	// Since there is a discrepency between the fields in the endpointmonitor CR and the statuscake API
 	// use the tags to define a last updated by tags. This ensures we are not ratelimited by statuscake.
-	oldConf := oldMonitor.Config.(*endpointmonitorv1alpha1.StatusCakeConfig)
-	newConf := newMonitor.Config.(*endpointmonitorv1alpha1.StatusCakeConfig)
+	oldConf := statusCakeConfig(oldMonitor)
+	newConf := statusCakeConfig(newMonitor)
 
 	if isHeartbeat(newMonitor) {
-		if oldConf.TestTags != newConf.TestTags || oldConf.CheckRate != newConf.CheckRate ||
+		if oldConf.TestTags != newConf.TestTags || heartbeatPeriod(oldMonitor) != heartbeatPeriod(newMonitor) ||
 			oldConf.Paused != newConf.Paused || oldConf.ContactGroup != newConf.ContactGroup {
 			log.Info("Heartbeat monitor configuration changed, updating...",
 				"name", newMonitor.Name)
@@ -52,20 +69,74 @@ func (monitor *StatusCakeMonitorService) Equal(oldMonitor models.Monitor, newMon
 		return true
 	}
 
-	if oldConf.TestTags != newConf.TestTags {
+	oldTags, oldSecretsHash := splitSecretsTag(oldConf.TestTags)
+	if oldTags != newConf.TestTags {
 		msg := "Found a difference between the old TestTags and new TestTags. Updating the UptimeCheck..."
-		log.Info(msg, "Old Tags", oldConf.TestTags, "New Tags", newConf.TestTags)
+		log.Info(msg, "Old Tags", oldTags, "New Tags", newConf.TestTags)
+		return false
+	}
+	if oldSecretsHash != secretsHash(newConf) {
+		log.Info("Sensitive fields changed, updating the UptimeCheck...", "name", newMonitor.Name)
 		return false
 	}
 	return true
 }
 
-// isHeartbeat returns true when the monitor config specifies TestType "Heartbeat"
+// secretsHash returns a hash of the sensitive fields of the config, which may be read from Secrets, or
+// an empty string without any
+func secretsHash(providerConfig *endpointmonitorv1alpha1.StatusCakeConfig) string {
+	if len(providerConfig.BasicAuthPassword) == 0 && len(providerConfig.FindString) == 0 && len(providerConfig.RawPostData) == 0 {
+		return ""
+	}
+	fields, _ := json.Marshal([]string{providerConfig.BasicAuthPassword, providerConfig.FindString, providerConfig.RawPostData})
+	sum := sha256.Sum256(fields)
+	return hex.EncodeToString(sum[:8])
+}
+
+// splitSecretsTag splits the hash of the sensitive fields off the comma separated tags of a test
+func splitSecretsTag(testTags string) (string, string) {
+	var tags []string
+	var hash string
+	for _, tag := range strings.Split(testTags, ",") {
+		if strings.HasPrefix(tag, secretsTagPrefix) {
+			hash = strings.TrimPrefix(tag, secretsTagPrefix)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return strings.Join(tags, ","), hash
+}
+
+// SupportsCheckType returns whether StatusCake can run the check type, TCP, PING and heartbeat tests are
+// supported while DNS tests need the expected records
+func (monitor *StatusCakeMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType == endpointmonitorv1alpha1.CheckTypeTCP || checkType == endpointmonitorv1alpha1.CheckTypeICMP ||
+		checkType == endpointmonitorv1alpha1.CheckTypeHeartbeat
+}
+
+// statusCakeConfig returns the StatusCake config of the monitor, empty when it has none
+func statusCakeConfig(m models.Monitor) *endpointmonitorv1alpha1.StatusCakeConfig {
+	if cfg, ok := m.Config.(*endpointmonitorv1alpha1.StatusCakeConfig); ok && cfg != nil {
+		return cfg
+	}
+	return &endpointmonitorv1alpha1.StatusCakeConfig{}
+}
+
+// isHeartbeat returns true for heartbeat monitors and when the monitor config specifies TestType "Heartbeat"
 func isHeartbeat(m models.Monitor) bool {
-	if cfg, ok := m.Config.(*endpointmonitorv1alpha1.StatusCakeConfig); ok {
-		return strings.EqualFold(cfg.TestType, "Heartbeat")
+	return m.Heartbeat != nil || strings.EqualFold(statusCakeConfig(m).TestType, "Heartbeat")
+}
+
+// heartbeatPeriod returns the time in seconds without a ping after which a heartbeat test alerts. checkRate
+// takes precedence over the schedule of the job, which is given the grace period to ping the test.
+func heartbeatPeriod(m models.Monitor) int {
+	if checkRate := statusCakeConfig(m).CheckRate; checkRate > 0 {
+		return checkRate
 	}
-	return false
+	if m.Heartbeat != nil {
+		return m.Heartbeat.Period + m.Heartbeat.Grace
+	}
+	return defaultHeartbeatPeriod
 }
 
 // buildHeartbeatForm builds the form values for the heartbeat create/update API
@@ -75,13 +146,10 @@ func buildHeartbeatForm(m models.Monitor, cgroup string) url.Values {
 
 	providerConfig, _ := m.Config.(*endpointmonitorv1alpha1.StatusCakeConfig)
 
-	period := 300
-	if providerConfig != nil && providerConfig.CheckRate > 0 {
-		if providerConfig.CheckRate >= 30 && providerConfig.CheckRate <= 172800 {
-			period = providerConfig.CheckRate
-		} else {
-			log.Error(nil, fmt.Sprintf("checkRate %d is out of the valid range for heartbeat monitors (30–172800 seconds), using default 300", providerConfig.CheckRate))
-		}
+	period := heartbeatPeriod(m)
+	if period < minHeartbeatPeriod || period > maxHeartbeatPeriod {
+		log.Error(nil, fmt.Sprintf("period %d is out of the valid range for heartbeat monitors (30–172800 seconds), using default 300", period))
+		period = defaultHeartbeatPeriod
 	}
 	f.Add("period", strconv.Itoa(period))
 
@@ -112,19 +180,32 @@ func buildHeartbeatForm(m models.Monitor, cgroup string) url.Values {
 func buildUpsertForm(m models.Monitor, cgroup string) url.Values {
 	f := url.Values{}
 	f.Add("name", m.Name)
-	unEscapedURL, _ := url.QueryUnescape(m.URL)
-	f.Add("website_url", unEscapedURL)
 
 	// Retrieve provider configuration
 	providerConfig, _ := m.Config.(*endpointmonitorv1alpha1.StatusCakeConfig)
 
+	// TCP and PING tests take the host as website url
+	checkType := m.CheckType()
+	host, port := m.Target()
+	if checkType == endpointmonitorv1alpha1.CheckTypeHTTP {
+		unEscapedURL, _ := url.QueryUnescape(m.URL)
+		f.Add("website_url", unEscapedURL)
+	} else {
+		f.Add("website_url", host)
+	}
+
 	if providerConfig != nil && providerConfig.CheckRate > 0 {
 		f.Add("check_rate", strconv.Itoa(providerConfig.CheckRate))
 	} else {
 		f.Add("check_rate", "300")
 	}
 
-	if providerConfig != nil && len(providerConfig.TestType) > 0 {
+	if checkType == endpointmonitorv1alpha1.CheckTypeTCP {
+		f.Add("test_type", "TCP")
+		f.Add("port", strconv.Itoa(port))
+	} else if checkType == endpointmonitorv1alpha1.CheckTypeICMP {
+		f.Add("test_type", "PING")
+	} else if providerConfig != nil && len(providerConfig.TestType) > 0 {
 		f.Add("test_type", providerConfig.TestType)
 	} else {
 		f.Add("test_type", "HTTP")
@@ -150,6 +231,11 @@ func buildUpsertForm(m models.Monitor, cgroup string) url.Values {
 			f.Add("tags[]", testTag)
 		}
 	}
+	if providerConfig != nil {
+		if hash := secretsHash(providerConfig); len(hash) > 0 {
+			f.Add("tags[]", secretsTagPrefix+hash)
+		}
+	}
 
 	if providerConfig != nil && len(providerConfig.Regions) > 0 {
 		regions := convertStringToArray(providerConfig.Regions)
@@ -158,7 +244,12 @@ func buildUpsertForm(m models.Monitor, cgroup string) url.Values {
 		}
 	}
 
-	if providerConfig != nil && len(providerConfig.BasicAuthUser) > 0 {
+	if providerConfig != nil && len(providerConfig.BasicAuthUser) > 0 && len(providerConfig.BasicAuthPassword) > 0 {
+		// Password resolved from basicAuthPasswordFrom by the controller
+		f.Add("basic_username", providerConfig.BasicAuthUser)
+		f.Add("basic_password", providerConfig.BasicAuthPassword)
+		log.Info("Basic auth requirement detected. Setting username and password")
+	} else if providerConfig != nil && len(providerConfig.BasicAuthUser) > 0 {
 		// This value is mandatory
 		// Environment variable should define the password
 		// Mounted via a secret; key is the username, value is the password
@@ -283,7 +374,7 @@ func buildUpsertForm(m models.Monitor, cgroup string) url.Values {
 	if providerConfig != nil && providerConfig.TriggerRate > 0 {
 		f.Add("trigger_rate", strconv.Itoa(providerConfig.TriggerRate))
 	}
-	if providerConfig != nil && providerConfig.Port > 0 {
+	if providerConfig != nil && providerConfig.Port > 0 && checkType != endpointmonitorv1alpha1.CheckTypeTCP {
 		f.Add("port", strconv.Itoa(providerConfig.Port))
 	}
 	if providerConfig != nil && providerConfig.Confirmation > 0 {
@@ -321,13 +412,23 @@ func convertStringToArray(stringValues string) []string {
 	return stringArray
 }
 
+// MonitorLinks links to the status page of the test in the dashboard
+func (service *StatusCakeMonitorService) MonitorLinks(m models.Monitor) (models.MonitorLinks, error) {
+	if isHeartbeat(m) {
+		return models.MonitorLinks{DashboardURL: "https://app.statuscake.com/HeartbeatStatus.php?tid=" + m.ID}, nil
+	}
+	return models.MonitorLinks{DashboardURL: "https://app.statuscake.com/UptimeStatus.php?tid=" + m.ID}, nil
+}
+
 // Setup function is used to initialise the StatusCake service
-func (service *StatusCakeMonitorService) Setup(p config.Provider) {
+func (service *StatusCakeMonitorService) Setup(p config.Provider) error {
 	service.apiKey = p.ApiKey
 	service.url = p.ApiURL
 	service.username = p.Username
 	service.cgroup = p.AlertContacts
 	service.client = &http.Client{}
+	service.rateLimiter = rate.NewLimiter(requestsPerSecond, 1)
+	return nil
 }
 
 // GetByName function will Get a monitor by it's name
@@ -451,7 +552,7 @@ func (service *StatusCakeMonitorService) doRequest(req *http.Request) (*http.Res
 
 func (service *StatusCakeMonitorService) doRequestWithRetries(req *http.Request, attempt int) (*http.Response, error) {
 	// Wait for the rate limiter to allow a request
-	err := rateLimiter.Wait(req.Context())
+	err := service.rateLimiter.Wait(req.Context())
 	if err != nil {
 		log.Error(err, "Rate limiter wait failed")
 		return nil, err
@@ -497,7 +598,7 @@ func (service *StatusCakeMonitorService) GetAll() ([]models.Monitor, error) {
 	var uptimeData []StatusCakeMonitorData
 	page := 1
 	for {
-		res, err := service.fetchMonitors(page)
+		res, err := service.fetchMonitors(page, 100)
 		if err != nil {
 			return nil, err
 		}
@@ -566,14 +667,20 @@ func (service *StatusCakeMonitorService) fetchHeartbeatMonitors(page int) (*Stat
 	return &result, nil
 }
 
-func (service *StatusCakeMonitorService) fetchMonitors(page int) (*StatusCakeMonitor, error) {
+// CheckConnection verifies the API key by listing a single uptime check
+func (service *StatusCakeMonitorService) CheckConnection() error {
+	_, err := service.fetchMonitors(1, 1)
+	return err
+}
+
+func (service *StatusCakeMonitorService) fetchMonitors(page int, limit int) (*StatusCakeMonitor, error) {
 	u, err := url.Parse(service.url)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse StatusCake URL: %w", err)
 	}
 	u.Path = "/v1/uptime/"
 	query := u.Query()
-	query.Add("limit", "100")
+	query.Add("limit", strconv.Itoa(limit))
 	query.Add("page", strconv.Itoa(page))
 	u.RawQuery = query.Encode()
 	u.Scheme = "https"
@@ -639,7 +746,7 @@ func (service *StatusCakeMonitorService) Add(m models.Monitor) {
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
 			log.Error(err, "Unable to read response")
-			os.Exit(1)
+			return
 		}
 		log.Error(nil, "Insert Request failed for name: "+m.Name+" with status code "+strconv.Itoa(resp.StatusCode))
 		log.Error(nil, string(bodyBytes))
@@ -713,7 +820,7 @@ func (service *StatusCakeMonitorService) Update(m models.Monitor) {
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
 			log.Error(err, "Unable to read response")
-			os.Exit(1)
+			return
 		}
 		log.Error(nil, "Update Request failed for name: "+m.Name+" with status code "+strconv.Itoa(resp.StatusCode))
 		log.Error(nil, string(bodyBytes))
@@ -2,6 +2,7 @@ package statuscake
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -316,3 +317,73 @@ func TestBuildUpsertForm(t *testing.T) {
 	assert.Equal(t, "1", vals.Get("trigger_rate"))
 	assert.Equal(t, "30", vals.Get("timeout"))
 }
+
+func TestEqualDetectsRotatedSecrets(t *testing.T) {
+	service := &StatusCakeMonitorService{}
+	desired := models.Monitor{Name: "checkout", URL: "https://checkout.example.com", Config: &endpointmonitorv1alpha1.StatusCakeConfig{
+		TestTags: "prod", BasicAuthUser: "monitor", BasicAuthPassword: "s3cret",
+	}}
+
+	// The test read back from StatusCake only holds the tags sent with it, not the password
+	tags := strings.Join(buildUpsertForm(desired, "")["tags[]"], ",")
+	assert.Assert(t, strings.HasPrefix(tags, "prod,"+secretsTagPrefix), tags)
+	assert.Assert(t, !strings.Contains(tags, "s3cret"))
+	created := models.Monitor{Name: "checkout", URL: "https://checkout.example.com", Config: &endpointmonitorv1alpha1.StatusCakeConfig{TestTags: tags}}
+	assert.Assert(t, service.Equal(created, desired))
+
+	// A password rotated in its Secret is pushed to StatusCake
+	rotated := desired
+	rotated.Config = &endpointmonitorv1alpha1.StatusCakeConfig{TestTags: "prod", BasicAuthUser: "monitor", BasicAuthPassword: "rotated"}
+	assert.Assert(t, !service.Equal(created, rotated))
+
+	// So is the removal of the sensitive fields
+	rotated.Config = &endpointmonitorv1alpha1.StatusCakeConfig{TestTags: "prod"}
+	assert.Assert(t, !service.Equal(created, rotated))
+}
+
+func TestTranslateCheck(t *testing.T) {
+	followRedirects := true
+	providerConfig, unsupported := TranslateCheck(&endpointmonitorv1alpha1.Check{
+		Interval:            60,
+		Timeout:             90,
+		Method:              "POST",
+		Body:                "user=monitor",
+		Headers:             map[string]string{"X-Monitor": "imc"},
+		BodyAssertions:      []endpointmonitorv1alpha1.BodyAssertion{{Type: endpointmonitorv1alpha1.BodyAssertionContains, Value: "welcome"}},
+		FollowRedirects:     &followRedirects,
+		Locations:           []string{"uk1", "us2"},
+		ExpectedStatusCodes: []int{200},
+	})
+	assert.DeepEqual(t, *providerConfig, endpointmonitorv1alpha1.StatusCakeConfig{
+		CheckRate:      60,
+		RawPostData:    "user=monitor",
+		FindString:     "welcome",
+		FollowRedirect: true,
+		Regions:        "uk1,us2",
+	})
+	assert.DeepEqual(t, unsupported, []string{"timeout: must be between 5 and 75 seconds", "headers", "expectedStatusCodes"})
+
+	providerConfig, unsupported = TranslateCheck(&endpointmonitorv1alpha1.Check{Method: "PUT"})
+	assert.Assert(t, providerConfig == nil)
+	assert.DeepEqual(t, unsupported, []string{"method: only GET, or POST with a body, is supported"})
+}
+
+func TestBuildSSLForm(t *testing.T) {
+	m := models.Monitor{
+		Name:   "ssl-test",
+		URL:    "https://example.com:8443/health",
+		Config: &endpointmonitorv1alpha1.StatusCakeConfig{ContactGroup: "123456,654321"},
+	}
+
+	form := buildSSLForm(m, "999", 28)
+	assert.Equal(t, form.Get("website_url"), "https://example.com:8443")
+	assert.Equal(t, form.Get("check_rate"), "86400")
+	assert.DeepEqual(t, form["alert_at[]"], []string{"1", "14", "28"})
+	assert.DeepEqual(t, form["contact_groups[]"], []string{"123456", "654321"})
+	assert.Equal(t, form.Get("alert_expiry"), "true")
+
+	form = buildSSLForm(models.Monitor{Name: "ssl-test", URL: "https://example.com/"}, "999", 1)
+	assert.Equal(t, form.Get("website_url"), "https://example.com")
+	assert.DeepEqual(t, form["alert_at[]"], []string{"1", "2", "3"})
+	assert.DeepEqual(t, form["contact_groups[]"], []string{"999"})
+}
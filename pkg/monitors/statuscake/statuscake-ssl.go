@@ -0,0 +1,196 @@
+package statuscake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	statuscake "github.com/StatusCakeDev/statuscake-go"
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+// sslCheckRate is the rate in seconds at which SSL tests are run, certificates are checked daily
+const sslCheckRate = 86400
+
+// EnsureCertificateMonitor creates or updates the SSL test alerting on the expiry of the certificate
+// served by the host of the monitor, and returns its ID. A test that no longer exists is created again.
+func (service *StatusCakeMonitorService) EnsureCertificateMonitor(m models.Monitor, id string, alertDaysBefore int) (string, error) {
+	data := buildSSLForm(m, service.cgroup, alertDaysBefore)
+
+	if len(id) > 0 {
+		existing, err := service.getSSLTest(id)
+		if err != nil {
+			return "", err
+		}
+		if existing != nil && existing.WebsiteURL != data.Get("website_url") {
+			// website_url can't be changed on update, the test is replaced instead
+			if err := service.RemoveCertificateMonitor(id); err != nil {
+				return "", err
+			}
+		} else if existing != nil {
+			if sslTestUpToDate(*existing, data) {
+				return id, nil
+			}
+			data.Del("website_url")
+			resp, err := service.sslRequest("PUT", "/v1/ssl/"+id, data)
+			if err != nil {
+				return "", err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusNoContent {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				return "", fmt.Errorf("update of SSL test %s failed with status code %d: %s", id, resp.StatusCode, string(bodyBytes))
+			}
+			log.Info("SSL test updated", "id", id, "name", m.Name)
+			return id, nil
+		}
+	}
+
+	resp, err := service.sslRequest("POST", "/v1/ssl", data)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("insert of SSL test for %s failed with status code %d: %s", m.Name, resp.StatusCode, string(bodyBytes))
+	}
+	var created statuscake.APIResponse
+	if err := json.Unmarshal(bodyBytes, &created); err != nil {
+		return "", err
+	}
+	log.Info("SSL test added", "id", created.Data.NewID, "name", m.Name)
+	return created.Data.NewID, nil
+}
+
+// RemoveCertificateMonitor deletes the SSL test, a test that no longer exists is ignored
+func (service *StatusCakeMonitorService) RemoveCertificateMonitor(id string) error {
+	resp, err := service.sslRequest("DELETE", "/v1/ssl/"+id, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("delete of SSL test %s failed with status code %d", id, resp.StatusCode)
+	}
+	log.Info("SSL test deleted", "id", id)
+	return nil
+}
+
+// getSSLTest fetches an SSL test, nil is returned when it doesn't exist
+func (service *StatusCakeMonitorService) getSSLTest(id string) (*statuscake.SSLTest, error) {
+	resp, err := service.sslRequest("GET", "/v1/ssl/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get of SSL test %s failed with status code %d: %s", id, resp.StatusCode, string(bodyBytes))
+	}
+	var data statuscake.SSLTestResponse
+	if err := json.Unmarshal(bodyBytes, &data); err != nil {
+		return nil, err
+	}
+	return &data.Data, nil
+}
+
+func (service *StatusCakeMonitorService) sslRequest(method string, path string, data url.Values) (*http.Response, error) {
+	u, err := url.Parse(service.url)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path
+	u.Scheme = "https"
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewBufferString(data.Encode())
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", service.apiKey))
+	if data != nil {
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return service.doRequest(req)
+}
+
+// buildSSLForm creates the form needed to add or update an SSL test. StatusCake takes three alert
+// days, the first alert is sent at alertDaysBefore followed by reminders closer to the expiry.
+func buildSSLForm(m models.Monitor, cgroup string, alertDaysBefore int) url.Values {
+	f := url.Values{}
+
+	host, port := m.Target()
+	websiteURL := "https://" + host
+	if port > 0 && port != 80 && port != 443 {
+		websiteURL = "https://" + net.JoinHostPort(host, strconv.Itoa(port))
+	}
+	f.Add("website_url", websiteURL)
+	f.Add("check_rate", strconv.Itoa(sslCheckRate))
+
+	for _, days := range sslAlertDays(alertDaysBefore) {
+		f.Add("alert_at[]", strconv.Itoa(days))
+	}
+	f.Add("alert_expiry", "true")
+	f.Add("alert_reminder", "true")
+	f.Add("alert_broken", "true")
+
+	providerConfig, _ := m.Config.(*endpointmonitorv1alpha1.StatusCakeConfig)
+	if providerConfig != nil && len(providerConfig.ContactGroup) > 0 {
+		for _, cg := range convertStringToArray(providerConfig.ContactGroup) {
+			f.Add("contact_groups[]", cg)
+		}
+	} else if cgroup != "" {
+		for _, cg := range convertStringToArray(cgroup) {
+			f.Add("contact_groups[]", cg)
+		}
+	}
+	return f
+}
+
+// sslAlertDays spreads the three alert days of an SSL test between alertDaysBefore and the day
+// before the expiry
+func sslAlertDays(alertDaysBefore int) []int {
+	if alertDaysBefore < 3 {
+		alertDaysBefore = 3
+	}
+	return []int{1, (alertDaysBefore + 1) / 2, alertDaysBefore}
+}
+
+// sslTestUpToDate returns whether the SSL test of the same website already alerts as described by the form
+func sslTestUpToDate(test statuscake.SSLTest, data url.Values) bool {
+	alertAt := []string{}
+	for _, days := range test.AlertAt {
+		alertAt = append(alertAt, strconv.Itoa(int(days)))
+	}
+	slices.Sort(alertAt)
+	wanted := slices.Clone(data["alert_at[]"])
+	slices.Sort(wanted)
+	if !slices.Equal(alertAt, wanted) {
+		return false
+	}
+	contactGroups := slices.Clone(test.ContactGroups)
+	slices.Sort(contactGroups)
+	wantedGroups := slices.Clone(data["contact_groups[]"])
+	slices.Sort(wantedGroups)
+	return slices.Equal(contactGroups, wantedGroups) && test.AlertExpiry
+}
@@ -0,0 +1,36 @@
+package updown
+
+import (
+	"slices"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// updownPeriods are the check intervals in seconds accepted by Updown
+var updownPeriods = []int{15, 30, 60, 120, 300, 600, 1800, 3600}
+
+// TranslateCheck translates a provider agnostic check into an Updown config, along with the fields
+// of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.UpdownConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.UpdownConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			if !slices.Contains(updownPeriods, check.Interval) {
+				unsupported = append(unsupported, "interval: must be one of 15, 30, 60, 120, 300, 600, 1800, 3600 seconds")
+				continue
+			}
+			providerConfig.Period = check.Interval
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
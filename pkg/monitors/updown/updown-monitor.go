@@ -35,7 +35,16 @@ func (updownService *UpdownMonitorService) Equal(oldMonitor models.Monitor, newM
 }
 
 // Setup method will initialize a updown's go client object by using the configuration parameters
-func (updownService *UpdownMonitorService) Setup(confProvider config.Provider) {
+// MonitorLinks links to the page of the check, which is public when publishPage is set
+func (updownService *UpdownMonitorService) MonitorLinks(m models.Monitor) (models.MonitorLinks, error) {
+	links := models.MonitorLinks{DashboardURL: "https://updown.io/" + m.ID}
+	if providerConfig, _ := m.Config.(*endpointmonitorv1alpha1.UpdownConfig); providerConfig != nil && providerConfig.PublishPage {
+		links.StatusPageURL = links.DashboardURL
+	}
+	return links, nil
+}
+
+func (updownService *UpdownMonitorService) Setup(confProvider config.Provider) error {
 
 	// initializeCustomLog(os.Stdout)
 	log.Info("Updown monitor's Setup has been called. Updown monitor initializing")
@@ -46,6 +55,13 @@ func (updownService *UpdownMonitorService) Setup(confProvider config.Provider) {
 	// creating updown go client
 	updownService.client = updown.NewClient(updownService.apiKey, http.DefaultClient)
 	log.Info("Updown monitor has been initialized")
+	return nil
+}
+
+// CheckConnection verifies the API key by listing the checks, which updown doesn't paginate
+func (updownService *UpdownMonitorService) CheckConnection() error {
+	_, err := updownService.GetAll()
+	return err
 }
 
 func (updownService *UpdownMonitorService) GetAll() ([]models.Monitor, error) {
@@ -183,6 +199,22 @@ func (updownService *UpdownMonitorService) Update(updownMonitor models.Monitor)
 	log.Info(fmt.Sprintf("Monitor %s has been updated with following parameters", updownMonitor.Name))
 }
 
+// Pause method will disable a monitor (updown check) without removing it
+func (updownService *UpdownMonitorService) Pause(updownMonitor models.Monitor) error {
+	httpCheckItemObj := updownService.createHttpCheck(updownMonitor)
+	httpCheckItemObj.Enabled = false
+	_, httpResponse, err := updownService.client.Check.Update(updownMonitor.ID, httpCheckItemObj)
+	if err != nil {
+		return fmt.Errorf("monitor %s is not paused: %w", updownMonitor.Name, err)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to pause monitor %s, status code is %s", updownMonitor.Name, httpResponse.Status)
+	}
+
+	log.Info(fmt.Sprintf("Monitor %s has been paused", updownMonitor.Name))
+	return nil
+}
+
 // Remove method will remove a monitor (updown check)
 func (updownService *UpdownMonitorService) Remove(updownMonitor models.Monitor) {
 
@@ -0,0 +1,31 @@
+package uptime
+
+import (
+	"strings"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// TranslateCheck translates a provider agnostic check into an Uptime config, along with the fields
+// of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.UptimeConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.UptimeConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			providerConfig.Interval = check.Interval
+		case "locations":
+			providerConfig.Locations = strings.Join(check.Locations, ",")
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
@@ -13,6 +13,17 @@ func UptimeMonitorMonitorToBaseMonitorMapper(uptimeMonitor UptimeMonitorMonitor)
 
 	m.Name = uptimeMonitor.Name
 	m.URL = uptimeMonitor.MspAddress
+	// TCP, ICMP and DNS checks keep the bare host as address
+	if !strings.Contains(uptimeMonitor.MspAddress, "://") {
+		switch uptimeMonitor.CheckType {
+		case "TCP":
+			m.URL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeTCP, uptimeMonitor.MspAddress, uptimeMonitor.MspPort)
+		case "ICMP":
+			m.URL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeICMP, uptimeMonitor.MspAddress, 0)
+		case "DNS":
+			m.URL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeDNS, uptimeMonitor.MspAddress, 0)
+		}
+	}
 	m.ID = strconv.Itoa(uptimeMonitor.PK)
 
 	var providerConfig endpointmonitorv1alpha1.UptimeConfig
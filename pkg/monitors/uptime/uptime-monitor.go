@@ -22,13 +22,14 @@ import (
 	"github.com/stakater/IngressMonitorController/v2/pkg/util"
 )
 
-var cache = gocache.New(5*time.Minute, 5*time.Minute)
 var log = logf.Log.WithName("uptime-monitor")
 
 type UpTimeMonitorService struct {
 	apiKey        string
 	url           string
 	alertContacts string
+	// cache holds the checks of this account only
+	cache *gocache.Cache
 }
 
 func (monitor *UpTimeMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
@@ -42,10 +43,36 @@ func (monitor *UpTimeMonitorService) Equal(oldMonitor models.Monitor, newMonitor
 	return true
 }
 
-func (monitor *UpTimeMonitorService) Setup(p config.Provider) {
+// SupportsCheckType returns whether Uptime can run the check type, TCP, ICMP and DNS checks are supported
+func (monitor *UpTimeMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType == endpointmonitorv1alpha1.CheckTypeTCP || checkType == endpointmonitorv1alpha1.CheckTypeICMP || checkType == endpointmonitorv1alpha1.CheckTypeDNS
+}
+
+// MonitorLinks links to the page of the check in the dashboard
+func (monitor *UpTimeMonitorService) MonitorLinks(m models.Monitor) (models.MonitorLinks, error) {
+	return models.MonitorLinks{DashboardURL: "https://uptime.com/devices/services/" + m.ID}, nil
+}
+
+func (monitor *UpTimeMonitorService) Setup(p config.Provider) error {
 	monitor.apiKey = p.ApiKey
 	monitor.url = p.ApiURL
 	monitor.alertContacts = p.AlertContacts
+	monitor.cache = gocache.New(5*time.Minute, 5*time.Minute)
+	return nil
+}
+
+// CheckConnection verifies the API key by listing a single check
+func (monitor *UpTimeMonitorService) CheckConnection() error {
+	headers := map[string]string{
+		"Authorization": "Token " + monitor.apiKey,
+		"Content-Type":  "application/json",
+	}
+	client := http.CreateHttpClient(monitor.url + "checks/?page_size=1")
+	response := client.GetUrl(headers, []byte(""))
+	if response.StatusCode != Http.StatusOK {
+		return fmt.Errorf("uptime API returned status %d", response.StatusCode)
+	}
+	return nil
 }
 
 func (monitor *UpTimeMonitorService) GetAll() ([]models.Monitor, error) {
@@ -57,7 +84,7 @@ func (monitor *UpTimeMonitorService) GetAll() ([]models.Monitor, error) {
 	val := "notNull"
 	next := &val
 
-	cached, found := cache.Get("uptime-checks")
+	cached, found := monitor.cache.Get("uptime-checks")
 	if found {
 		return UptimeMonitorMonitorsToBaseMonitorsMapper(cached.([]UptimeMonitorMonitor)), nil
 	}
@@ -78,7 +105,7 @@ func (monitor *UpTimeMonitorService) GetAll() ([]models.Monitor, error) {
 		pageNo++
 		next = f.Next
 	}
-	cache.Set("uptime-checks", monitors, gocache.DefaultExpiration)
+	monitor.cache.Set("uptime-checks", monitors, gocache.DefaultExpiration)
 	return UptimeMonitorMonitorsToBaseMonitorsMapper(monitors), nil
 }
 
@@ -97,8 +124,8 @@ func (monitor *UpTimeMonitorService) GetByName(name string) (*models.Monitor, er
 
 func (monitor *UpTimeMonitorService) Add(m models.Monitor) {
 
-	defer cache.Flush()
-	action := "checks/add-http/"
+	defer monitor.cache.Flush()
+	action := "checks/add-" + strings.ToLower(string(m.CheckType())) + "/"
 	client := http.CreateHttpClient(monitor.url + action)
 
 	headers := make(map[string]string)
@@ -140,7 +167,7 @@ func (monitor *UpTimeMonitorService) Add(m models.Monitor) {
 func (monitor *UpTimeMonitorService) Update(m models.Monitor) {
 
 	log.Info("Updating Monitor: " + m.Name)
-	defer cache.Flush()
+	defer monitor.cache.Flush()
 
 	action := "checks/" + m.ID + "/"
 	client := http.CreateHttpClient(monitor.url + action)
@@ -177,7 +204,7 @@ func (monitor *UpTimeMonitorService) Update(m models.Monitor) {
 
 func (monitor *UpTimeMonitorService) Remove(m models.Monitor) {
 
-	defer cache.Flush()
+	defer monitor.cache.Flush()
 	action := "checks/" + m.ID + "/"
 
 	client := http.CreateHttpClient(monitor.url + action)
@@ -211,8 +238,22 @@ func processProviderConfig(m models.Monitor) map[string]interface{} {
 
 	body := make(map[string]interface{})
 	body["name"] = m.Name
-	unEscapedURL, _ := url.QueryUnescape(m.URL)
-	body["msp_address"] = unEscapedURL
+	switch m.CheckType() {
+	case endpointmonitorv1alpha1.CheckTypeTCP:
+		host, port := m.Target()
+		body["msp_address"] = host
+		body["msp_port"] = port
+	case endpointmonitorv1alpha1.CheckTypeICMP:
+		host, _ := m.Target()
+		body["msp_address"] = host
+	case endpointmonitorv1alpha1.CheckTypeDNS:
+		host, _ := m.Target()
+		body["msp_address"] = host
+		body["msp_dns_record_type"] = "A"
+	default:
+		unEscapedURL, _ := url.QueryUnescape(m.URL)
+		body["msp_address"] = unEscapedURL
+	}
 
 	if providerConfig != nil && providerConfig.Interval > 0 {
 		body["msp_interval"] = strconv.Itoa(providerConfig.Interval)
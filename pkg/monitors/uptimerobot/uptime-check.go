@@ -0,0 +1,53 @@
+package uptimerobot
+
+import (
+	"strconv"
+	"strings"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+)
+
+// TranslateCheck translates a provider agnostic check into an UptimeRobot config, along with the
+// fields of the check it can't express
+func TranslateCheck(check *endpointmonitorv1alpha1.Check) (*endpointmonitorv1alpha1.UptimeRobotConfig, []string) {
+	providerConfig := &endpointmonitorv1alpha1.UptimeRobotConfig{}
+	translated := false
+	var unsupported []string
+	for _, field := range check.SetFields() {
+		switch field {
+		case "interval":
+			if check.Interval < 60 {
+				unsupported = append(unsupported, "interval: must be at least 60 seconds")
+				continue
+			}
+			providerConfig.Interval = check.Interval
+		case "expectedStatusCodes":
+			statuses := make([]string, 0, len(check.ExpectedStatusCodes))
+			for _, code := range check.ExpectedStatusCodes {
+				statuses = append(statuses, strconv.Itoa(code)+":1")
+			}
+			providerConfig.CustomHTTPStatuses = strings.Join(statuses, "_")
+		case "bodyAssertions":
+			if len(check.BodyAssertions) > 1 {
+				unsupported = append(unsupported, "bodyAssertions: only one assertion is supported")
+				continue
+			}
+			assertion := check.BodyAssertions[0]
+			providerConfig.MonitorType = "keyword"
+			providerConfig.KeywordValue = assertion.Value
+			// UptimeRobot alerts when the keyword exists (yes) or doesn't exist (no)
+			providerConfig.KeywordExists = "no"
+			if assertion.Type == endpointmonitorv1alpha1.BodyAssertionNotContains {
+				providerConfig.KeywordExists = "yes"
+			}
+		default:
+			unsupported = append(unsupported, field)
+			continue
+		}
+		translated = true
+	}
+	if !translated {
+		return nil, unsupported
+	}
+	return providerConfig, unsupported
+}
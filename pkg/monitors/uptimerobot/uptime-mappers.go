@@ -14,6 +14,20 @@ func UptimeMonitorMonitorToBaseMonitorMapper(uptimeMonitor UptimeMonitorMonitor)
 
 	m.Name = uptimeMonitor.FriendlyName
 	m.URL = uptimeMonitor.URL
+	switch uptimeMonitor.Type {
+	case 3:
+		m.URL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeICMP, uptimeMonitor.URL, 0)
+	case 4:
+		port, _ := strconv.Atoi(uptimeMonitor.Port)
+		m.URL = models.TargetURL(endpointmonitorv1alpha1.CheckTypeTCP, uptimeMonitor.URL, port)
+	case 5:
+		// The url of heartbeat monitors is the token of their ping URL
+		m.URL = uptimeMonitor.URL
+		if !strings.Contains(m.URL, "://") {
+			m.URL = HeartbeatURL + m.URL
+		}
+		m.Heartbeat = &models.Heartbeat{Period: uptimeMonitor.Interval}
+	}
 	m.ID = strconv.Itoa(uptimeMonitor.ID)
 
 	var providerConfig endpointmonitorv1alpha1.UptimeRobotConfig
@@ -49,6 +63,10 @@ func UptimeStatusPageToBaseStatusPageMapper(uptimePublicStatusPage UptimePublicS
 	s.Name = uptimePublicStatusPage.FriendlyName
 	s.Monitors = util.SliceItoa(uptimePublicStatusPage.Monitors)
 	s.ID = strconv.Itoa(uptimePublicStatusPage.ID)
+	s.URL = uptimePublicStatusPage.StandardURL
+	if len(uptimePublicStatusPage.CustomURL) > 0 {
+		s.URL = uptimePublicStatusPage.CustomURL
+	}
 
 	return &s
 }
@@ -31,6 +31,35 @@ func TestUptimeMonitorMonitorToBaseMonitorMapper(t *testing.T) {
 	}
 }
 
+func TestUptimeMonitorMonitorToBaseMonitorMapperForPortAndPingMonitors(t *testing.T) {
+	portMonitor := UptimeMonitorMonitorToBaseMonitorMapper(UptimeMonitorMonitor{FriendlyName: "Port Monitor", ID: 126, URL: "10.0.0.1", Type: 4, SubType: "99", Port: "5432"})
+	if portMonitor.URL != "tcp://10.0.0.1:5432" {
+		t.Errorf("Expected url tcp://10.0.0.1:5432 for port monitor, got %s", portMonitor.URL)
+	}
+
+	pingMonitor := UptimeMonitorMonitorToBaseMonitorMapper(UptimeMonitorMonitor{FriendlyName: "Ping Monitor", ID: 127, URL: "10.0.0.1", Type: 3})
+	if pingMonitor.URL != "icmp://10.0.0.1" {
+		t.Errorf("Expected url icmp://10.0.0.1 for ping monitor, got %s", pingMonitor.URL)
+	}
+}
+
+func TestUptimeMonitorMonitorToBaseMonitorMapperForHeartbeatMonitors(t *testing.T) {
+	heartbeatMonitor := UptimeMonitorMonitorToBaseMonitorMapper(UptimeMonitorMonitor{FriendlyName: "Heartbeat Monitor", ID: 128, URL: "m128-0a1b2c3d", Type: 5, Interval: 3900})
+	if heartbeatMonitor.URL != "https://heartbeat.uptimerobot.com/m128-0a1b2c3d" {
+		t.Errorf("Expected the ping url of the heartbeat monitor, got %s", heartbeatMonitor.URL)
+	}
+	if heartbeatMonitor.Heartbeat == nil || heartbeatMonitor.Heartbeat.Period != 3900 {
+		t.Error("Expected a heartbeat with the interval as period")
+	}
+
+	// The heartbeat read back equals the heartbeat it was created from
+	service := UpTimeMonitorService{apiKey: "key"}
+	desired := models.Monitor{Name: "Heartbeat Monitor", ID: "128", Heartbeat: &models.Heartbeat{Schedule: "0 * * * *", Period: 3600, Grace: 300}}
+	if !service.Equal(*heartbeatMonitor, desired) {
+		t.Error("Expected the heartbeat monitors to be equal")
+	}
+}
+
 func TestUptimeMonitorMonitorsToBaseMonitorsMapper(t *testing.T) {
 	uptimeMonitorObject1 := UptimeMonitorMonitor{FriendlyName: "Test Monitor 1", ID: 124, URL: "https://stakater.com", Interval: 900}
 	uptimeMonitorObject2 := UptimeMonitorMonitor{FriendlyName: "Test Monitor 2", ID: 125, URL: "https://stackator.com", Interval: 600}
@@ -73,3 +102,16 @@ func TestUptimeStatusPageToBaseStatusPageMapper(t *testing.T) {
 		t.Error("Mapper the monitors array correctly, expected: 1234-5678, but got: " + strings.Join(uptimeStatusPageObject.Monitors, "-"))
 	}
 }
+
+func TestUptimeStatusPageToBaseStatusPageMapperPrefersCustomURL(t *testing.T) {
+	uptimePublicStatusPageObject := UptimePublicStatusPage{ID: 124, StandardURL: "https://stats.uptimerobot.com/abc"}
+
+	if url := UptimeStatusPageToBaseStatusPageMapper(uptimePublicStatusPageObject).URL; url != "https://stats.uptimerobot.com/abc" {
+		t.Error("Mapper did not map the standard URL, got: " + url)
+	}
+
+	uptimePublicStatusPageObject.CustomURL = "https://status.example.com"
+	if url := UptimeStatusPageToBaseStatusPageMapper(uptimePublicStatusPageObject).URL; url != "https://status.example.com" {
+		t.Error("Mapper did not prefer the custom URL, got: " + url)
+	}
+}
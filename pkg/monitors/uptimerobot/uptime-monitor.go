@@ -27,6 +27,12 @@ type UpTimeMonitorService struct {
 // Default Interval for status checking
 const DefaultInterval = 300
 
+// HeartbeatURL is the base of the ping URL of heartbeat monitors
+const HeartbeatURL = "https://heartbeat.uptimerobot.com/"
+
+// DashboardURL is the base of the dashboard page of monitors
+const DashboardURL = "https://dashboard.uptimerobot.com/monitors/"
+
 const maxRateLimitRetries = 3
 
 func (monitor *UpTimeMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
@@ -37,12 +43,56 @@ func (monitor *UpTimeMonitorService) Equal(oldMonitor models.Monitor, newMonitor
 	return true
 }
 
-func (monitor *UpTimeMonitorService) Setup(p config.Provider) {
+// SupportsCheckType returns whether UptimeRobot can run the check type, port, ping and heartbeat monitors
+// are supported
+func (monitor *UpTimeMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType == endpointmonitorv1alpha1.CheckTypeTCP || checkType == endpointmonitorv1alpha1.CheckTypeICMP ||
+		checkType == endpointmonitorv1alpha1.CheckTypeHeartbeat
+}
+
+// MonitorLinks links to the dashboard page of the monitor and to the first of its statusPages
+func (monitor *UpTimeMonitorService) MonitorLinks(m models.Monitor) (models.MonitorLinks, error) {
+	links := models.MonitorLinks{DashboardURL: DashboardURL + m.ID}
+	providerConfig, _ := m.Config.(*endpointmonitorv1alpha1.UptimeRobotConfig)
+	if providerConfig == nil || len(providerConfig.StatusPages) == 0 {
+		return links, nil
+	}
+	statusPage, err := monitor.statusPageService.Get(strings.Split(providerConfig.StatusPages, "-")[0])
+	if err != nil {
+		return links, err
+	}
+	if statusPage != nil {
+		links.StatusPageURL = statusPage.URL
+	}
+	return links, nil
+}
+
+func (monitor *UpTimeMonitorService) Setup(p config.Provider) error {
 	monitor.apiKey = p.ApiKey
 	monitor.url = p.ApiURL
 	monitor.alertContacts = p.AlertContacts
 	monitor.statusPageService = UpTimeStatusPageService{}
 	monitor.statusPageService.Setup(p)
+	return nil
+}
+
+// CheckConnection verifies the API key by fetching the account details
+func (monitor *UpTimeMonitorService) CheckConnection() error {
+	client := http.CreateHttpClient(monitor.url + "getAccountDetails")
+
+	response := client.PostUrlEncodedFormBody("api_key=" + monitor.apiKey + "&format=json")
+	if response.StatusCode != Http.StatusOK {
+		return fmt.Errorf("getAccountDetails request failed with status code %d", response.StatusCode)
+	}
+
+	var f UptimeMonitorAccountDetailsResponse
+	if err := json.Unmarshal(response.Bytes, &f); err != nil {
+		return fmt.Errorf("unable to unmarshal account details response: %w", err)
+	}
+	if f.Stat != "ok" {
+		return fmt.Errorf("getAccountDetails request failed: %s", f.Error.Message)
+	}
+	return nil
 }
 
 func (monitor *UpTimeMonitorService) GetByName(name string) (*models.Monitor, error) {
@@ -227,11 +277,26 @@ func (monitor *UpTimeMonitorService) Update(m models.Monitor) {
 func (monitor *UpTimeMonitorService) processProviderConfig(m models.Monitor, createMonitorRequest bool) string {
 	var body string
 
+	// Port and ping monitors take the host as url, heartbeat monitors have none
+	checkType := m.CheckType()
+	monitorURL := m.URL
+	host, port := m.Target()
+	if checkType != endpointmonitorv1alpha1.CheckTypeHTTP {
+		monitorURL = host
+	}
+
 	// if createFunction is true, generate query for create else for update
 	if createMonitorRequest {
-		body = "api_key=" + monitor.apiKey + "&format=json&url=" + url.QueryEscape(m.URL) + "&friendly_name=" + url.QueryEscape(m.Name)
+		body = "api_key=" + monitor.apiKey + "&format=json&friendly_name=" + url.QueryEscape(m.Name)
 	} else {
-		body = "api_key=" + monitor.apiKey + "&format=json&id=" + m.ID + "&friendly_name=" + m.Name + "&url=" + m.URL
+		body = "api_key=" + monitor.apiKey + "&format=json&id=" + m.ID + "&friendly_name=" + m.Name
+	}
+	if checkType != endpointmonitorv1alpha1.CheckTypeHeartbeat {
+		if createMonitorRequest {
+			body += "&url=" + url.QueryEscape(monitorURL)
+		} else {
+			body += "&url=" + monitorURL
+		}
 	}
 
 	// Retrieve provider configuration
@@ -245,6 +310,9 @@ func (monitor *UpTimeMonitorService) processProviderConfig(m models.Monitor, cre
 
 	if providerConfig != nil && providerConfig.Interval > 0 {
 		body += "&interval=" + strconv.Itoa(providerConfig.Interval)
+	} else if m.Heartbeat != nil {
+		// Heartbeat monitors alert when no ping arrived within the interval
+		body += "&interval=" + strconv.Itoa(m.Heartbeat.Period+m.Heartbeat.Grace)
 	} else {
 		// Uptime robot adds a default interval of 5 minutes, if it is not specified
 		body += "&interval=" + strconv.Itoa(DefaultInterval)
@@ -258,7 +326,14 @@ func (monitor *UpTimeMonitorService) processProviderConfig(m models.Monitor, cre
 		body += "&custom_http_statuses=" + providerConfig.CustomHTTPStatuses
 	}
 
-	if providerConfig != nil && len(providerConfig.MonitorType) != 0 {
+	if checkType == endpointmonitorv1alpha1.CheckTypeHeartbeat {
+		body += "&type=5"
+	} else if checkType == endpointmonitorv1alpha1.CheckTypeTCP {
+		// Port monitor of the custom sub type
+		body += "&type=4&sub_type=99&port=" + strconv.Itoa(port)
+	} else if checkType == endpointmonitorv1alpha1.CheckTypeICMP {
+		body += "&type=3"
+	} else if providerConfig != nil && len(providerConfig.MonitorType) != 0 {
 		if strings.Contains(strings.ToLower(providerConfig.MonitorType), "http") {
 			body += "&type=1"
 		} else if strings.Contains(strings.ToLower(providerConfig.MonitorType), "keyword") {
@@ -315,6 +390,26 @@ func (monitor *UpTimeMonitorService) Remove(m models.Monitor) {
 	}
 }
 
+// Pause disables the checks of a monitor while keeping it and its history
+func (monitor *UpTimeMonitorService) Pause(m models.Monitor) error {
+	client := http.CreateHttpClient(monitor.url + "editMonitor")
+
+	response := client.PostUrlEncodedFormBody("api_key=" + monitor.apiKey + "&format=json&id=" + m.ID + "&status=0")
+	if response.StatusCode != Http.StatusOK {
+		return fmt.Errorf("editMonitor request failed with status code %d", response.StatusCode)
+	}
+
+	var f UptimeMonitorStatusMonitorResponse
+	if err := json.Unmarshal(response.Bytes, &f); err != nil {
+		return fmt.Errorf("unable to unmarshal editMonitor response: %w", err)
+	}
+	if f.Stat != "ok" {
+		return fmt.Errorf("monitor %s couldn't be paused: %s", m.Name, f.Error.Message)
+	}
+	log.Info("Monitor Paused: " + m.Name)
+	return nil
+}
+
 func (monitor *UpTimeMonitorService) handleStatusPagesConfig(monitorToAdd models.Monitor, monitorId string) {
 	// Retrieve provider configuration
 	providerConfig, _ := monitorToAdd.Config.(*endpointmonitorv1alpha1.UptimeRobotConfig)
@@ -1,7 +1,10 @@
 package uptimerobot
 
 import (
+	Http "net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -433,3 +436,53 @@ func TestAddMonitorWithIncorrectValues(t *testing.T) {
 // 	}
 // 	service.Remove(*mRes)
 // }
+
+func TestCheckConnection(t *testing.T) {
+	server := httptest.NewServer(Http.HandlerFunc(func(w Http.ResponseWriter, r *Http.Request) {
+		if r.URL.Path != "/getAccountDetails" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("api_key") == "valid" {
+			_, _ = w.Write([]byte(`{"stat":"ok","account":{"email":"ops@example.com"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"stat":"fail","error":{"type":"invalid_parameter","message":"api_key not found."}}`))
+	}))
+	defer server.Close()
+
+	service := UpTimeMonitorService{}
+	_ = service.Setup(config.Provider{Name: "UptimeRobot", ApiKey: "valid", ApiURL: server.URL + "/"})
+	if err := service.CheckConnection(); err != nil {
+		t.Errorf("Expected the connectivity check to succeed, got %v", err)
+	}
+
+	_ = service.Setup(config.Provider{Name: "UptimeRobot", ApiKey: "revoked", ApiURL: server.URL + "/"})
+	if err := service.CheckConnection(); err == nil || !strings.Contains(err.Error(), "api_key not found.") {
+		t.Errorf("Expected the connectivity check to fail, got %v", err)
+	}
+}
+
+func TestPause(t *testing.T) {
+	server := httptest.NewServer(Http.HandlerFunc(func(w Http.ResponseWriter, r *Http.Request) {
+		if r.URL.Path != "/editMonitor" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("id") != "42" || r.PostForm.Get("status") != "0" {
+			t.Errorf("Unexpected editMonitor request %v", r.PostForm)
+		}
+		_, _ = w.Write([]byte(`{"stat":"ok","monitor":{"id":42}}`))
+	}))
+	defer server.Close()
+
+	service := UpTimeMonitorService{}
+	_ = service.Setup(config.Provider{Name: "UptimeRobot", ApiKey: "valid", ApiURL: server.URL + "/"})
+	if err := service.Pause(models.Monitor{ID: "42", Name: "frontend-shop"}); err != nil {
+		t.Errorf("Expected the monitor to be paused, got %v", err)
+	}
+}
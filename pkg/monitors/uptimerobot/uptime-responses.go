@@ -48,6 +48,11 @@ type UptimeMonitorNewMonitorResponse struct {
 	Error   UptimeMonitorError         `json:"error"`
 }
 
+type UptimeMonitorAccountDetailsResponse struct {
+	Stat  string             `json:"stat"`
+	Error UptimeMonitorError `json:"error"`
+}
+
 type UptimeMonitorError struct {
 	Type    string `json:"type"`
 	Message string `json:"message"`
@@ -71,6 +76,8 @@ type UptimePublicStatusPage struct {
 	FriendlyName string `json:"friendly_name"`
 	Monitors     []int  `json:"monitors"`
 	CustomDomain string `json:"custom_domain"`
+	CustomURL    string `json:"custom_url"`
+	StandardURL  string `json:"standard_url"`
 	Password     string `json:"password"`
 	Sort         int    `json:"sort"`
 	Status       int    `json:"status"`
@@ -26,6 +26,8 @@ type UpTimeStatusPage struct {
 	ID       string
 	Name     string
 	Monitors []string
+	// URL of the status page, on its custom domain when it has one
+	URL string
 }
 
 func (statusPage *UpTimeStatusPageService) Setup(p config.Provider) {
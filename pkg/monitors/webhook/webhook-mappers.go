@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+// WebhookMonitor is the JSON representation of a monitor exchanged with the webhook
+type WebhookMonitor struct {
+	ID     string            `json:"id,omitempty"`
+	Name   string            `json:"name"`
+	URL    string            `json:"url"`
+	Config json.RawMessage   `json:"config,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// CheckType is HTTP, TCP, ICMP, DNS or GRPC, derived from the scheme of the url
+	CheckType string `json:"checkType,omitempty"`
+	// GRPC holds the options of GRPC checks
+	GRPC *endpointmonitorv1alpha1.GRPCCheck `json:"grpc,omitempty"`
+}
+
+// WebhookMonitorToBaseMonitorMapper function to map a webhook monitor to Monitor
+func WebhookMonitorToBaseMonitorMapper(webhookMonitor WebhookMonitor) *models.Monitor {
+	var m models.Monitor
+	m.Name = webhookMonitor.Name
+	m.URL = webhookMonitor.URL
+	m.ID = webhookMonitor.ID
+	m.Labels = webhookMonitor.Labels
+	m.GRPC = webhookMonitor.GRPC
+	if len(webhookMonitor.Config) > 0 && string(webhookMonitor.Config) != "null" {
+		m.Config = &runtime.RawExtension{Raw: webhookMonitor.Config}
+	}
+	return &m
+}
+
+// WebhookMonitorsToBaseMonitorsMapper function to map webhook monitors to Monitors
+func WebhookMonitorsToBaseMonitorsMapper(webhookMonitors []WebhookMonitor) []models.Monitor {
+	var monitors []models.Monitor
+	for _, webhookMonitor := range webhookMonitors {
+		monitors = append(monitors, *WebhookMonitorToBaseMonitorMapper(webhookMonitor))
+	}
+	return monitors
+}
+
+// BaseMonitorToWebhookMonitorMapper function to map Monitor to a webhook monitor.
+// The monitor config is sent as a JSON object whatever its Go type is.
+func BaseMonitorToWebhookMonitorMapper(m models.Monitor) (WebhookMonitor, error) {
+	webhookMonitor := WebhookMonitor{
+		ID:        m.ID,
+		Name:      m.Name,
+		URL:       m.URL,
+		Labels:    m.Labels,
+		CheckType: string(m.CheckType()),
+		GRPC:      m.GRPC,
+	}
+
+	switch config := m.Config.(type) {
+	case nil:
+	case *runtime.RawExtension:
+		if config != nil && len(config.Raw) > 0 {
+			webhookMonitor.Config = config.Raw
+		}
+	default:
+		raw, err := json.Marshal(config)
+		if err != nil {
+			return webhookMonitor, err
+		}
+		if string(raw) != "null" {
+			webhookMonitor.Config = raw
+		}
+	}
+	return webhookMonitor, nil
+}
@@ -0,0 +1,263 @@
+// Package webhook adds a generic provider that forwards monitors to user-defined HTTP endpoints
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	endpointmonitorv1alpha1 "github.com/stakater/IngressMonitorController/v2/api/v1alpha1"
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+var log = logf.Log.WithName("webhook-monitor")
+
+const (
+	// Default timeout in seconds for a single request
+	TimeoutDefaultValue = 30
+
+	// SignatureHeader carries the HMAC-SHA256 signature of "<timestamp>.<body>"
+	SignatureHeader = "X-IMC-Signature"
+	// TimestampHeader carries the unix timestamp used to compute the signature
+	TimestampHeader = "X-IMC-Timestamp"
+
+	idPlaceholder = "{id}"
+)
+
+// WebhookMonitorService sends monitors as JSON to user-defined HTTP endpoints
+type WebhookMonitorService struct {
+	createURL string
+	updateURL string
+	deleteURL string
+	listURL   string
+	secret    string
+	headers   map[string]string
+	client    *http.Client
+}
+
+// Setup function is used to initialise the webhook service
+func (service *WebhookMonitorService) Setup(p config.Provider) error {
+	webhookConfig := p.WebhookConfig
+	baseURL := strings.TrimSuffix(webhookConfig.URL, "/")
+
+	service.createURL = firstNonEmpty(webhookConfig.CreateURL, baseURL)
+	service.listURL = firstNonEmpty(webhookConfig.ListURL, baseURL)
+	service.updateURL = firstNonEmpty(webhookConfig.UpdateURL, withID(baseURL))
+	service.deleteURL = firstNonEmpty(webhookConfig.DeleteURL, withID(baseURL))
+	service.secret = webhookConfig.Secret
+	service.headers = webhookConfig.Headers
+
+	timeout := TimeoutDefaultValue
+	if webhookConfig.Timeout > 0 {
+		timeout = webhookConfig.Timeout
+	}
+	service.client = &http.Client{Timeout: time.Duration(timeout) * time.Second}
+
+	if len(service.createURL) == 0 || len(service.listURL) == 0 {
+		return fmt.Errorf("webhook url is required for provider %s", p.Name)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+func withID(baseURL string) string {
+	if len(baseURL) == 0 {
+		return ""
+	}
+	return baseURL + "/" + idPlaceholder
+}
+
+// GetAll fetches all monitors from the list endpoint
+func (service *WebhookMonitorService) GetAll() ([]models.Monitor, error) {
+	body, err := service.doRequest(http.MethodGet, service.listURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("webhook list request failed: %w", err)
+	}
+
+	var webhookMonitors []WebhookMonitor
+	if err := json.Unmarshal(body, &webhookMonitors); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal webhook list response: %w", err)
+	}
+	return WebhookMonitorsToBaseMonitorsMapper(webhookMonitors), nil
+}
+
+// GetByName function will Get a monitor by it's name
+func (service *WebhookMonitorService) GetByName(name string) (*models.Monitor, error) {
+	monitors, err := service.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, monitor := range monitors {
+		if monitor.Name == name {
+			return &monitor, nil
+		}
+	}
+	return nil, nil
+}
+
+// Add will create a new Monitor
+func (service *WebhookMonitorService) Add(m models.Monitor) {
+	if err := service.send(http.MethodPost, service.createURL, m); err != nil {
+		log.Error(err, "Monitor couldn't be added: "+m.Name)
+		return
+	}
+	log.Info("Monitor Added: " + m.Name)
+}
+
+// Update will update an existing Monitor
+func (service *WebhookMonitorService) Update(m models.Monitor) {
+	if err := service.send(http.MethodPut, service.urlForMonitor(service.updateURL, m), m); err != nil {
+		log.Error(err, "Monitor couldn't be updated: "+m.Name)
+		return
+	}
+	log.Info("Monitor Updated: " + m.Name)
+}
+
+// Remove will delete an existing Monitor
+func (service *WebhookMonitorService) Remove(m models.Monitor) {
+	if err := service.send(http.MethodDelete, service.urlForMonitor(service.deleteURL, m), m); err != nil {
+		log.Error(err, "Monitor couldn't be removed: "+m.Name)
+		return
+	}
+	log.Info("Monitor Removed: " + m.Name)
+}
+
+// SupportsCheckType returns whether the webhook can run the check type, the receiving service decides
+// which check types it runs so all are passed on except heartbeats, which need a provider to receive the pings
+func (service *WebhookMonitorService) SupportsCheckType(checkType endpointmonitorv1alpha1.CheckType) bool {
+	return checkType != endpointmonitorv1alpha1.CheckTypeHeartbeat
+}
+
+// Equal compares the name, url, gRPC options, labels and the JSON form of the config of both monitors
+func (service *WebhookMonitorService) Equal(oldMonitor models.Monitor, newMonitor models.Monitor) bool {
+	oldWebhookMonitor, err := BaseMonitorToWebhookMonitorMapper(oldMonitor)
+	if err != nil {
+		log.Error(err, "Unable to convert monitor "+oldMonitor.Name)
+		return false
+	}
+	newWebhookMonitor, err := BaseMonitorToWebhookMonitorMapper(newMonitor)
+	if err != nil {
+		log.Error(err, "Unable to convert monitor "+newMonitor.Name)
+		return false
+	}
+
+	if oldWebhookMonitor.Name != newWebhookMonitor.Name || oldWebhookMonitor.URL != newWebhookMonitor.URL {
+		return false
+	}
+	if !reflect.DeepEqual(oldWebhookMonitor.GRPC, newWebhookMonitor.GRPC) {
+		return false
+	}
+	if len(oldWebhookMonitor.Labels) != 0 || len(newWebhookMonitor.Labels) != 0 {
+		if !reflect.DeepEqual(oldWebhookMonitor.Labels, newWebhookMonitor.Labels) {
+			return false
+		}
+	}
+	return jsonEqual(oldWebhookMonitor.Config, newWebhookMonitor.Config)
+}
+
+// jsonEqual compares two JSON documents semantically, ignoring key order and formatting
+func jsonEqual(a, b json.RawMessage) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var aValue, bValue interface{}
+	if err := json.Unmarshal(a, &aValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &bValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+func (service *WebhookMonitorService) urlForMonitor(rawURL string, m models.Monitor) string {
+	return strings.ReplaceAll(rawURL, idPlaceholder, url.PathEscape(m.ID))
+}
+
+func (service *WebhookMonitorService) send(method string, rawURL string, m models.Monitor) error {
+	webhookMonitor, err := BaseMonitorToWebhookMonitorMapper(m)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(webhookMonitor)
+	if err != nil {
+		return err
+	}
+	_, err = service.doRequest(method, rawURL, payload)
+	return err
+}
+
+// doRequest sends a signed request. Failed requests aren't retried here, as a create isn't idempotent and
+// waiting would hold up the reconcile, the next reconcile of the EndpointMonitor sends them again.
+func (service *WebhookMonitorService) doRequest(method string, rawURL string, payload []byte) ([]byte, error) {
+	if service.client == nil {
+		return nil, fmt.Errorf("webhook provider is not set up")
+	}
+	if len(rawURL) == 0 {
+		return nil, fmt.Errorf("no webhook url configured for %s requests", method)
+	}
+
+	req, err := http.NewRequest(method, rawURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range service.headers {
+		req.Header.Set(key, value)
+	}
+	if len(service.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(service.secret, timestamp, payload))
+	}
+
+	resp, err := service.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, nil
+	}
+	return nil, fmt.Errorf("%s %s returned HTTP %d: %s", method, rawURL, resp.StatusCode, string(body))
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "<timestamp>.<payload>" using secret as key.
+// Receivers can recompute it to verify that a request was sent by the controller.
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
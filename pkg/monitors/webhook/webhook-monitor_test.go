@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/stakater/IngressMonitorController/v2/pkg/config"
+	"github.com/stakater/IngressMonitorController/v2/pkg/models"
+)
+
+func init() {
+	// To allow normal logging to be printed if tests fails
+	// Dev mode is an extra feature to make output more readable
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+}
+
+const testSecret = "s3cr3t"
+
+// fakeReceiver is an in-memory webhook receiver that verifies request signatures
+type fakeReceiver struct {
+	mu       sync.Mutex
+	monitors map[string]WebhookMonitor
+	failures int
+	requests []string
+}
+
+func (f *fakeReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	if f.failures > 0 {
+		f.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	expected := "sha256=" + Sign(testSecret, r.Header.Get(TimestampHeader), body)
+	if r.Header.Get(SignatureHeader) != expected {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/monitors/")
+	switch r.Method {
+	case http.MethodGet:
+		var list []WebhookMonitor
+		for _, m := range f.monitors {
+			list = append(list, m)
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case http.MethodPost:
+		var m WebhookMonitor
+		_ = json.Unmarshal(body, &m)
+		m.ID = m.Name
+		f.monitors[m.ID] = m
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodPut:
+		var m WebhookMonitor
+		_ = json.Unmarshal(body, &m)
+		f.monitors[id] = m
+	case http.MethodDelete:
+		delete(f.monitors, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func setupTestService(t *testing.T, receiver *fakeReceiver) *WebhookMonitorService {
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	service := &WebhookMonitorService{}
+	service.Setup(config.Provider{
+		Name: "Webhook",
+		WebhookConfig: config.Webhook{
+			URL:    server.URL + "/monitors/",
+			Secret: testSecret,
+		},
+	})
+	return service
+}
+
+func TestWebhookMonitorLifecycle(t *testing.T) {
+	receiver := &fakeReceiver{monitors: map[string]WebhookMonitor{}}
+	service := setupTestService(t, receiver)
+
+	config := &runtime.RawExtension{Raw: []byte(`{"queue":"ops","priority":2}`)}
+	labels := map[string]string{"team": "checkout"}
+	service.Add(models.Monitor{Name: "checkout-api", URL: "https://checkout.example.com", Config: config, Labels: labels})
+
+	monitor, err := service.GetByName("checkout-api")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor != nil)
+	assert.Equal(t, monitor.ID, "checkout-api")
+	assert.Equal(t, monitor.Labels["team"], "checkout")
+
+	// Key order and whitespace of the config must not matter
+	desired := models.Monitor{Name: monitor.Name, ID: monitor.ID, URL: monitor.URL, Labels: labels,
+		Config: &runtime.RawExtension{Raw: []byte(`{ "priority": 2, "queue": "ops" }`)}}
+	assert.Assert(t, service.Equal(*monitor, desired))
+
+	desired.Labels = map[string]string{"team": "payments"}
+	assert.Assert(t, !service.Equal(*monitor, desired))
+	service.Update(desired)
+
+	monitor, err = service.GetByName("checkout-api")
+	assert.NilError(t, err)
+	assert.Equal(t, monitor.Labels["team"], "payments")
+
+	service.Remove(*monitor)
+	monitor, err = service.GetByName("checkout-api")
+	assert.NilError(t, err)
+	assert.Assert(t, monitor == nil)
+
+	assert.DeepEqual(t, receiver.requests, []string{
+		"POST /monitors",
+		"GET /monitors",
+		"PUT /monitors/checkout-api",
+		"GET /monitors",
+		"DELETE /monitors/checkout-api",
+		"GET /monitors",
+	})
+}
+
+func TestWebhookMonitorDoesNotRetry(t *testing.T) {
+	receiver := &fakeReceiver{monitors: map[string]WebhookMonitor{}, failures: 2}
+	service := setupTestService(t, receiver)
+
+	_, err := service.GetAll()
+	assert.ErrorContains(t, err, "503")
+
+	// A create that may have reached the receiver isn't sent twice, the next reconcile checks for it first
+	service.Add(models.Monitor{Name: "checkout-api", URL: "https://checkout.example.com"})
+	assert.DeepEqual(t, receiver.requests, []string{"GET /monitors", "POST /monitors"})
+	assert.Equal(t, len(receiver.monitors), 0)
+}
+
+func TestWebhookMonitorRejectsWrongSignature(t *testing.T) {
+	receiver := &fakeReceiver{monitors: map[string]WebhookMonitor{}}
+	service := setupTestService(t, receiver)
+	service.secret = "wrong"
+
+	_, err := service.GetAll()
+	assert.ErrorContains(t, err, "401")
+	assert.Equal(t, len(receiver.requests), 1)
+}
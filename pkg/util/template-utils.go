@@ -2,33 +2,89 @@ package util
 
 import (
 	"bytes"
-	"html/template"
+	"strings"
+	"text/template"
+	"unicode"
 )
 
 const (
-	defaultNameTemplate = "{{.Name}}-{{.Namespace}}"
+	DefaultNameTemplate = "{{.Name}}-{{.Namespace}}"
 )
 
+// MonitorNameTemplateParts holds the values available to the monitor name template
 type MonitorNameTemplateParts struct {
-	Name      string
-	Namespace string
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+	// Host and Path of the resolved monitor url
+	Host string
+	Path string
+	// Provider is the type of the provider the monitor is created in
+	Provider string
+	// ClusterName is the cluster name configured for the controller
+	ClusterName string
 }
 
-func GetNameTemplateFormat(nameTemplate string) (string, error) {
+var nameTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trunc": func(length int, s string) string {
+		runes := []rune(s)
+		if length >= 0 && len(runes) > length {
+			return string(runes[:length])
+		}
+		return s
+	},
+	"replace": func(old, new, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+	"default": func(defaultValue, value string) string {
+		if len(value) == 0 {
+			return defaultValue
+		}
+		return value
+	},
+}
+
+// ParseNameTemplate parses a monitor name template, falling back to the default template when empty
+func ParseNameTemplate(nameTemplate string) (*template.Template, error) {
 	if nameTemplate == "" {
-		nameTemplate = defaultNameTemplate
+		nameTemplate = DefaultNameTemplate
 	}
-	placeholders := MonitorNameTemplateParts{"%[1]s", "%[2]s"}
+	// Missing labels and annotations render as empty strings instead of "<no value>"
+	return template.New("monitorName").Funcs(nameTemplateFuncs).Option("missingkey=zero").Parse(nameTemplate)
+}
 
-	tmpl, err := template.New("format").Parse(nameTemplate)
+// RenderMonitorName renders the monitor name template against parts
+func RenderMonitorName(nameTemplate string, parts MonitorNameTemplateParts) (string, error) {
+	tmpl, err := ParseNameTemplate(nameTemplate)
 	if err != nil {
 		return "", err
 	}
 
 	var buffer bytes.Buffer
-	err = tmpl.Execute(&buffer, placeholders)
+	err = tmpl.Execute(&buffer, parts)
 	if err != nil {
 		return "", err
 	}
 	return buffer.String(), nil
 }
+
+// SanitizeMonitorName replaces control characters, collapses whitespace and truncates the
+// name to maxLength characters. A maxLength of 0 or less means the name is not truncated.
+func SanitizeMonitorName(name string, maxLength int) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Join(strings.Fields(name), " ")
+
+	runes := []rune(name)
+	if maxLength > 0 && len(runes) > maxLength {
+		name = strings.TrimSpace(string(runes[:maxLength]))
+	}
+	return name
+}
@@ -0,0 +1,70 @@
+package util
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestRenderMonitorName(t *testing.T) {
+	parts := MonitorNameTemplateParts{
+		Name:        "frontend",
+		Namespace:   "shop",
+		Labels:      map[string]string{"team": "Checkout"},
+		Annotations: map[string]string{"monitor/alias": "store front"},
+		Host:        "shop.example.com",
+		Path:        "/healthz",
+		Provider:    "UptimeRobot",
+		ClusterName: "prod-eu",
+	}
+
+	var tests = []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "default template", template: "", want: "frontend-shop"},
+		{name: "no html escaping", template: "{{.Name}} & <{{.Namespace}}>", want: "frontend & <shop>"},
+		{name: "labels and annotations", template: `{{.Labels.team | lower}}/{{index .Annotations "monitor/alias"}}`, want: "checkout/store front"},
+		{name: "host, path and cluster", template: "[{{.ClusterName}}] {{.Host}}{{.Path}}", want: "[prod-eu] shop.example.com/healthz"},
+		{name: "missing label with default", template: `{{.Labels.owner | default "unowned"}}`, want: "unowned"},
+		{name: "trunc and replace", template: `{{.Host | replace "." "-" | trunc 7}}`, want: "shop-ex"},
+		{name: "upper provider", template: "{{.Provider | upper}}", want: "UPTIMEROBOT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMonitorName(tt.template, parts)
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestRenderMonitorNameWithInvalidTemplate(t *testing.T) {
+	_, err := RenderMonitorName("{{.Name", MonitorNameTemplateParts{})
+	assert.Assert(t, err != nil)
+
+	_, err = RenderMonitorName("{{.Unknown}}", MonitorNameTemplateParts{})
+	assert.Assert(t, err != nil)
+}
+
+func TestSanitizeMonitorName(t *testing.T) {
+	var tests = []struct {
+		name      string
+		input     string
+		maxLength int
+		want      string
+	}{
+		{name: "unchanged", input: "frontend-shop", want: "frontend-shop"},
+		{name: "control characters", input: "frontend\n\tshop\x00", want: "frontend shop"},
+		{name: "collapse whitespace", input: "  frontend   shop ", want: "frontend shop"},
+		{name: "truncate", input: "frontend-shop", maxLength: 8, want: "frontend"},
+		{name: "truncate multibyte", input: "ünïcödé", maxLength: 3, want: "ünï"},
+		{name: "no trailing space after truncate", input: "front end", maxLength: 6, want: "front"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, SanitizeMonitorName(tt.input, tt.maxLength), tt.want)
+		})
+	}
+}